@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// AnnounceHostIP makes every pod announce the IP of the node it runs on
+	AnnounceHostIP = "$(HOST_IP)"
+	// AnnouncePodIP makes every pod announce its own IP
+	AnnouncePodIP = "$(POD_IP)"
+)
+
+// RedisAnnouncePort returns the port the redis pods are announced and reached by
+func (r *RedisFailover) RedisAnnouncePort() int32 {
+	if r.Spec.Redis.AnnouncePort > 0 {
+		return r.Spec.Redis.AnnouncePort
+	}
+	return r.Spec.Redis.Port
+}
+
+// validateAnnounce checks the address announced by the pods of a component with the given number of replicas.
+// Every pod must announce its own address, so a fixed one is only valid for a single pod.
+func validateAnnounce(ip string, port int32, replicas int32) error {
+	if port < 0 {
+		return errors.New("announcePort can't be negative")
+	}
+	switch {
+	case ip == "", ip == AnnounceHostIP, ip == AnnouncePodIP:
+	case strings.Contains(ip, "$("):
+		return fmt.Errorf("announceIP %q can only reference %s or %s", ip, AnnounceHostIP, AnnouncePodIP)
+	case replicas > 1:
+		return fmt.Errorf("announceIP %q would be announced by all the %d pods, it must reference %s or %s", ip, replicas, AnnounceHostIP, AnnouncePodIP)
+	}
+	return nil
+}
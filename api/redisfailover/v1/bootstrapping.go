@@ -10,3 +10,19 @@ func (r *RedisFailover) SentinelsAllowed() bool {
 	bootstrapping := r.Bootstrapping()
 	return !bootstrapping || (bootstrapping && r.Spec.BootstrapNode.AllowSentinels)
 }
+
+// SharedSentinels returns true when the redis are monitored by an existing sentinel group or by the
+// sentinels of another RedisFailover, instead of the sentinels deployed for this one
+func (r *RedisFailover) SharedSentinels() bool {
+	return r.SentinelsAllowed() && (r.Spec.Sentinel.SharedGroup != nil || r.Spec.Sentinel.PoolRef != nil)
+}
+
+// PooledSentinels returns true when the redis are monitored by the sentinels of another RedisFailover
+func (r *RedisFailover) PooledSentinels() bool {
+	return r.SentinelsAllowed() && r.Spec.Sentinel.PoolRef != nil
+}
+
+// DeploySentinels returns true when the operator deploys the sentinels of the RedisFailover
+func (r *RedisFailover) DeploySentinels() bool {
+	return r.SentinelsAllowed() && !r.SharedSentinels()
+}
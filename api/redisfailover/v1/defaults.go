@@ -7,6 +7,10 @@ const (
 	defaultExporterImage         = "quay.io/oliver006/redis_exporter:v1.43.0"
 	defaultImage                 = "redis:6.2.6-alpine"
 	defaultRedisPort             = 6379
+	defaultSentinelMasterName    = "mymaster"
+
+	defaultMonitoringReplicationLagBytes = 10 << 20
+	defaultMonitoringMemoryUsagePercent  = 90
 )
 
 var (
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+)
+
+const maxMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// InMaintenanceWindow returns true when the disruptive operations can be done at the given time, that is
+// when a maintenance window is open or when there are no maintenance windows
+func (r *RedisFailover) InMaintenanceWindow(now time.Time) bool {
+	if len(r.Spec.MaintenanceWindows) == 0 {
+		return true
+	}
+	for _, window := range r.Spec.MaintenanceWindows {
+		if open, err := window.Open(now); err == nil && open {
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns true when the window is open at the given time. A window opened on one of its days stays
+// open for its whole duration, even past midnight.
+func (w MaintenanceWindow) Open(now time.Time) (bool, error) {
+	location, err := w.location()
+	if err != nil {
+		return false, err
+	}
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false, fmt.Errorf("invalid start %q, it must be in HH:MM format", w.Start)
+	}
+	days := map[time.Weekday]bool{}
+	for _, day := range w.Days {
+		weekday, ok := weekdays[day]
+		if !ok {
+			return false, fmt.Errorf("invalid day %q, it must be one of Mon, Tue, Wed, Thu, Fri, Sat or Sun", day)
+		}
+		days[weekday] = true
+	}
+
+	now = now.In(location)
+	// The window may have been opened on any of the previous days its duration reaches
+	for ago := 0; ago <= int(w.Duration.Duration/(24*time.Hour))+1; ago++ {
+		day := now.AddDate(0, 0, -ago)
+		if len(days) > 0 && !days[day.Weekday()] {
+			continue
+		}
+		opened := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, location)
+		if !now.Before(opened) && now.Before(opened.Add(w.Duration.Duration)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (w MaintenanceWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+	}
+	return location, nil
+}
+
+func (w MaintenanceWindow) validate() error {
+	if w.Duration.Duration <= 0 || w.Duration.Duration > maxMaintenanceWindowDuration {
+		return fmt.Errorf("duration must be positive and up to %s", maxMaintenanceWindowDuration)
+	}
+	_, err := w.Open(time.Now())
+	return err
+}
+
+// SetPendingOperation sets the operation as pending with the given message, or removes it from the pending
+// operations when the message is empty. It returns true when the pending operations changed.
+func (s *RedisFailoverStatus) SetPendingOperation(operation PendingOperationType, message string) bool {
+	for i, pending := range s.PendingOperations {
+		if pending.Type != operation {
+			continue
+		}
+		if message == "" {
+			s.PendingOperations = append(s.PendingOperations[:i:i], s.PendingOperations[i+1:]...)
+			if len(s.PendingOperations) == 0 {
+				s.PendingOperations = nil
+			}
+			return true
+		}
+		if pending.Message == message {
+			return false
+		}
+		s.PendingOperations[i].Message = message
+		return true
+	}
+	if message == "" {
+		return false
+	}
+	s.PendingOperations = append(s.PendingOperations, PendingOperation{Type: operation, Message: message})
+	return true
+}
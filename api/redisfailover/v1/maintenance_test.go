@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	// Saturday
+	saturday := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		window      MaintenanceWindow
+		now         time.Time
+		expectation bool
+	}{
+		{
+			name:        "every day within the window",
+			window:      MaintenanceWindow{Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			now:         saturday.Add(3 * time.Hour),
+			expectation: true,
+		},
+		{
+			name:        "every day before the window",
+			window:      MaintenanceWindow{Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			now:         saturday.Add(time.Hour),
+			expectation: false,
+		},
+		{
+			name:        "every day when the window closes",
+			window:      MaintenanceWindow{Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			now:         saturday.Add(4 * time.Hour),
+			expectation: false,
+		},
+		{
+			name:        "on another day",
+			window:      MaintenanceWindow{Days: []string{"Sun"}, Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			now:         saturday.Add(3 * time.Hour),
+			expectation: false,
+		},
+		{
+			name:        "opened the day before, past midnight",
+			window:      MaintenanceWindow{Days: []string{"Fri"}, Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			now:         saturday.Add(time.Hour),
+			expectation: true,
+		},
+		{
+			name:        "in another timezone",
+			window:      MaintenanceWindow{Days: []string{"Sat"}, Start: "02:00", Duration: metav1.Duration{Duration: time.Hour}, Timezone: "Europe/Madrid"},
+			now:         saturday.Add(30 * time.Minute),
+			expectation: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			open, err := test.window.Open(test.now)
+			assert.NoError(err)
+			assert.Equal(test.expectation, open)
+		})
+	}
+}
+
+func TestInMaintenanceWindow(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2023, 7, 1, 3, 0, 0, 0, time.UTC)
+
+	rf := generateRedisFailover("test", nil)
+	assert.True(rf.InMaintenanceWindow(now), "without windows the operations are done at any time")
+
+	rf.Spec.MaintenanceWindows = []MaintenanceWindow{
+		{Start: "05:00", Duration: metav1.Duration{Duration: time.Hour}},
+	}
+	assert.False(rf.InMaintenanceWindow(now))
+
+	rf.Spec.MaintenanceWindows = append(rf.Spec.MaintenanceWindows, MaintenanceWindow{Start: "02:30", Duration: metav1.Duration{Duration: time.Hour}})
+	assert.True(rf.InMaintenanceWindow(now))
+}
+
+func TestValidateMaintenanceWindows(t *testing.T) {
+	tests := []struct {
+		name          string
+		window        MaintenanceWindow
+		expectedError string
+	}{
+		{
+			name:   "valid window",
+			window: MaintenanceWindow{Days: []string{"Sat", "Sun"}, Start: "02:00", Duration: metav1.Duration{Duration: time.Hour}, Timezone: "Europe/Madrid"},
+		},
+		{
+			name:          "invalid day",
+			window:        MaintenanceWindow{Days: []string{"Saturday"}, Start: "02:00", Duration: metav1.Duration{Duration: time.Hour}},
+			expectedError: `maintenanceWindows[0]: invalid day "Saturday", it must be one of Mon, Tue, Wed, Thu, Fri, Sat or Sun`,
+		},
+		{
+			name:          "invalid start",
+			window:        MaintenanceWindow{Start: "2am", Duration: metav1.Duration{Duration: time.Hour}},
+			expectedError: `maintenanceWindows[0]: invalid start "2am", it must be in HH:MM format`,
+		},
+		{
+			name:          "without duration",
+			window:        MaintenanceWindow{Start: "02:00"},
+			expectedError: "maintenanceWindows[0]: duration must be positive and up to 168h0m0s",
+		},
+		{
+			name:          "invalid timezone",
+			window:        MaintenanceWindow{Start: "02:00", Duration: metav1.Duration{Duration: time.Hour}, Timezone: "Mars/Olympus"},
+			expectedError: `maintenanceWindows[0]: invalid timezone "Mars/Olympus": unknown time zone Mars/Olympus`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.MaintenanceWindows = []MaintenanceWindow{test.window}
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestSetPendingOperation(t *testing.T) {
+	assert := assert.New(t)
+	status := &RedisFailoverStatus{}
+
+	assert.False(status.SetPendingOperation(PendingOperationRedisPodsUpdate, ""))
+	assert.True(status.SetPendingOperation(PendingOperationRedisPodsUpdate, "restart"))
+	assert.False(status.SetPendingOperation(PendingOperationRedisPodsUpdate, "restart"))
+	assert.True(status.SetPendingOperation(PendingOperationStorageExpansion, "expansion"))
+	assert.True(status.SetPendingOperation(PendingOperationRedisPodsUpdate, "restart again"))
+	assert.Equal([]PendingOperation{
+		{Type: PendingOperationRedisPodsUpdate, Message: "restart again"},
+		{Type: PendingOperationStorageExpansion, Message: "expansion"},
+	}, status.PendingOperations)
+
+	assert.True(status.SetPendingOperation(PendingOperationRedisPodsUpdate, ""))
+	assert.True(status.SetPendingOperation(PendingOperationStorageExpansion, ""))
+	assert.Nil(status.PendingOperations)
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// validate checks the monitoring settings, setting their defaults when monitoring is enabled
+func (m *MonitoringSettings) validate() error {
+	if !m.Enabled {
+		return nil
+	}
+
+	switch m.MonitorKind {
+	case "":
+		m.MonitorKind = MonitorKindServiceMonitor
+	case MonitorKindServiceMonitor, MonitorKindPodMonitor:
+	default:
+		return fmt.Errorf("unsupported monitorKind %q", m.MonitorKind)
+	}
+
+	if m.Interval != "" {
+		if interval, err := time.ParseDuration(m.Interval); err != nil || interval <= 0 {
+			return fmt.Errorf("invalid interval %q, it must be a positive duration like 30s", m.Interval)
+		}
+	}
+
+	switch {
+	case m.Rules.ReplicationLagBytes < 0:
+		return errors.New("rules replicationLagBytes can't be negative")
+	case m.Rules.ReplicationLagBytes == 0:
+		m.Rules.ReplicationLagBytes = defaultMonitoringReplicationLagBytes
+	}
+
+	switch {
+	case m.Rules.MemoryUsagePercent < 0 || m.Rules.MemoryUsagePercent > 100:
+		return errors.New("rules memoryUsagePercent must be between 0 and 100")
+	case m.Rules.MemoryUsagePercent == 0:
+		m.Rules.MemoryUsagePercent = defaultMonitoringMemoryUsagePercent
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMonitoring(t *testing.T) {
+	tests := []struct {
+		name               string
+		monitoring         MonitoringSettings
+		expectedMonitoring MonitoringSettings
+		expectedError      string
+	}{
+		{
+			name: "disabled monitoring is not defaulted",
+		},
+		{
+			name:       "enabled monitoring is defaulted",
+			monitoring: MonitoringSettings{Enabled: true},
+			expectedMonitoring: MonitoringSettings{
+				Enabled:     true,
+				MonitorKind: MonitorKindServiceMonitor,
+				Rules:       MonitoringRules{ReplicationLagBytes: 10 << 20, MemoryUsagePercent: 90},
+			},
+		},
+		{
+			name: "set values are kept",
+			monitoring: MonitoringSettings{
+				Enabled:     true,
+				MonitorKind: MonitorKindPodMonitor,
+				Interval:    "15s",
+				Rules:       MonitoringRules{ReplicationLagBytes: 1024, MemoryUsagePercent: 80},
+			},
+			expectedMonitoring: MonitoringSettings{
+				Enabled:     true,
+				MonitorKind: MonitorKindPodMonitor,
+				Interval:    "15s",
+				Rules:       MonitoringRules{ReplicationLagBytes: 1024, MemoryUsagePercent: 80},
+			},
+		},
+		{
+			name:          "invalid monitor kind",
+			monitoring:    MonitoringSettings{Enabled: true, MonitorKind: "Probe"},
+			expectedError: `monitoring: unsupported monitorKind "Probe"`,
+		},
+		{
+			name:          "invalid interval",
+			monitoring:    MonitoringSettings{Enabled: true, Interval: "often"},
+			expectedError: `monitoring: invalid interval "often", it must be a positive duration like 30s`,
+		},
+		{
+			name:          "invalid memory usage",
+			monitoring:    MonitoringSettings{Enabled: true, Rules: MonitoringRules{MemoryUsagePercent: 150}},
+			expectedError: "monitoring: rules memoryUsagePercent must be between 0 and 100",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Monitoring = test.monitoring
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(err)
+				assert.Equal(test.expectedMonitoring, rf.Spec.Monitoring)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
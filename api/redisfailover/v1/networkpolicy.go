@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validate checks the peers of the network policy settings
+func (n *NetworkPolicySettings) validate() error {
+	if !n.Enabled {
+		return nil
+	}
+	for i, peer := range n.Clients {
+		if err := peer.validate(); err != nil {
+			return fmt.Errorf("clients[%d]: %w", i, err)
+		}
+	}
+	for i, peer := range n.Scrapers {
+		if err := peer.validate(); err != nil {
+			return fmt.Errorf("scrapers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the peer selects some pods with valid selectors
+func (p NetworkPolicyPeer) validate() error {
+	if p.NamespaceSelector == nil && p.PodSelector == nil {
+		return errors.New("a namespaceSelector or a podSelector is required")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(p.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(p.PodSelector); err != nil {
+		return fmt.Errorf("invalid podSelector: %w", err)
+	}
+	return nil
+}
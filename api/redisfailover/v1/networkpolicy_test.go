@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateNetworkPolicy(t *testing.T) {
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}
+
+	tests := []struct {
+		name          string
+		networkPolicy NetworkPolicySettings
+		expectedError string
+	}{
+		{
+			name: "disabled network policy",
+		},
+		{
+			name:          "enabled network policy without peers",
+			networkPolicy: NetworkPolicySettings{Enabled: true},
+		},
+		{
+			name: "enabled network policy with peers",
+			networkPolicy: NetworkPolicySettings{
+				Enabled:  true,
+				Clients:  []NetworkPolicyPeer{{PodSelector: appSelector}, {NamespaceSelector: &metav1.LabelSelector{}}},
+				Scrapers: []NetworkPolicyPeer{{NamespaceSelector: appSelector, PodSelector: appSelector}},
+			},
+		},
+		{
+			name: "client without selectors",
+			networkPolicy: NetworkPolicySettings{
+				Enabled: true,
+				Clients: []NetworkPolicyPeer{{PodSelector: appSelector}, {}},
+			},
+			expectedError: "networkPolicy: clients[1]: a namespaceSelector or a podSelector is required",
+		},
+		{
+			name: "scraper with an invalid selector",
+			networkPolicy: NetworkPolicySettings{
+				Enabled: true,
+				Scrapers: []NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Like"}},
+				}}},
+			},
+			expectedError: `networkPolicy: scrapers[0]: invalid podSelector: "Like" is not a valid label selector operator`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.NetworkPolicy = test.networkPolicy
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// PausedAnnotation pauses the healing of a RedisFailover. Its value is either a boolean or the time, in
+// RFC 3339 format, until the RedisFailover is paused.
+const PausedAnnotation = "redisfailovers.databases.spotahome.com/paused"
+
+// Paused returns true when the healing of the RedisFailover is paused at the given time, either by the
+// paused annotation or by the spec. An expired pause is ignored.
+func (r *RedisFailover) Paused(now time.Time) bool {
+	if r.Spec.Paused && (r.Spec.PausedUntil == nil || now.Before(r.Spec.PausedUntil.Time)) {
+		return true
+	}
+	paused, until, err := r.pausedAnnotation()
+	if err != nil || !paused {
+		return false
+	}
+	return until.IsZero() || now.Before(until)
+}
+
+// pausedAnnotation parses the paused annotation, returning the expiry of the pause when it has one
+func (r *RedisFailover) pausedAnnotation() (bool, time.Time, error) {
+	value, ok := r.Annotations[PausedAnnotation]
+	if !ok {
+		return false, time.Time{}, nil
+	}
+	if paused, err := strconv.ParseBool(value); err == nil {
+		return paused, time.Time{}, nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("%s annotation must be a boolean or a RFC 3339 time, got %q", PausedAnnotation, value)
+	}
+	return true, until, nil
+}
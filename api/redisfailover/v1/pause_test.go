@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPaused(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	later := metav1.NewTime(now.Add(time.Hour))
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+
+	tests := []struct {
+		name          string
+		annotation    string
+		paused        bool
+		pausedUntil   *metav1.Time
+		expectation   bool
+		expectedError string
+	}{
+		{
+			name:        "not paused",
+			expectation: false,
+		},
+		{
+			name:        "paused by spec",
+			paused:      true,
+			expectation: true,
+		},
+		{
+			name:        "paused by spec until later",
+			paused:      true,
+			pausedUntil: &later,
+			expectation: true,
+		},
+		{
+			name:        "expired pause by spec",
+			paused:      true,
+			pausedUntil: &earlier,
+			expectation: false,
+		},
+		{
+			name:        "paused by annotation",
+			annotation:  "true",
+			expectation: true,
+		},
+		{
+			name:        "resumed by annotation",
+			annotation:  "false",
+			expectation: false,
+		},
+		{
+			name:        "paused by annotation until later",
+			annotation:  later.Format(time.RFC3339),
+			expectation: true,
+		},
+		{
+			name:        "expired pause by annotation",
+			annotation:  earlier.Format(time.RFC3339),
+			expectation: false,
+		},
+		{
+			name:          "invalid annotation",
+			annotation:    "tomorrow",
+			expectation:   false,
+			expectedError: `redisfailovers.databases.spotahome.com/paused annotation must be a boolean or a RFC 3339 time, got "tomorrow"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Paused = test.paused
+			rf.Spec.PausedUntil = test.pausedUntil
+			if test.annotation != "" {
+				rf.Annotations = map[string]string{PausedAnnotation: test.annotation}
+			}
+
+			assert.Equal(test.expectation, rf.Paused(now))
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
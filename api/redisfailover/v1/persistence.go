@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+var defaultRDBSchedule = []RDBSaveRule{
+	{Seconds: 900, Changes: 1},
+	{Seconds: 300, Changes: 10},
+}
+
+const defaultAppendFsync = "everysec"
+
+// GetMode returns the persistence mode, RDB when not set
+func (p *RedisPersistence) GetMode() PersistenceMode {
+	if p == nil || p.Mode == "" {
+		return PersistenceModeRDB
+	}
+	return p.Mode
+}
+
+// Enabled returns true when redis persists its dataset to disk
+func (p *RedisPersistence) Enabled() bool {
+	return p.GetMode() != PersistenceModeNone
+}
+
+// AOFEnabled returns true when redis logs the write operations in the append only file
+func (p *RedisPersistence) AOFEnabled() bool {
+	mode := p.GetMode()
+	return mode == PersistenceModeAOF || mode == PersistenceModeHybrid
+}
+
+// GetRDBSchedule returns the RDB save rules of the persistence mode, the default ones when not set
+func (p *RedisPersistence) GetRDBSchedule() []RDBSaveRule {
+	mode := p.GetMode()
+	if mode != PersistenceModeRDB && mode != PersistenceModeHybrid {
+		return nil
+	}
+	if p == nil || len(p.RDBSchedule) == 0 {
+		return defaultRDBSchedule
+	}
+	return p.RDBSchedule
+}
+
+// GetAppendFsync returns the fsync policy of the append only file, everysec when not set
+func (p *RedisPersistence) GetAppendFsync() string {
+	if p == nil || p.AppendFsync == "" {
+		return defaultAppendFsync
+	}
+	return p.AppendFsync
+}
+
+// GetAOFUseRDBPreamble returns whether the append only file is rewritten with a RDB preamble. It is
+// used by default on the Hybrid mode only.
+func (p *RedisPersistence) GetAOFUseRDBPreamble() bool {
+	if p != nil && p.AOFUseRDBPreamble != nil {
+		return *p.AOFUseRDBPreamble
+	}
+	return p.GetMode() == PersistenceModeHybrid
+}
+
+// Config returns the redis configuration of the persistence mode, as "parameter value" entries that
+// can be applied at runtime
+func (p *RedisPersistence) Config() []string {
+	save := []string{}
+	for _, rule := range p.GetRDBSchedule() {
+		save = append(save, fmt.Sprintf("%d %d", rule.Seconds, rule.Changes))
+	}
+	config := []string{}
+	if len(save) == 0 {
+		config = append(config, `save ""`)
+	} else {
+		config = append(config, fmt.Sprintf("save %s", strings.Join(save, " ")))
+	}
+
+	if !p.AOFEnabled() {
+		return append(config, "appendonly no")
+	}
+	return append(config,
+		"appendonly yes",
+		fmt.Sprintf("appendfsync %s", p.GetAppendFsync()),
+		fmt.Sprintf("aof-use-rdb-preamble %s", yesNo(p.GetAOFUseRDBPreamble())),
+	)
+}
+
+func (p *RedisPersistence) validate() error {
+	if p == nil {
+		return nil
+	}
+	switch p.Mode {
+	case "", PersistenceModeRDB, PersistenceModeAOF, PersistenceModeHybrid, PersistenceModeNone:
+	default:
+		return fmt.Errorf("unsupported persistence mode %q", p.Mode)
+	}
+	switch p.AppendFsync {
+	case "", "always", "everysec", "no":
+	default:
+		return fmt.Errorf("unsupported persistence appendFsync %q", p.AppendFsync)
+	}
+
+	mode := p.GetMode()
+	if len(p.RDBSchedule) > 0 && mode != PersistenceModeRDB && mode != PersistenceModeHybrid {
+		return fmt.Errorf("persistence rdbSchedule can't be used with mode %s", mode)
+	}
+	for _, rule := range p.RDBSchedule {
+		if rule.Seconds <= 0 || rule.Changes <= 0 {
+			return fmt.Errorf("persistence rdbSchedule rules need positive seconds and changes")
+		}
+	}
+	if (p.AppendFsync != "" || p.AOFUseRDBPreamble != nil) && !p.AOFEnabled() {
+		return fmt.Errorf("persistence appendFsync and aofUseRdbPreamble can't be used with mode %s", mode)
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -19,6 +19,10 @@ const (
 	RFName       = "redisfailover"
 	RFNamePlural = "redisfailovers"
 	RFScope      = apiextensionsv1.NamespaceScoped
+
+	RFSnapshotKind       = "RedisFailoverSnapshot"
+	RFSnapshotName       = "redisfailoversnapshot"
+	RFSnapshotNamePlural = "redisfailoversnapshots"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -49,6 +53,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&RedisFailover{},
 		&RedisFailoverList{},
+		&RedisFailoverSnapshot{},
+		&RedisFailoverSnapshotList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
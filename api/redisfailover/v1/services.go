@@ -0,0 +1,7 @@
+package v1
+
+// DeployRedisService returns true when the headless service of the redis pods is needed: to scrape their
+// exporter, or to resolve the hostnames they announce
+func (r *RedisFailover) DeployRedisService() bool {
+	return r.Spec.Redis.Exporter.Enabled || r.Spec.AnnounceHostnames
+}
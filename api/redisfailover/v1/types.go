@@ -14,19 +14,134 @@ import (
 // +kubebuilder:printcolumn:name="SENTINELS",type="integer",JSONPath=".spec.sentinel.replicas"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:singular=redisfailover,path=redisfailovers,shortName=rf,scope=Namespaced
+// +kubebuilder:subresource:status
 type RedisFailover struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              RedisFailoverSpec `json:"spec"`
+	Spec              RedisFailoverSpec   `json:"spec"`
+	Status            RedisFailoverStatus `json:"status,omitempty"`
 }
 
 // RedisFailoverSpec represents a Redis failover spec
 type RedisFailoverSpec struct {
-	Redis          RedisSettings      `json:"redis,omitempty"`
-	Sentinel       SentinelSettings   `json:"sentinel,omitempty"`
-	Auth           AuthSettings       `json:"auth,omitempty"`
-	LabelWhitelist []string           `json:"labelWhitelist,omitempty"`
-	BootstrapNode  *BootstrapSettings `json:"bootstrapNode,omitempty"`
+	Redis             RedisSettings      `json:"redis,omitempty"`
+	Sentinel          SentinelSettings   `json:"sentinel,omitempty"`
+	Auth              AuthSettings       `json:"auth,omitempty"`
+	LabelWhitelist    []string           `json:"labelWhitelist,omitempty"`
+	BootstrapNode     *BootstrapSettings `json:"bootstrapNode,omitempty"`
+	AnnounceHostnames bool               `json:"announceHostnames,omitempty"`
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// +kubebuilder:validation:Enum=Report;Enforce
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// Paused stops the healing of the RedisFailover, so it can be fixed manually. The checks keep running
+	// and reporting metrics. It can also be set with the paused annotation.
+	Paused bool `json:"paused,omitempty"`
+	// PausedUntil is the time the pause expires at. Without it, the RedisFailover is paused until Paused is unset.
+	PausedUntil *metav1.Time `json:"pausedUntil,omitempty"`
+	// MaintenanceWindows are the recurring time ranges when the disruptive operations, like restarting the
+	// redis pods to update them or expanding their volumes, are done. Without them, they are done at any time.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// Monitoring creates the prometheus-operator objects scraping the exporters and alerting on the RedisFailover
+	Monitoring MonitoringSettings `json:"monitoring,omitempty"`
+	// NetworkPolicy creates the NetworkPolicies denying the connections to the redis and sentinel pods that
+	// are not needed by the RedisFailover or allowed to its clients
+	NetworkPolicy NetworkPolicySettings `json:"networkPolicy,omitempty"`
+}
+
+// MaintenanceWindow is a time range repeated on some days of the week
+type MaintenanceWindow struct {
+	// Days of the week the window opens on, by their three letter name (Mon, Tue...). Every day when empty.
+	Days []string `json:"days,omitempty"`
+	// Start is the time of the day the window opens at, in HH:MM format
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// Duration of the window, like 2h or 30m
+	Duration metav1.Duration `json:"duration"`
+	// Timezone is the IANA time zone of the window, like Europe/Madrid. UTC when empty.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// DeletionPolicy defines what happens to the redis data when a RedisFailover is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the persistent volume claims of the redis nodes
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the persistent volume claims of the redis nodes
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicySnapshot takes a VolumeSnapshot of every persistent volume claim of the redis
+	// nodes, after a final save of the dataset, and deletes the claims afterwards
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
+// DriftPolicy defines what happens when the configuration of a redis or sentinel is changed out of the operator
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport reports the changed parameters with metrics and events, keeping the changed values
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyEnforce reports the changed parameters and sets them back to the desired values
+	DriftPolicyEnforce DriftPolicy = "Enforce"
+)
+
+// RedisFailoverStatus represents the observed state of a Redis failover
+type RedisFailoverStatus struct {
+	// Paused is true while the healing of the RedisFailover is paused
+	Paused  bool           `json:"paused,omitempty"`
+	Storage *StorageStatus `json:"storage,omitempty"`
+	// PendingOperations are the disruptive operations waiting for a maintenance window
+	PendingOperations []PendingOperation `json:"pendingOperations,omitempty"`
+}
+
+// PendingOperationType is a disruptive operation that is only done within the maintenance windows
+type PendingOperationType string
+
+const (
+	// PendingOperationRedisPodsUpdate restarts the redis pods that are outdated against their statefulset
+	PendingOperationRedisPodsUpdate PendingOperationType = "RedisPodsUpdate"
+	// PendingOperationStorageExpansion expands the redis persistent volume claims, restarting the pods
+	// whose volumes can only be expanded offline
+	PendingOperationStorageExpansion PendingOperationType = "StorageExpansion"
+)
+
+// PendingOperation is a disruptive operation waiting for a maintenance window
+type PendingOperation struct {
+	Type    PendingOperationType `json:"type"`
+	Message string               `json:"message,omitempty"`
+}
+
+// StorageExpansionPhase is the progress of the expansion of the redis persistent volume claims
+type StorageExpansionPhase string
+
+const (
+	// StorageExpansionPhaseResizing the volumes are being expanded
+	StorageExpansionPhaseResizing StorageExpansionPhase = "Resizing"
+	// StorageExpansionPhaseFileSystemResizePending the volumes are expanded and wait for the file
+	// system to be resized on the node
+	StorageExpansionPhaseFileSystemResizePending StorageExpansionPhase = "FileSystemResizePending"
+	// StorageExpansionPhaseResized the volumes have the requested capacity
+	StorageExpansionPhaseResized StorageExpansionPhase = "Resized"
+	// StorageExpansionPhasePending the volumes wait for a maintenance window to be expanded
+	StorageExpansionPhasePending StorageExpansionPhase = "Pending"
+	// StorageExpansionPhaseFailed the volumes can't be expanded to the requested capacity
+	StorageExpansionPhaseFailed StorageExpansionPhase = "Failed"
+)
+
+// StorageStatus reports the expansion of the redis persistent volume claims
+type StorageStatus struct {
+	Phase                  StorageExpansionPhase                `json:"phase,omitempty"`
+	RequestedCapacity      string                               `json:"requestedCapacity,omitempty"`
+	Message                string                               `json:"message,omitempty"`
+	PersistentVolumeClaims []PersistentVolumeClaimStorageStatus `json:"persistentVolumeClaims,omitempty"`
+}
+
+// PersistentVolumeClaimStorageStatus reports the expansion of a redis persistent volume claim
+type PersistentVolumeClaimStorageStatus struct {
+	Name     string                `json:"name"`
+	Capacity string                `json:"capacity,omitempty"`
+	Phase    StorageExpansionPhase `json:"phase,omitempty"`
+	Message  string                `json:"message,omitempty"`
 }
 
 // RedisCommandRename defines the specification of a "rename-command" configuration option
@@ -48,6 +163,7 @@ type RedisSettings struct {
 	ShutdownConfigMap             string                            `json:"shutdownConfigMap,omitempty"`
 	StartupConfigMap              string                            `json:"startupConfigMap,omitempty"`
 	Storage                       RedisStorage                      `json:"storage,omitempty"`
+	Persistence                   *RedisPersistence                 `json:"persistence,omitempty"`
 	InitContainers                []corev1.Container                `json:"initContainers,omitempty"`
 	Exporter                      Exporter                          `json:"exporter,omitempty"`
 	ExtraContainers               []corev1.Container                `json:"extraContainers,omitempty"`
@@ -71,6 +187,12 @@ type RedisSettings struct {
 	CustomReadinessProbe          *corev1.Probe                     `json:"customReadinessProbe,omitempty"`
 	CustomStartupProbe            *corev1.Probe                     `json:"customStartupProbe,omitempty"`
 	DisablePodDisruptionBudget    bool                              `json:"disablePodDisruptionBudget,omitempty"`
+	MasterService                 ServiceSettings                   `json:"masterService,omitempty"`
+	// AnnounceIP is the address every redis pod announces to its master and the sentinels, instead of its pod
+	// IP. It can reference $(HOST_IP) or $(POD_IP), a fixed address is only valid for a single redis.
+	AnnounceIP string `json:"announceIP,omitempty"`
+	// AnnouncePort is the port every redis pod announces along with its address
+	AnnouncePort int32 `json:"announcePort,omitempty"`
 }
 
 // SentinelSettings defines the specification of the sentinel cluster
@@ -105,6 +227,43 @@ type SentinelSettings struct {
 	CustomReadinessProbe       *corev1.Probe                     `json:"customReadinessProbe,omitempty"`
 	CustomStartupProbe         *corev1.Probe                     `json:"customStartupProbe,omitempty"`
 	DisablePodDisruptionBudget bool                              `json:"disablePodDisruptionBudget,omitempty"`
+	Service                    ServiceSettings                   `json:"service,omitempty"`
+	// AnnounceIP is the address every sentinel announces to the rest. It can reference $(HOST_IP) or $(POD_IP),
+	// a fixed address is only valid for a single sentinel.
+	AnnounceIP   string `json:"announceIP,omitempty"`
+	AnnouncePort int32  `json:"announcePort,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9._-]+$`
+	MasterName  string                 `json:"masterName,omitempty"`
+	SharedGroup *SharedSentinelGroup   `json:"sharedGroup,omitempty"`
+	PoolRef     *SentinelPoolReference `json:"poolRef,omitempty"`
+}
+
+// SentinelPoolReference points to a RedisFailover of the same namespace whose sentinels monitor the redis of
+// the RedisFailover along with its own master
+type SentinelPoolReference struct {
+	// Name is the name of the RedisFailover deploying the sentinels
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SharedSentinelGroup is an existing group of sentinels, not deployed by the operator, that monitors the redis
+// of the RedisFailover along with other masters
+type SharedSentinelGroup struct {
+	// Addresses are the hosts of the sentinels of the group, listening on the sentinel port 26379
+	// +kubebuilder:validation:MinItems=1
+	Addresses []string `json:"addresses"`
+	// Quorum is the number of sentinels that need to agree about the master being down. Defaults to
+	// the majority of the group.
+	Quorum int32 `json:"quorum,omitempty"`
+}
+
+// ServiceSettings defines how a service generated by the operator is exposed
+type ServiceSettings struct {
+	Type                     corev1.ServiceType                      `json:"type,omitempty"`
+	LoadBalancerIP           string                                  `json:"loadBalancerIP,omitempty"`
+	LoadBalancerSourceRanges []string                                `json:"loadBalancerSourceRanges,omitempty"`
+	ExternalTrafficPolicy    corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	ExtraPorts               []corev1.ServicePort                    `json:"extraPorts,omitempty"`
 }
 
 // AuthSettings contains settings about auth
@@ -130,6 +289,64 @@ type Exporter struct {
 	Resources                *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
+// MonitorKind is the kind of the prometheus-operator objects scraping the exporters
+type MonitorKind string
+
+const (
+	// MonitorKindServiceMonitor scrapes the exporters through the services of the redis and the sentinels
+	MonitorKindServiceMonitor MonitorKind = "ServiceMonitor"
+	// MonitorKindPodMonitor scrapes the exporters of the redis and sentinel pods directly
+	MonitorKindPodMonitor MonitorKind = "PodMonitor"
+)
+
+// MonitoringSettings defines the prometheus-operator objects generated for the RedisFailover, they need
+// the prometheus-operator CRDs installed on the cluster
+type MonitoringSettings struct {
+	// Enabled creates a monitor for every enabled exporter and a PrometheusRule with the default alerts
+	Enabled bool `json:"enabled,omitempty"`
+	// MonitorKind is the kind of the monitors, ServiceMonitor by default
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+	MonitorKind MonitorKind `json:"monitorKind,omitempty"`
+	// Interval the exporters are scraped at, like 30s. The prometheus one when empty.
+	Interval string `json:"interval,omitempty"`
+	// Labels are added to the monitors and the rule, so the selectors of prometheus match them
+	Labels map[string]string `json:"labels,omitempty"`
+	Rules  MonitoringRules   `json:"rules,omitempty"`
+}
+
+// MonitoringRules defines the default alerts of the RedisFailover
+type MonitoringRules struct {
+	// Disabled skips the creation of the PrometheusRule
+	Disabled bool `json:"disabled,omitempty"`
+	// ReplicationLagBytes is the replication lag of a slave alerted on, 10MiB by default
+	// +kubebuilder:validation:Minimum=0
+	ReplicationLagBytes int64 `json:"replicationLagBytes,omitempty"`
+	// MemoryUsagePercent is the percentage of maxmemory used alerted on, 90 by default
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MemoryUsagePercent int32 `json:"memoryUsagePercent,omitempty"`
+}
+
+// NetworkPolicySettings defines the NetworkPolicies generated for the RedisFailover. They allow the
+// replication between the redis, the sentinels, the operator and the given clients, the rest of
+// connections to the redis and sentinel pods are denied.
+type NetworkPolicySettings struct {
+	// Enabled creates a NetworkPolicy for the redis pods and another one for the sentinel pods
+	Enabled bool `json:"enabled,omitempty"`
+	// Clients are the pods allowed to connect to redis and sentinel
+	Clients []NetworkPolicyPeer `json:"clients,omitempty"`
+	// Scrapers are the pods allowed to connect to the exporters. Any pod when empty.
+	Scrapers []NetworkPolicyPeer `json:"scrapers,omitempty"`
+}
+
+// NetworkPolicyPeer selects the pods allowed by a NetworkPolicy. Without a namespace selector, the pods
+// are selected in the namespace of the RedisFailover; without a pod selector, every pod of the selected
+// namespaces is.
+type NetworkPolicyPeer struct {
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	PodSelector       *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
 // SentinelConfigCopy defines the specification for the sentinel exporter
 type SentinelConfigCopy struct {
 	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
@@ -142,6 +359,41 @@ type RedisStorage struct {
 	PersistentVolumeClaim *EmbeddedPersistentVolumeClaim `json:"persistentVolumeClaim,omitempty"`
 }
 
+// PersistenceMode defines how redis persists its dataset to disk
+type PersistenceMode string
+
+const (
+	// PersistenceModeRDB takes point-in-time snapshots of the dataset following the RDB schedule
+	PersistenceModeRDB PersistenceMode = "RDB"
+	// PersistenceModeAOF logs every write operation in the append only file
+	PersistenceModeAOF PersistenceMode = "AOF"
+	// PersistenceModeHybrid logs every write operation in the append only file, and takes RDB
+	// snapshots following the RDB schedule
+	PersistenceModeHybrid PersistenceMode = "Hybrid"
+	// PersistenceModeNone doesn't persist the dataset
+	PersistenceModeNone PersistenceMode = "None"
+)
+
+// RedisPersistence defines how redis persists its dataset. When not set, redis takes RDB snapshots
+// following the default schedule.
+type RedisPersistence struct {
+	// +kubebuilder:validation:Enum=RDB;AOF;Hybrid;None
+	Mode PersistenceMode `json:"mode,omitempty"`
+	// +kubebuilder:validation:Enum=always;everysec;no
+	AppendFsync       string        `json:"appendFsync,omitempty"`
+	RDBSchedule       []RDBSaveRule `json:"rdbSchedule,omitempty"`
+	AOFUseRDBPreamble *bool         `json:"aofUseRdbPreamble,omitempty"`
+}
+
+// RDBSaveRule makes redis take a RDB snapshot after the given seconds when at least the given number
+// of changes were done
+type RDBSaveRule struct {
+	// +kubebuilder:validation:Minimum=1
+	Seconds int32 `json:"seconds"`
+	// +kubebuilder:validation:Minimum=1
+	Changes int32 `json:"changes"`
+}
+
 // EmbeddedPersistentVolumeClaim is an embedded version of k8s.io/api/core/v1.PersistentVolumeClaim.
 // It contains TypeMeta and a reduced ObjectMeta.
 type EmbeddedPersistentVolumeClaim struct {
@@ -198,3 +450,60 @@ type RedisFailoverList struct {
 
 	Items []RedisFailover `json:"items"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverSnapshot represents a point-in-time VolumeSnapshot of the data of a Redis failover
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
+// +kubebuilder:printcolumn:name="REDISFAILOVER",type="string",JSONPath=".spec.redisFailoverName"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="VOLUMESNAPSHOT",type="string",JSONPath=".status.volumeSnapshotName"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=redisfailoversnapshot,path=redisfailoversnapshots,shortName=rfsnap,scope=Namespaced
+// +kubebuilder:subresource:status
+type RedisFailoverSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RedisFailoverSnapshotSpec   `json:"spec"`
+	Status            RedisFailoverSnapshotStatus `json:"status,omitempty"`
+}
+
+// RedisFailoverSnapshotSpec represents a Redis failover snapshot spec
+type RedisFailoverSnapshotSpec struct {
+	RedisFailoverName       string `json:"redisFailoverName"`
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// RedisFailoverSnapshotPhase is the progress of a Redis failover snapshot
+type RedisFailoverSnapshotPhase string
+
+const (
+	// RedisFailoverSnapshotPhaseSaving the replica is saving its dataset to disk
+	RedisFailoverSnapshotPhaseSaving RedisFailoverSnapshotPhase = "Saving"
+	// RedisFailoverSnapshotPhaseSnapshotting the VolumeSnapshot is created and not ready to use yet
+	RedisFailoverSnapshotPhaseSnapshotting RedisFailoverSnapshotPhase = "Snapshotting"
+	// RedisFailoverSnapshotPhaseReady the VolumeSnapshot is ready to be restored
+	RedisFailoverSnapshotPhaseReady RedisFailoverSnapshotPhase = "Ready"
+	// RedisFailoverSnapshotPhaseFailed the snapshot can't be taken
+	RedisFailoverSnapshotPhaseFailed RedisFailoverSnapshotPhase = "Failed"
+)
+
+// RedisFailoverSnapshotStatus represents the observed state of a Redis failover snapshot
+type RedisFailoverSnapshotStatus struct {
+	Phase                     RedisFailoverSnapshotPhase `json:"phase,omitempty"`
+	PodName                   string                     `json:"podName,omitempty"`
+	PersistentVolumeClaimName string                     `json:"persistentVolumeClaimName,omitempty"`
+	VolumeSnapshotName        string                     `json:"volumeSnapshotName,omitempty"`
+	Message                   string                     `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverSnapshotList represents a Redis failover snapshot list
+type RedisFailoverSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RedisFailoverSnapshot `json:"items"`
+}
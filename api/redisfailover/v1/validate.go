@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -16,6 +19,56 @@ func (r *RedisFailover) Validate() error {
 		return fmt.Errorf("name length can't be higher than %d", maxNameLength)
 	}
 
+	if err := validateServiceSettings(r.Spec.Redis.MasterService); err != nil {
+		return fmt.Errorf("redis masterService: %w", err)
+	}
+
+	if err := validateServiceSettings(r.Spec.Sentinel.Service); err != nil {
+		return fmt.Errorf("sentinel service: %w", err)
+	}
+
+	if err := r.validateSentinelGroup(); err != nil {
+		return err
+	}
+
+	if err := r.validateDeletionPolicy(); err != nil {
+		return err
+	}
+
+	switch r.Spec.DriftPolicy {
+	case "":
+		r.Spec.DriftPolicy = DriftPolicyReport
+	case DriftPolicyReport, DriftPolicyEnforce:
+	default:
+		return fmt.Errorf("unsupported driftPolicy %q", r.Spec.DriftPolicy)
+	}
+
+	if _, _, err := r.pausedAnnotation(); err != nil {
+		return err
+	}
+
+	for i, window := range r.Spec.MaintenanceWindows {
+		if err := window.validate(); err != nil {
+			return fmt.Errorf("maintenanceWindows[%d]: %w", i, err)
+		}
+	}
+
+	if err := r.Spec.Redis.Persistence.validate(); err != nil {
+		return err
+	}
+
+	if persistence := r.Spec.Redis.Persistence; persistence != nil && !persistence.Enabled() && r.Spec.DeletionPolicy == DeletionPolicyRetain {
+		return errors.New("persistence can't be disabled while retaining the redis storage, the retained volumes wouldn't have any data")
+	}
+
+	if err := r.Spec.Monitoring.validate(); err != nil {
+		return fmt.Errorf("monitoring: %w", err)
+	}
+
+	if err := r.Spec.NetworkPolicy.validate(); err != nil {
+		return fmt.Errorf("networkPolicy: %w", err)
+	}
+
 	if r.Bootstrapping() {
 		if r.Spec.BootstrapNode.Host == "" {
 			return errors.New("BootstrapNode must include a host when provided")
@@ -24,9 +77,9 @@ func (r *RedisFailover) Validate() error {
 		if r.Spec.BootstrapNode.Port == "" {
 			r.Spec.BootstrapNode.Port = strconv.Itoa(defaultRedisPort)
 		}
-		r.Spec.Redis.CustomConfig = deduplicateStr(append(bootstrappingRedisCustomConfig, r.Spec.Redis.CustomConfig...))
+		r.Spec.Redis.CustomConfig = deduplicateStr(append(r.initialRedisCustomConfig(bootstrappingRedisCustomConfig), r.Spec.Redis.CustomConfig...))
 	} else {
-		r.Spec.Redis.CustomConfig = deduplicateStr(append(defaultRedisCustomConfig, r.Spec.Redis.CustomConfig...))
+		r.Spec.Redis.CustomConfig = deduplicateStr(append(r.initialRedisCustomConfig(defaultRedisCustomConfig), r.Spec.Redis.CustomConfig...))
 	}
 
 	if r.Spec.Redis.Image == "" {
@@ -49,6 +102,19 @@ func (r *RedisFailover) Validate() error {
 		r.Spec.Sentinel.Replicas = defaultSentinelNumber
 	}
 
+	if err := validateAnnounce(r.Spec.Redis.AnnounceIP, r.Spec.Redis.AnnouncePort, r.Spec.Redis.Replicas); err != nil {
+		return fmt.Errorf("redis %w", err)
+	}
+	if r.Spec.AnnounceHostnames && (r.Spec.Redis.AnnounceIP != "" || r.Spec.Redis.AnnouncePort > 0) {
+		return errors.New("redis announceIP and announcePort can't be used with announceHostnames")
+	}
+
+	if r.DeploySentinels() {
+		if err := validateAnnounce(r.Spec.Sentinel.AnnounceIP, r.Spec.Sentinel.AnnouncePort, r.Spec.Sentinel.Replicas); err != nil {
+			return fmt.Errorf("sentinel %w", err)
+		}
+	}
+
 	if r.Spec.Redis.Exporter.Image == "" {
 		r.Spec.Redis.Exporter.Image = defaultExporterImage
 	}
@@ -64,6 +130,133 @@ func (r *RedisFailover) Validate() error {
 	return nil
 }
 
+// validateSentinelGroup checks the shared sentinel group and pool settings, and defaults the master name the
+// sentinels know the redis master by
+func (r *RedisFailover) validateSentinelGroup() error {
+	group := r.Spec.Sentinel.SharedGroup
+	if group != nil {
+		if r.Bootstrapping() {
+			return errors.New("sentinel sharedGroup can't be used with bootstrapNode")
+		}
+		// Other masters are monitored by the same sentinels, so the default name is likely taken
+		if r.Spec.Sentinel.MasterName == "" {
+			return errors.New("sentinel sharedGroup requires a masterName")
+		}
+		if len(group.Addresses) == 0 {
+			return errors.New("sentinel sharedGroup requires at least one address")
+		}
+		if group.Quorum <= 0 {
+			group.Quorum = int32(len(group.Addresses))/2 + 1
+		} else if group.Quorum > int32(len(group.Addresses)) {
+			return fmt.Errorf("sentinel sharedGroup quorum %d is greater than the number of addresses", group.Quorum)
+		}
+	}
+
+	pool := r.Spec.Sentinel.PoolRef
+	if pool != nil {
+		if group != nil {
+			return errors.New("sentinel poolRef can't be used with sharedGroup")
+		}
+		if r.Bootstrapping() {
+			return errors.New("sentinel poolRef can't be used with bootstrapNode")
+		}
+		// The pool monitors its own master too, so the default name is taken
+		if r.Spec.Sentinel.MasterName == "" {
+			return errors.New("sentinel poolRef requires a masterName")
+		}
+		if pool.Name == "" {
+			return errors.New("sentinel poolRef requires a name")
+		}
+		if pool.Name == r.Name {
+			return errors.New("sentinel poolRef can't point to the RedisFailover itself")
+		}
+	}
+
+	if r.Spec.Sentinel.MasterName == "" {
+		r.Spec.Sentinel.MasterName = defaultSentinelMasterName
+	} else if strings.ContainsAny(r.Spec.Sentinel.MasterName, " \t\n") {
+		return fmt.Errorf("invalid sentinel masterName %q", r.Spec.Sentinel.MasterName)
+	}
+	return nil
+}
+
+// validateDeletionPolicy checks the deletion policy matches the storage settings. When not set,
+// it is defaulted from keepAfterDeletion so the previous behaviour is kept.
+func (r *RedisFailover) validateDeletionPolicy() error {
+	storage := r.Spec.Redis.Storage
+	switch r.Spec.DeletionPolicy {
+	case "":
+		r.Spec.DeletionPolicy = DeletionPolicyDelete
+		if storage.KeepAfterDeletion {
+			r.Spec.DeletionPolicy = DeletionPolicyRetain
+		}
+	case DeletionPolicyDelete:
+		if storage.KeepAfterDeletion {
+			return errors.New("deletionPolicy Delete can't be used with redis storage keepAfterDeletion")
+		}
+	case DeletionPolicyRetain:
+	case DeletionPolicySnapshot:
+		if storage.PersistentVolumeClaim == nil {
+			return errors.New("deletionPolicy Snapshot requires a redis persistentVolumeClaim storage")
+		}
+	default:
+		return fmt.Errorf("unsupported deletionPolicy %q", r.Spec.DeletionPolicy)
+	}
+	return nil
+}
+
+// initialRedisCustomConfig returns the configuration applied to redis before the custom one. When the
+// persistence is set, its configuration is applied too, so changing it doesn't need a restart.
+func (r *RedisFailover) initialRedisCustomConfig(defaults []string) []string {
+	config := append([]string{}, defaults...)
+	if r.Spec.Redis.Persistence != nil {
+		config = append(config, r.Spec.Redis.Persistence.Config()...)
+	}
+	return config
+}
+
+// Warnings returns the settings that are valid but most likely not what was intended
+func (r *RedisFailover) Warnings() []string {
+	warnings := []string{}
+	persistence := r.Spec.Redis.Persistence
+	storage := r.Spec.Redis.Storage
+	if persistence != nil && persistence.Enabled() && storage.PersistentVolumeClaim == nil {
+		warnings = append(warnings, fmt.Sprintf("persistence mode %s is enabled on an emptyDir volume, the data is lost when a pod is deleted", persistence.GetMode()))
+	}
+	if r.Spec.Monitoring.Enabled && !r.Spec.Redis.Exporter.Enabled {
+		warnings = append(warnings, "monitoring is enabled without the redis exporter, the alerts have no metrics to fire on")
+	}
+	if r.Spec.NetworkPolicy.Enabled && r.Spec.Sentinel.SharedGroup != nil {
+		warnings = append(warnings, "the network policy doesn't allow the shared sentinels, they must be added to its clients")
+	}
+	if len(r.Spec.Redis.Command) > 0 {
+		warnings = append(warnings, "the custom redis command must drop the slaveof and replicaof lines of /redis/redis.conf, otherwise a restarted master starts as a master")
+	}
+	return warnings
+}
+
+func validateServiceSettings(s ServiceSettings) error {
+	switch s.Type {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		return fmt.Errorf("service type %s is not supported", s.Type)
+	}
+
+	if len(s.LoadBalancerSourceRanges) > 0 && s.Type != corev1.ServiceTypeLoadBalancer {
+		return errors.New("loadBalancerSourceRanges can only be used with LoadBalancer services")
+	}
+
+	if s.LoadBalancerIP != "" && s.Type != corev1.ServiceTypeLoadBalancer {
+		return errors.New("loadBalancerIP can only be used with LoadBalancer services")
+	}
+
+	if s.ExternalTrafficPolicy != "" && s.Type != corev1.ServiceTypeLoadBalancer && s.Type != corev1.ServiceTypeNodePort {
+		return errors.New("externalTrafficPolicy can only be used with NodePort or LoadBalancer services")
+	}
+
+	return nil
+}
+
 func deduplicateStr(strSlice []string) []string {
 	allKeys := make(map[string]bool)
 	list := []string{}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -117,8 +118,11 @@ func TestValidate(t *testing.T) {
 							Exporter: Exporter{
 								Image: defaultSentinelExporterImage,
 							},
+							MasterName: defaultSentinelMasterName,
 						},
-						BootstrapNode: test.expectedBootstrapNode,
+						BootstrapNode:  test.expectedBootstrapNode,
+						DeletionPolicy: DeletionPolicyDelete,
+						DriftPolicy:    DriftPolicyReport,
 					},
 				}
 				assert.Equal(expectedRF, rf)
@@ -130,3 +134,535 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateServiceSettings(t *testing.T) {
+	tests := []struct {
+		name              string
+		rfMasterService   ServiceSettings
+		rfSentinelService ServiceSettings
+		expectedError     string
+	}{
+		{
+			name: "defaults are valid",
+		},
+		{
+			name: "LoadBalancer master service with source ranges",
+			rfMasterService: ServiceSettings{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+				ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+			},
+		},
+		{
+			name: "NodePort sentinel service with traffic policy",
+			rfSentinelService: ServiceSettings{
+				Type:                  corev1.ServiceTypeNodePort,
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			},
+		},
+		{
+			name: "unsupported service type",
+			rfMasterService: ServiceSettings{
+				Type: corev1.ServiceTypeExternalName,
+			},
+			expectedError: "redis masterService: service type ExternalName is not supported",
+		},
+		{
+			name: "source ranges without LoadBalancer",
+			rfMasterService: ServiceSettings{
+				Type:                     corev1.ServiceTypeNodePort,
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			},
+			expectedError: "redis masterService: loadBalancerSourceRanges can only be used with LoadBalancer services",
+		},
+		{
+			name: "traffic policy on a ClusterIP service",
+			rfSentinelService: ServiceSettings{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			},
+			expectedError: "sentinel service: externalTrafficPolicy can only be used with NodePort or LoadBalancer services",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.MasterService = test.rfMasterService
+			rf.Spec.Sentinel.Service = test.rfSentinelService
+
+			err := rf.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateAnnounce(t *testing.T) {
+	tests := []struct {
+		name              string
+		announceHostnames bool
+		redis             RedisSettings
+		sentinel          SentinelSettings
+		sharedGroup       bool
+		expectedError     string
+	}{
+		{
+			name: "defaults are valid",
+		},
+		{
+			name:     "addresses resolved per pod",
+			redis:    RedisSettings{AnnounceIP: AnnounceHostIP, AnnouncePort: 30379},
+			sentinel: SentinelSettings{AnnounceIP: AnnouncePodIP, AnnouncePort: 32379},
+		},
+		{
+			name:     "fixed addresses of a single pod",
+			redis:    RedisSettings{Replicas: 1, AnnounceIP: "203.0.113.10"},
+			sentinel: SentinelSettings{Replicas: 1, AnnounceIP: "203.0.113.11"},
+		},
+		{
+			name:          "fixed redis address of several pods",
+			redis:         RedisSettings{AnnounceIP: "203.0.113.10"},
+			expectedError: `redis announceIP "203.0.113.10" would be announced by all the 3 pods, it must reference $(HOST_IP) or $(POD_IP)`,
+		},
+		{
+			name:          "fixed sentinel address of several pods",
+			sentinel:      SentinelSettings{AnnounceIP: "203.0.113.11"},
+			expectedError: `sentinel announceIP "203.0.113.11" would be announced by all the 3 pods, it must reference $(HOST_IP) or $(POD_IP)`,
+		},
+		{
+			name:        "fixed sentinel address of shared sentinels",
+			sentinel:    SentinelSettings{AnnounceIP: "203.0.113.11", MasterName: "mymaster"},
+			sharedGroup: true,
+		},
+		{
+			name:          "unsupported reference",
+			redis:         RedisSettings{AnnounceIP: "$(NODE_IP)"},
+			expectedError: `redis announceIP "$(NODE_IP)" can only reference $(HOST_IP) or $(POD_IP)`,
+		},
+		{
+			name:          "negative port",
+			sentinel:      SentinelSettings{AnnouncePort: -1},
+			expectedError: "sentinel announcePort can't be negative",
+		},
+		{
+			name:              "redis address with hostnames",
+			announceHostnames: true,
+			redis:             RedisSettings{AnnounceIP: AnnounceHostIP},
+			expectedError:     "redis announceIP and announcePort can't be used with announceHostnames",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.AnnounceHostnames = test.announceHostnames
+			rf.Spec.Redis = test.redis
+			rf.Spec.Sentinel = test.sentinel
+			if test.sharedGroup {
+				rf.Spec.Sentinel.SharedGroup = &SharedSentinelGroup{Addresses: []string{"10.0.0.1"}}
+			}
+
+			err := rf.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		name                   string
+		rfDeletionPolicy       DeletionPolicy
+		rfStorage              RedisStorage
+		expectedDeletionPolicy DeletionPolicy
+		expectedError          string
+	}{
+		{
+			name:                   "defaults to Delete",
+			expectedDeletionPolicy: DeletionPolicyDelete,
+		},
+		{
+			name:                   "defaults to Retain when keeping the storage after deletion",
+			rfStorage:              RedisStorage{KeepAfterDeletion: true},
+			expectedDeletionPolicy: DeletionPolicyRetain,
+		},
+		{
+			name:                   "Retain",
+			rfDeletionPolicy:       DeletionPolicyRetain,
+			expectedDeletionPolicy: DeletionPolicyRetain,
+		},
+		{
+			name:             "Snapshot with a persistent volume claim",
+			rfDeletionPolicy: DeletionPolicySnapshot,
+			rfStorage: RedisStorage{
+				PersistentVolumeClaim: &EmbeddedPersistentVolumeClaim{},
+			},
+			expectedDeletionPolicy: DeletionPolicySnapshot,
+		},
+		{
+			name:             "Snapshot without a persistent volume claim",
+			rfDeletionPolicy: DeletionPolicySnapshot,
+			expectedError:    "deletionPolicy Snapshot requires a redis persistentVolumeClaim storage",
+		},
+		{
+			name:             "Delete keeping the storage after deletion",
+			rfDeletionPolicy: DeletionPolicyDelete,
+			rfStorage:        RedisStorage{KeepAfterDeletion: true},
+			expectedError:    "deletionPolicy Delete can't be used with redis storage keepAfterDeletion",
+		},
+		{
+			name:             "unsupported policy",
+			rfDeletionPolicy: "Orphan",
+			expectedError:    `unsupported deletionPolicy "Orphan"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.DeletionPolicy = test.rfDeletionPolicy
+			rf.Spec.Redis.Storage = test.rfStorage
+
+			err := rf.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+				assert.Equal(test.expectedDeletionPolicy, rf.Spec.DeletionPolicy)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateSentinelGroup(t *testing.T) {
+	tests := []struct {
+		name               string
+		masterName         string
+		sharedGroup        *SharedSentinelGroup
+		poolRef            *SentinelPoolReference
+		bootstrapNode      *BootstrapSettings
+		expectedMasterName string
+		expectedQuorum     int32
+		expectedError      string
+	}{
+		{
+			name:               "defaults the master name",
+			expectedMasterName: "mymaster",
+		},
+		{
+			name:               "custom master name",
+			masterName:         "cache",
+			expectedMasterName: "cache",
+		},
+		{
+			name:          "invalid master name",
+			masterName:    "my master",
+			expectedError: `invalid sentinel masterName "my master"`,
+		},
+		{
+			name:               "shared group defaults the quorum",
+			masterName:         "cache",
+			sharedGroup:        &SharedSentinelGroup{Addresses: []string{"s1", "s2", "s3"}},
+			expectedMasterName: "cache",
+			expectedQuorum:     2,
+		},
+		{
+			name:               "shared group with quorum",
+			masterName:         "cache",
+			sharedGroup:        &SharedSentinelGroup{Addresses: []string{"s1", "s2", "s3"}, Quorum: 3},
+			expectedMasterName: "cache",
+			expectedQuorum:     3,
+		},
+		{
+			name:          "shared group without master name",
+			sharedGroup:   &SharedSentinelGroup{Addresses: []string{"s1"}},
+			expectedError: "sentinel sharedGroup requires a masterName",
+		},
+		{
+			name:          "shared group without addresses",
+			masterName:    "cache",
+			sharedGroup:   &SharedSentinelGroup{},
+			expectedError: "sentinel sharedGroup requires at least one address",
+		},
+		{
+			name:          "shared group quorum greater than the addresses",
+			masterName:    "cache",
+			sharedGroup:   &SharedSentinelGroup{Addresses: []string{"s1"}, Quorum: 2},
+			expectedError: "sentinel sharedGroup quorum 2 is greater than the number of addresses",
+		},
+		{
+			name:          "shared group when bootstrapping",
+			masterName:    "cache",
+			sharedGroup:   &SharedSentinelGroup{Addresses: []string{"s1"}},
+			bootstrapNode: &BootstrapSettings{Host: "127.0.0.1", AllowSentinels: true},
+			expectedError: "sentinel sharedGroup can't be used with bootstrapNode",
+		},
+		{
+			name:               "pool",
+			masterName:         "cache",
+			poolRef:            &SentinelPoolReference{Name: "sentinels"},
+			expectedMasterName: "cache",
+		},
+		{
+			name:          "pool without master name",
+			poolRef:       &SentinelPoolReference{Name: "sentinels"},
+			expectedError: "sentinel poolRef requires a masterName",
+		},
+		{
+			name:          "pool without name",
+			masterName:    "cache",
+			poolRef:       &SentinelPoolReference{},
+			expectedError: "sentinel poolRef requires a name",
+		},
+		{
+			name:          "pool pointing to itself",
+			masterName:    "cache",
+			poolRef:       &SentinelPoolReference{Name: "test"},
+			expectedError: "sentinel poolRef can't point to the RedisFailover itself",
+		},
+		{
+			name:          "pool with shared group",
+			masterName:    "cache",
+			sharedGroup:   &SharedSentinelGroup{Addresses: []string{"s1"}},
+			poolRef:       &SentinelPoolReference{Name: "sentinels"},
+			expectedError: "sentinel poolRef can't be used with sharedGroup",
+		},
+		{
+			name:          "pool when bootstrapping",
+			masterName:    "cache",
+			poolRef:       &SentinelPoolReference{Name: "sentinels"},
+			bootstrapNode: &BootstrapSettings{Host: "127.0.0.1", AllowSentinels: true},
+			expectedError: "sentinel poolRef can't be used with bootstrapNode",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", test.bootstrapNode)
+			rf.Spec.Sentinel.MasterName = test.masterName
+			rf.Spec.Sentinel.SharedGroup = test.sharedGroup
+			rf.Spec.Sentinel.PoolRef = test.poolRef
+
+			err := rf.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+				assert.Equal(test.expectedMasterName, rf.Spec.Sentinel.MasterName)
+				if test.sharedGroup != nil {
+					assert.Equal(test.expectedQuorum, rf.Spec.Sentinel.SharedGroup.Quorum)
+				}
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateDriftPolicy(t *testing.T) {
+	tests := []struct {
+		name                string
+		rfDriftPolicy       DriftPolicy
+		expectedDriftPolicy DriftPolicy
+		expectedError       string
+	}{
+		{
+			name:                "defaults to Report",
+			expectedDriftPolicy: DriftPolicyReport,
+		},
+		{
+			name:                "Enforce",
+			rfDriftPolicy:       DriftPolicyEnforce,
+			expectedDriftPolicy: DriftPolicyEnforce,
+		},
+		{
+			name:          "unsupported policy",
+			rfDriftPolicy: "Ignore",
+			expectedError: `unsupported driftPolicy "Ignore"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.DriftPolicy = test.rfDriftPolicy
+
+			err := rf.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+				assert.Equal(test.expectedDriftPolicy, rf.Spec.DriftPolicy)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidatePersistence(t *testing.T) {
+	tests := []struct {
+		name                 string
+		persistence          *RedisPersistence
+		keepAfterDeletion    bool
+		expectedCustomConfig []string
+		expectedError        string
+	}{
+		{
+			name:                 "not set",
+			expectedCustomConfig: []string{"replica-priority 100"},
+		},
+		{
+			name:        "RDB",
+			persistence: &RedisPersistence{Mode: PersistenceModeRDB, RDBSchedule: []RDBSaveRule{{Seconds: 60, Changes: 1000}}},
+			expectedCustomConfig: []string{
+				"replica-priority 100",
+				"save 60 1000",
+				"appendonly no",
+			},
+		},
+		{
+			name:        "Hybrid",
+			persistence: &RedisPersistence{Mode: PersistenceModeHybrid, AppendFsync: "always"},
+			expectedCustomConfig: []string{
+				"replica-priority 100",
+				"save 900 1 300 10",
+				"appendonly yes",
+				"appendfsync always",
+				"aof-use-rdb-preamble yes",
+			},
+		},
+		{
+			name:        "None",
+			persistence: &RedisPersistence{Mode: PersistenceModeNone},
+			expectedCustomConfig: []string{
+				"replica-priority 100",
+				`save ""`,
+				"appendonly no",
+			},
+		},
+		{
+			name:              "None keeping the storage after deletion",
+			persistence:       &RedisPersistence{Mode: PersistenceModeNone},
+			keepAfterDeletion: true,
+			expectedError:     "persistence can't be disabled while retaining the redis storage, the retained volumes wouldn't have any data",
+		},
+		{
+			name:          "unsupported mode",
+			persistence:   &RedisPersistence{Mode: "Snapshot"},
+			expectedError: `unsupported persistence mode "Snapshot"`,
+		},
+		{
+			name:          "RDB schedule without RDB",
+			persistence:   &RedisPersistence{Mode: PersistenceModeAOF, RDBSchedule: []RDBSaveRule{{Seconds: 60, Changes: 1000}}},
+			expectedError: "persistence rdbSchedule can't be used with mode AOF",
+		},
+		{
+			name:          "append fsync without AOF",
+			persistence:   &RedisPersistence{Mode: PersistenceModeRDB, AppendFsync: "always"},
+			expectedError: "persistence appendFsync and aofUseRdbPreamble can't be used with mode RDB",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.Persistence = test.persistence
+			rf.Spec.Redis.Storage.KeepAfterDeletion = test.keepAfterDeletion
+
+			err := rf.Validate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+				assert.Equal(test.expectedCustomConfig, rf.Spec.Redis.CustomConfig)
+			} else {
+				assert.EqualError(err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name             string
+		persistence      *RedisPersistence
+		storage          RedisStorage
+		monitoring       MonitoringSettings
+		networkPolicy    NetworkPolicySettings
+		sharedGroup      *SharedSentinelGroup
+		command          []string
+		expectedWarnings []string
+	}{
+		{
+			name:             "persistence not set",
+			expectedWarnings: []string{},
+		},
+		{
+			name:        "persistence on an emptyDir",
+			persistence: &RedisPersistence{Mode: PersistenceModeAOF},
+			expectedWarnings: []string{
+				"persistence mode AOF is enabled on an emptyDir volume, the data is lost when a pod is deleted",
+			},
+		},
+		{
+			name:             "persistence on a persistent volume claim",
+			persistence:      &RedisPersistence{Mode: PersistenceModeAOF},
+			storage:          RedisStorage{PersistentVolumeClaim: &EmbeddedPersistentVolumeClaim{}},
+			expectedWarnings: []string{},
+		},
+		{
+			name:       "monitoring without the redis exporter",
+			monitoring: MonitoringSettings{Enabled: true},
+			expectedWarnings: []string{
+				"monitoring is enabled without the redis exporter, the alerts have no metrics to fire on",
+			},
+		},
+		{
+			name:          "network policy with shared sentinels",
+			networkPolicy: NetworkPolicySettings{Enabled: true},
+			sharedGroup:   &SharedSentinelGroup{Addresses: []string{"10.0.0.1"}},
+			expectedWarnings: []string{
+				"the network policy doesn't allow the shared sentinels, they must be added to its clients",
+			},
+		},
+		{
+			name:    "custom redis command",
+			command: []string{"redis-server", "/redis/redis.conf"},
+			expectedWarnings: []string{
+				"the custom redis command must drop the slaveof and replicaof lines of /redis/redis.conf, otherwise a restarted master starts as a master",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.Persistence = test.persistence
+			rf.Spec.Redis.Storage = test.storage
+			rf.Spec.Monitoring = test.monitoring
+			rf.Spec.Redis.Command = test.command
+			rf.Spec.NetworkPolicy = test.networkPolicy
+			if test.sharedGroup != nil {
+				rf.Spec.Sentinel.SharedGroup = test.sharedGroup
+				rf.Spec.Sentinel.MasterName = "shared"
+			}
+
+			assert.NoError(rf.Validate())
+			assert.Equal(test.expectedWarnings, rf.Warnings())
+		})
+	}
+}
@@ -16,6 +16,10 @@ func (c *FakeDatabasesV1) RedisFailovers(namespace string) v1.RedisFailoverInter
 	return &FakeRedisFailovers{c, namespace}
 }
 
+func (c *FakeDatabasesV1) RedisFailoverSnapshots(namespace string) v1.RedisFailoverSnapshotInterface {
+	return &FakeRedisFailoverSnapshots{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeDatabasesV1) RESTClient() rest.Interface {
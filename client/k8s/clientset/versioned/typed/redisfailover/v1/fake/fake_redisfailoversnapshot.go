@@ -0,0 +1,125 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeRedisFailoverSnapshots implements RedisFailoverSnapshotInterface
+type FakeRedisFailoverSnapshots struct {
+	Fake *FakeDatabasesV1
+	ns   string
+}
+
+var redisfailoversnapshotsResource = v1.SchemeGroupVersion.WithResource("redisfailoversnapshots")
+
+var redisfailoversnapshotsKind = v1.SchemeGroupVersion.WithKind("RedisFailoverSnapshot")
+
+// Get takes name of the redisFailoverSnapshot, and returns the corresponding redisFailoverSnapshot object, and an error if there is any.
+func (c *FakeRedisFailoverSnapshots) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(redisfailoversnapshotsResource, c.ns, name), &v1.RedisFailoverSnapshot{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverSnapshot), err
+}
+
+// List takes label and field selectors, and returns the list of RedisFailoverSnapshots that match those selectors.
+func (c *FakeRedisFailoverSnapshots) List(ctx context.Context, opts metav1.ListOptions) (result *v1.RedisFailoverSnapshotList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(redisfailoversnapshotsResource, redisfailoversnapshotsKind, c.ns, opts), &v1.RedisFailoverSnapshotList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1.RedisFailoverSnapshotList{ListMeta: obj.(*v1.RedisFailoverSnapshotList).ListMeta}
+	for _, item := range obj.(*v1.RedisFailoverSnapshotList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested redisFailoverSnapshots.
+func (c *FakeRedisFailoverSnapshots) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(redisfailoversnapshotsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a redisFailoverSnapshot and creates it.  Returns the server's representation of the redisFailoverSnapshot, and an error, if there is any.
+func (c *FakeRedisFailoverSnapshots) Create(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.CreateOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(redisfailoversnapshotsResource, c.ns, redisFailoverSnapshot), &v1.RedisFailoverSnapshot{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverSnapshot), err
+}
+
+// Update takes the representation of a redisFailoverSnapshot and updates it. Returns the server's representation of the redisFailoverSnapshot, and an error, if there is any.
+func (c *FakeRedisFailoverSnapshots) Update(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.UpdateOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(redisfailoversnapshotsResource, c.ns, redisFailoverSnapshot), &v1.RedisFailoverSnapshot{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverSnapshot), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRedisFailoverSnapshots) UpdateStatus(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.UpdateOptions) (*v1.RedisFailoverSnapshot, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(redisfailoversnapshotsResource, "status", c.ns, redisFailoverSnapshot), &v1.RedisFailoverSnapshot{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverSnapshot), err
+}
+
+// Delete takes name of the redisFailoverSnapshot and deletes it. Returns an error if one occurs.
+func (c *FakeRedisFailoverSnapshots) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(redisfailoversnapshotsResource, c.ns, name, opts), &v1.RedisFailoverSnapshot{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRedisFailoverSnapshots) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(redisfailoversnapshotsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1.RedisFailoverSnapshotList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched redisFailoverSnapshot.
+func (c *FakeRedisFailoverSnapshots) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.RedisFailoverSnapshot, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(redisfailoversnapshotsResource, c.ns, name, pt, data, subresources...), &v1.RedisFailoverSnapshot{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverSnapshot), err
+}
@@ -3,3 +3,5 @@
 package v1
 
 type RedisFailoverExpansion interface{}
+
+type RedisFailoverSnapshotExpansion interface{}
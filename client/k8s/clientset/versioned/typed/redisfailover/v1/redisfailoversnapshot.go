@@ -0,0 +1,179 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	scheme "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RedisFailoverSnapshotsGetter has a method to return a RedisFailoverSnapshotInterface.
+// A group's client should implement this interface.
+type RedisFailoverSnapshotsGetter interface {
+	RedisFailoverSnapshots(namespace string) RedisFailoverSnapshotInterface
+}
+
+// RedisFailoverSnapshotInterface has methods to work with RedisFailoverSnapshot resources.
+type RedisFailoverSnapshotInterface interface {
+	Create(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.CreateOptions) (*v1.RedisFailoverSnapshot, error)
+	Update(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.UpdateOptions) (*v1.RedisFailoverSnapshot, error)
+	UpdateStatus(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.UpdateOptions) (*v1.RedisFailoverSnapshot, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.RedisFailoverSnapshot, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.RedisFailoverSnapshotList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.RedisFailoverSnapshot, err error)
+	RedisFailoverSnapshotExpansion
+}
+
+// redisFailoverSnapshots implements RedisFailoverSnapshotInterface
+type redisFailoverSnapshots struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRedisFailoverSnapshots returns a RedisFailoverSnapshots
+func newRedisFailoverSnapshots(c *DatabasesV1Client, namespace string) *redisFailoverSnapshots {
+	return &redisFailoverSnapshots{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the redisFailoverSnapshot, and returns the corresponding redisFailoverSnapshot object, and an error if there is any.
+func (c *redisFailoverSnapshots) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	result = &v1.RedisFailoverSnapshot{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RedisFailoverSnapshots that match those selectors.
+func (c *redisFailoverSnapshots) List(ctx context.Context, opts metav1.ListOptions) (result *v1.RedisFailoverSnapshotList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.RedisFailoverSnapshotList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested redisFailoverSnapshots.
+func (c *redisFailoverSnapshots) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a redisFailoverSnapshot and creates it.  Returns the server's representation of the redisFailoverSnapshot, and an error, if there is any.
+func (c *redisFailoverSnapshots) Create(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.CreateOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	result = &v1.RedisFailoverSnapshot{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisFailoverSnapshot).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a redisFailoverSnapshot and updates it. Returns the server's representation of the redisFailoverSnapshot, and an error, if there is any.
+func (c *redisFailoverSnapshots) Update(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.UpdateOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	result = &v1.RedisFailoverSnapshot{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		Name(redisFailoverSnapshot.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisFailoverSnapshot).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *redisFailoverSnapshots) UpdateStatus(ctx context.Context, redisFailoverSnapshot *v1.RedisFailoverSnapshot, opts metav1.UpdateOptions) (result *v1.RedisFailoverSnapshot, err error) {
+	result = &v1.RedisFailoverSnapshot{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		Name(redisFailoverSnapshot.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisFailoverSnapshot).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the redisFailoverSnapshot and deletes it. Returns an error if one occurs.
+func (c *redisFailoverSnapshots) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *redisFailoverSnapshots) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched redisFailoverSnapshot.
+func (c *redisFailoverSnapshots) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.RedisFailoverSnapshot, err error) {
+	result = &v1.RedisFailoverSnapshot{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("redisfailoversnapshots").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+func runCli(p *plugin, opts *options, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected the name of a RedisFailover")
+	}
+	name, cliArgs := args[0], args[1:]
+	rf, err := p.getRedisFailover(name)
+	if err != nil {
+		return err
+	}
+
+	pod, container := opts.pod, redisContainer
+	var command []string
+	if pod == "" {
+		redises, err := p.redisStates(rf)
+		if err != nil {
+			return err
+		}
+		for _, redis := range redises {
+			if redis.isMaster() {
+				pod = redis.pod
+				break
+			}
+		}
+		if pod == "" {
+			return fmt.Errorf("no redis master found for redisfailover %s, choose a pod with --pod", name)
+		}
+	} else if rf.SentinelsAllowed() {
+		sentinels, err := p.sentinels(rf)
+		if err != nil {
+			return err
+		}
+		for _, s := range sentinels {
+			if s.pod == pod && s.container == sentinelContainer {
+				container = sentinelContainer
+				command = sentinelCommand(s, cliArgs...)
+			}
+		}
+	}
+	if command == nil {
+		command = redisCommand(rf, cliArgs...)
+	}
+
+	streams := execStreams{stdout: p.stdout, stderr: p.stderr}
+	if len(cliArgs) == 0 {
+		streams.stdin = p.stdin
+		if f, ok := p.stdin.(*os.File); ok {
+			streams.terminal = newTerminal(f)
+		}
+	}
+	return p.executor.Exec(context.Background(), rf.Namespace, pod, container, command, streams)
+}
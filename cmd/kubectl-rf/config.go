@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+// configDifference is a parameter of the custom configuration whose running value is different
+type configDifference struct {
+	parameter  string
+	configured string
+	running    string
+}
+
+func runConfig(p *plugin, _ *options, args []string) error {
+	if len(args) == 0 || args[0] != "diff" {
+		return fmt.Errorf("expected the diff subcommand, like \"kubectl rf config diff NAME\"")
+	}
+	name, err := requireName(args[1:])
+	if err != nil {
+		return err
+	}
+	rf, err := p.getRedisFailover(name)
+	if err != nil {
+		return err
+	}
+
+	differs := false
+	report := func(node string, current string, err error, configs []string) {
+		if err != nil {
+			fmt.Fprintf(p.stdout, "%s\n  <error: %s>\n", node, err)
+			differs = true
+			return
+		}
+		differences := configDifferences(parseConfigPairs(current), configs)
+		if len(differences) == 0 {
+			return
+		}
+		differs = true
+		fmt.Fprintf(p.stdout, "%s\n", node)
+		for _, d := range differences {
+			fmt.Fprintf(p.stdout, "  %s: configured %q, running %q\n", d.parameter, d.configured, d.running)
+		}
+	}
+
+	pods, err := p.redisPods(rf)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		current, err := p.redis(rf, pod.Name, "CONFIG", "GET", "*")
+		report(pod.Name, current, err, rf.Spec.Redis.CustomConfig)
+	}
+
+	if rf.DeploySentinels() {
+		sentinels, err := p.sentinels(rf)
+		if err != nil {
+			return err
+		}
+		for _, s := range sentinels {
+			current, err := p.sentinel(rf, s, "SENTINEL", "MASTER", rfservice.GetSentinelMasterName(rf))
+			report(s.name, current, err, rf.Spec.Sentinel.CustomConfig)
+		}
+	}
+
+	if differs {
+		return errDiffers
+	}
+	fmt.Fprintf(p.stdout, "The running configuration of redisfailover %s matches its custom configuration\n", rf.Name)
+	return nil
+}
+
+// parseConfigPairs parses the parameter and value lines of CONFIG GET and SENTINEL MASTER, as redis-cli
+// prints them when not run in a terminal
+func parseConfigPairs(output string) map[string]string {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	config := make(map[string]string, len(lines)/2)
+	for i := 0; i+1 < len(lines); i += 2 {
+		config[strings.ToLower(lines[i])] = lines[i+1]
+	}
+	return config
+}
+
+// configDifferences returns the parameters of the custom configuration whose current value is different,
+// the ones that are not reported are ignored as the operator does. The repeated parameters are compared
+// with all their values, as redis reports them.
+func configDifferences(current map[string]string, configs []string) []configDifference {
+	differences := []configDifference{}
+	for _, config := range redis.GroupConfig(configs) {
+		parameter, value, ok := splitConfig(config)
+		if !ok {
+			continue
+		}
+		running, reported := current[strings.ToLower(parameter)]
+		if !reported || redis.SameConfigValue(parameter, running, value) {
+			continue
+		}
+		differences = append(differences, configDifference{parameter: parameter, configured: value, running: running})
+	}
+	return differences
+}
+
+// splitConfig splits an entry of the custom configuration in its parameter and value
+func splitConfig(config string) (string, string, bool) {
+	parameter, value, ok := strings.Cut(strings.TrimSpace(config), " ")
+	if !ok || parameter == "" {
+		return "", "", false
+	}
+	if value == `""` {
+		value = ""
+	}
+	return parameter, value, true
+}
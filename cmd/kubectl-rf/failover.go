@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func runFailover(p *plugin, _ *options, args []string) error {
+	name, err := requireName(args)
+	if err != nil {
+		return err
+	}
+	rf, err := p.getRedisFailover(name)
+	if err != nil {
+		return err
+	}
+	if rf.Bootstrapping() {
+		return fmt.Errorf("redisfailover %s is bootstrapping from %s, its master is not failed over by sentinels", name, rf.Spec.BootstrapNode.Host)
+	}
+	sentinels, err := p.sentinels(rf)
+	if err != nil {
+		return err
+	}
+
+	// A single sentinel starts the failover, the rest of them follow it
+	masterName := rfservice.GetSentinelMasterName(rf)
+	for _, s := range sentinels {
+		if _, err := p.sentinel(rf, s, "SENTINEL", "FAILOVER", masterName); err != nil {
+			fmt.Fprintf(p.stderr, "sentinel %s: %s\n", s.name, err)
+			continue
+		}
+		fmt.Fprintf(p.stdout, "Failover of master %s started by sentinel %s, follow it with \"kubectl rf status %s\"\n", masterName, s.name, name)
+		return nil
+	}
+	return fmt.Errorf("no sentinel could start the failover of master %s", masterName)
+}
@@ -0,0 +1,149 @@
+// kubectl-rf is a kubectl plugin with the operations to inspect and fix the RedisFailovers by hand. Installed
+// in the PATH, it runs as `kubectl rf`.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// errDiffers is returned by the commands that compare, like `config diff`, when they find differences, so
+// the plugin exits with 1 without reporting an error
+var errDiffers = errors.New("differences found")
+
+// command is a subcommand of the plugin
+type command struct {
+	usage       string
+	description string
+	run         func(p *plugin, opts *options, args []string) error
+}
+
+var commands = map[string]command{
+	"status": {
+		usage:       "status NAME",
+		description: "Show the role, replication offset and sentinel view of every pod",
+		run:         runStatus,
+	},
+	"sentinels": {
+		usage:       "sentinels NAME",
+		description: "Compare the master every sentinel monitors",
+		run:         runSentinels,
+	},
+	"failover": {
+		usage:       "failover NAME",
+		description: "Ask the sentinels to fail over the master",
+		run:         runFailover,
+	},
+	"pause": {
+		usage:       "pause NAME [--until TIME | --for DURATION]",
+		description: "Pause the healing of the operator",
+		run:         runPause,
+	},
+	"resume": {
+		usage:       "resume NAME",
+		description: "Resume the healing paused with pause",
+		run:         runResume,
+	},
+	"config": {
+		usage:       "config diff NAME",
+		description: "Compare the custom configuration with the running one of every pod",
+		run:         runConfig,
+	},
+	"cli": {
+		usage:       "cli NAME [--pod POD] [-- ARGS]",
+		description: "Run redis-cli against the master, or the given pod, with the password of the redis container",
+		run:         runCli,
+	},
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, errDiffers) {
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		return nil
+	}
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		usage(stderr)
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	opts := &options{}
+	flags := flag.NewFlagSet("kubectl rf "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: kubectl rf %s\n\n%s.\n\nFlags:\n", cmd.usage, cmd.description)
+		flags.PrintDefaults()
+	}
+	opts.register(flags, name)
+	positional, err := parseInterspersed(flags, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p, err := newPlugin(opts, stdin, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	return cmd.run(p, opts, positional)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "kubectl rf inspects and fixes the RedisFailovers by hand.\n\nUsage:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  kubectl rf %-45s %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintf(w, "\nUse \"kubectl rf COMMAND --help\" for the flags of a command.\n")
+}
+
+// parseInterspersed parses the flags placed anywhere among the positional arguments, as kubectl does, and
+// returns the positional ones. The arguments after -- are positional.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// requireName returns the name of the RedisFailover, the only positional argument of most commands
+func requireName(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected the name of a RedisFailover, got %q", strings.Join(args, " "))
+	}
+	return args[0], nil
+}
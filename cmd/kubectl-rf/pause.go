@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+)
+
+func runPause(p *plugin, opts *options, args []string) error {
+	name, err := requireName(args)
+	if err != nil {
+		return err
+	}
+	value := "true"
+	switch {
+	case opts.until != "" && opts.duration != 0:
+		return fmt.Errorf("--until and --for can't be used together")
+	case opts.until != "":
+		until, err := time.Parse(time.RFC3339, opts.until)
+		if err != nil {
+			return fmt.Errorf("--until must be a RFC 3339 time: %w", err)
+		}
+		value = until.Format(time.RFC3339)
+	case opts.duration < 0:
+		return fmt.Errorf("--for must be positive")
+	case opts.duration > 0:
+		value = time.Now().Add(opts.duration).UTC().Format(time.RFC3339)
+	}
+
+	rf, err := p.k8sService.GetRedisFailover(context.TODO(), p.namespace, name)
+	if err != nil {
+		return err
+	}
+	rf = rf.DeepCopy()
+	if rf.Annotations == nil {
+		rf.Annotations = map[string]string{}
+	}
+	rf.Annotations[redisfailoverv1.PausedAnnotation] = value
+	if _, err := p.k8sService.UpdateRedisFailover(context.TODO(), rf); err != nil {
+		return err
+	}
+
+	if value == "true" {
+		fmt.Fprintf(p.stdout, "redisfailover %s paused, resume it with \"kubectl rf resume %s\"\n", name, name)
+	} else {
+		fmt.Fprintf(p.stdout, "redisfailover %s paused until %s\n", name, value)
+	}
+	return nil
+}
+
+func runResume(p *plugin, _ *options, args []string) error {
+	name, err := requireName(args)
+	if err != nil {
+		return err
+	}
+	rf, err := p.k8sService.GetRedisFailover(context.TODO(), p.namespace, name)
+	if err != nil {
+		return err
+	}
+	if _, ok := rf.Annotations[redisfailoverv1.PausedAnnotation]; ok {
+		rf = rf.DeepCopy()
+		delete(rf.Annotations, redisfailoverv1.PausedAnnotation)
+		if rf, err = p.k8sService.UpdateRedisFailover(context.TODO(), rf); err != nil {
+			return err
+		}
+	}
+
+	if rf.Paused(time.Now()) {
+		fmt.Fprintf(p.stdout, "redisfailover %s is still paused by its spec, set paused to false in it to resume it\n", name)
+		return nil
+	}
+	fmt.Fprintf(p.stdout, "redisfailover %s resumed\n", name)
+	return nil
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/remotecommand"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverclientset "github.com/spotahome/redis-operator/client/k8s/clientset/versioned"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	redisContainer    = "redis"
+	sentinelContainer = "sentinel"
+	sentinelPort      = "26379"
+
+	// redisCliScript runs redis-cli with the password of the container environment, so it isn't in the
+	// command line
+	redisCliScript = `if [ -n "${REDIS_PASSWORD}" ]; then export REDISCLI_AUTH="${REDIS_PASSWORD}"; fi; exec redis-cli -p %d "$@"`
+)
+
+// options are the flags of the plugin
+type options struct {
+	kubeConfig string
+	context    string
+	namespace  string
+
+	// pause
+	until    string
+	duration time.Duration
+	// cli
+	pod string
+}
+
+func (o *options) register(flags *flag.FlagSet, command string) {
+	flags.StringVar(&o.kubeConfig, "kubeconfig", "", "Path to the kubeconfig file")
+	flags.StringVar(&o.context, "context", "", "The kubeconfig context to use")
+	flags.StringVar(&o.namespace, "namespace", "", "Namespace of the RedisFailover")
+	flags.StringVar(&o.namespace, "n", "", "Namespace of the RedisFailover (shorthand)")
+	switch command {
+	case "pause":
+		flags.StringVar(&o.until, "until", "", "Pause until the given time, in RFC 3339 format")
+		flags.DurationVar(&o.duration, "for", 0, "Pause for the given duration")
+	case "cli":
+		flags.StringVar(&o.pod, "pod", "", "Pod to connect to instead of the master, a redis or a sentinel")
+	}
+}
+
+// podExecutor runs commands in the containers of the pods
+type podExecutor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string, streams execStreams) error
+}
+
+// execStreams are the streams of a command run in a container. With a terminal the stderr is merged in the
+// stdout.
+type execStreams struct {
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	terminal *terminal
+}
+
+// plugin has the clients used by the commands
+type plugin struct {
+	k8sService k8s.Services
+	executor   podExecutor
+	namespace  string
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+func newPlugin(opts *options, stdin io.Reader, stdout, stderr io.Writer) (*plugin, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.kubeConfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: opts.context,
+		Context:        clientcmdapi.Context{Namespace: opts.namespace},
+	})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not load configuration: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	rfClient, err := redisfailoverclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin{
+		k8sService: k8s.New(k8sClient, rfClient, nil, nil, log.Dummy, metrics.Dummy),
+		executor:   &remoteExecutor{config: config, client: k8sClient},
+		namespace:  namespace,
+		stdin:      stdin,
+		stdout:     stdout,
+		stderr:     stderr,
+	}, nil
+}
+
+// getRedisFailover returns the RedisFailover with its defaults set
+func (p *plugin) getRedisFailover(name string) (*redisfailoverv1.RedisFailover, error) {
+	rf, err := p.k8sService.GetRedisFailover(context.TODO(), p.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	rf = rf.DeepCopy()
+	if err := rf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid redisfailover %s: %w", name, err)
+	}
+	return rf, nil
+}
+
+// redisPods returns the running redis pods of the RedisFailover
+func (p *plugin) redisPods(rf *redisfailoverv1.RedisFailover) ([]corev1.Pod, error) {
+	pods, err := p.k8sService.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf))
+	if err != nil {
+		return nil, err
+	}
+	return runningPods(pods.Items), nil
+}
+
+// sentinel is a sentinel monitoring the redis of a RedisFailover, reached through the pod and container
+// the commands are run in
+type sentinel struct {
+	name      string
+	pod       string
+	container string
+	host      string
+}
+
+// sentinels returns the sentinels monitoring the redis of the RedisFailover: its own, the ones of its pool or
+// the ones of its shared group, reached from a redis pod
+func (p *plugin) sentinels(rf *redisfailoverv1.RedisFailover) ([]sentinel, error) {
+	if rf.Spec.Sentinel.SharedGroup != nil && rf.SharedSentinels() {
+		redises, err := p.redisPods(rf)
+		if err != nil {
+			return nil, err
+		}
+		if len(redises) == 0 {
+			return nil, fmt.Errorf("no running redis pod to reach the shared sentinels from")
+		}
+		sentinels := []sentinel{}
+		for _, address := range rf.Spec.Sentinel.SharedGroup.Addresses {
+			sentinels = append(sentinels, sentinel{name: address, pod: redises[0].Name, container: redisContainer, host: address})
+		}
+		return sentinels, nil
+	}
+
+	var name string
+	switch {
+	case rf.PooledSentinels():
+		name = rfservice.GetSentinelPoolName(rf)
+	case rf.DeploySentinels():
+		name = rfservice.GetSentinelName(rf)
+	default:
+		return nil, fmt.Errorf("redisfailover %s has no sentinels", rf.Name)
+	}
+	pods, err := p.k8sService.GetDeploymentPods(rf.Namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	sentinels := []sentinel{}
+	for _, pod := range runningPods(pods.Items) {
+		sentinels = append(sentinels, sentinel{name: pod.Name, pod: pod.Name, container: sentinelContainer})
+	}
+	return sentinels, nil
+}
+
+func runningPods(pods []corev1.Pod) []corev1.Pod {
+	running := []corev1.Pod{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	return running
+}
+
+// redisCommand returns the command line to run a redis command against the redis of the pod it runs in,
+// authenticated with the password the container gets from the auth secret
+func redisCommand(rf *redisfailoverv1.RedisFailover, args ...string) []string {
+	script := fmt.Sprintf(redisCliScript, rf.Spec.Redis.Port)
+	return append([]string{"sh", "-c", script, "--"}, args...)
+}
+
+// sentinelCommand returns the redis-cli command line to run a command against the sentinel
+func sentinelCommand(s sentinel, args ...string) []string {
+	command := []string{"redis-cli", "-p", sentinelPort}
+	if s.host != "" {
+		command = append(command, "-h", s.host)
+	}
+	return append(command, args...)
+}
+
+// redis runs a redis command on the redis of the pod and returns its output
+func (p *plugin) redis(rf *redisfailoverv1.RedisFailover, pod string, args ...string) (string, error) {
+	return p.output(rf.Namespace, pod, redisContainer, redisCommand(rf, args...))
+}
+
+// sentinel runs a sentinel command on the sentinel and returns its output
+func (p *plugin) sentinel(rf *redisfailoverv1.RedisFailover, s sentinel, args ...string) (string, error) {
+	return p.output(rf.Namespace, s.pod, s.container, sentinelCommand(s, args...))
+}
+
+// output runs a redis-cli command and returns its output. redis-cli reports the errors of the commands on the
+// output when it's not a terminal, so they are returned as errors.
+func (p *plugin) output(namespace, pod, container string, command []string) (string, error) {
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	err := p.executor.Exec(context.TODO(), namespace, pod, container, command, execStreams{stdout: stdout, stderr: stderr})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", pod, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", pod, err)
+	}
+	out := strings.ReplaceAll(stdout.String(), "\r\n", "\n")
+	for _, prefix := range []string{"ERR", "NOAUTH", "WRONGPASS", "Could not connect"} {
+		if strings.HasPrefix(out, prefix) {
+			return "", fmt.Errorf("%s: %s", pod, strings.TrimSpace(out))
+		}
+	}
+	return out, nil
+}
+
+// remoteExecutor runs the commands through the exec subresource of the pods, as kubectl exec does
+type remoteExecutor struct {
+	config *rest.Config
+	client kubernetes.Interface
+}
+
+func (e *remoteExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string, streams execStreams) error {
+	req := e.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     streams.stdin != nil,
+			Stdout:    true,
+			Stderr:    streams.terminal == nil,
+			TTY:       streams.terminal != nil,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdin:  streams.stdin,
+		Stdout: streams.stdout,
+	}
+	if streams.terminal == nil {
+		options.Stderr = streams.stderr
+		return executor.StreamWithContext(ctx, options)
+	}
+	options.Tty = true
+	options.TerminalSizeQueue = streams.terminal
+	return streams.terminal.run(func() error {
+		return executor.StreamWithContext(ctx, options)
+	})
+}
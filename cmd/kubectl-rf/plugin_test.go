@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	namespace = "testns"
+	name      = "test"
+)
+
+// fakeExecutor returns the output set for the commands run in every pod, by the last arguments of the command
+type fakeExecutor struct {
+	outputs  map[string]string
+	commands []string
+}
+
+func (f *fakeExecutor) Exec(_ context.Context, _, pod, container string, command []string, streams execStreams) error {
+	f.commands = append(f.commands, pod+"/"+container+": "+strings.Join(command, " "))
+	for args, output := range f.outputs {
+		key, cmd, _ := strings.Cut(args, ": ")
+		if key == pod && strings.HasSuffix(strings.Join(command, " "), cmd) {
+			_, err := io.WriteString(streams.stdout, output)
+			return err
+		}
+	}
+	return fmt.Errorf("unexpected command in %s: %s", pod, strings.Join(command, " "))
+}
+
+func newTestPlugin(executor podExecutor, rf *redisfailoverv1.RedisFailover) (*plugin, *bytes.Buffer) {
+	labels := map[string]string{"app.kubernetes.io/name": name}
+	pod := func(name, ip string, component string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app.kubernetes.io/name": "test", "app.kubernetes.io/component": component}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+	}
+	selector := func(component string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": "test", "app.kubernetes.io/component": component}}
+	}
+	kcli := kubernetes.NewSimpleClientset(
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Namespace: namespace, Labels: labels}, Spec: appsv1.StatefulSetSpec{Selector: selector("redis")}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rfs-test", Namespace: namespace, Labels: labels}, Spec: appsv1.DeploymentSpec{Selector: selector("sentinel")}},
+		pod("rfr-test-0", "10.0.0.1", "redis"),
+		pod("rfr-test-1", "10.0.0.2", "redis"),
+		pod("rfs-test-a", "10.0.1.1", "sentinel"),
+		pod("rfs-test-b", "10.0.1.2", "sentinel"),
+	)
+	stdout := &bytes.Buffer{}
+	return &plugin{
+		k8sService: k8s.New(kcli, redisfailoverfake.NewSimpleClientset(rf), nil, nil, log.Dummy, metrics.Dummy),
+		executor:   executor,
+		namespace:  namespace,
+		stdout:     stdout,
+		stderr:     io.Discard,
+	}, stdout
+}
+
+func newTestRedisFailover() *redisfailoverv1.RedisFailover {
+	return &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Redis: redisfailoverv1.RedisSettings{CustomConfig: []string{"maxmemory 1gb"}},
+		},
+	}
+}
+
+const (
+	masterReplication = "# Replication\r\nrole:master\r\nconnected_slaves:1\r\nmaster_repl_offset:120\r\n"
+	slaveReplication  = "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:up\r\nslave_repl_offset:100\r\n"
+)
+
+func sentinelInfo(address string) string {
+	return "# Sentinel\r\nsentinel_masters:1\r\nmaster0:name=mymaster,status=ok,address=" + address + ",slaves=1,sentinels=2\r\n"
+}
+
+func TestStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		"rfr-test-0: INFO replication": masterReplication,
+		"rfr-test-1: INFO replication": slaveReplication,
+		"rfs-test-a: INFO sentinel":    sentinelInfo("10.0.0.1:6379"),
+		"rfs-test-b: INFO sentinel":    sentinelInfo("10.0.0.1:6379"),
+	}}
+	p, stdout := newTestPlugin(executor, newTestRedisFailover())
+
+	assert.NoError(runStatus(p, &options{}, []string{name}))
+	assert.Regexp(`rfr-test-0\s+10.0.0.1\s+master\s+-\s+-\s+120\s+-`, stdout.String())
+	assert.Regexp(`rfr-test-1\s+10.0.0.2\s+slave\s+10.0.0.1:6379\s+up\s+100\s+20`, stdout.String())
+	assert.Regexp(`rfs-test-a\s+10.0.0.1:6379\s+ok\s+1\s+2`, stdout.String())
+	assert.Contains(stdout.String(), "All the sentinels agree on 10.0.0.1:6379 as master mymaster")
+}
+
+func TestSentinelsDisagree(t *testing.T) {
+	assert := assert.New(t)
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		"rfs-test-a: INFO sentinel": sentinelInfo("10.0.0.1:6379"),
+		"rfs-test-b: INFO sentinel": sentinelInfo("10.0.0.2:6379"),
+	}}
+	p, stdout := newTestPlugin(executor, newTestRedisFailover())
+
+	assert.ErrorIs(runSentinels(p, &options{}, []string{name}), errDiffers)
+	assert.Contains(stdout.String(), "The sentinels don't agree on master mymaster: 10.0.0.1:6379 (rfs-test-a), 10.0.0.2:6379 (rfs-test-b)")
+}
+
+func TestConfigDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	sentinelMaster := "name\nmymaster\ndown-after-milliseconds\n5000\nfailover-timeout\n10000\nparallel-syncs\n2\n"
+	executor := &fakeExecutor{outputs: map[string]string{
+		"rfr-test-0: CONFIG GET *":             "maxmemory\n1073741824\nreplica-priority\n100\n",
+		"rfr-test-1: CONFIG GET *":             "maxmemory\n2147483648\nreplica-priority\n100\n",
+		"rfs-test-a: SENTINEL MASTER mymaster": sentinelMaster,
+		"rfs-test-b: SENTINEL MASTER mymaster": strings.Replace(sentinelMaster, "5000", "1000", 1),
+	}}
+	p, stdout := newTestPlugin(executor, newTestRedisFailover())
+
+	assert.ErrorIs(runConfig(p, &options{}, []string{"diff", name}), errDiffers)
+	assert.Equal(`rfr-test-1
+  maxmemory: configured "1gb", running "2147483648"
+rfs-test-b
+  down-after-milliseconds: configured "5000", running "1000"
+`, stdout.String())
+}
+
+func TestPauseAndResume(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	p, _ := newTestPlugin(&fakeExecutor{}, newTestRedisFailover())
+	get := func() *redisfailoverv1.RedisFailover {
+		rf, err := p.k8sService.GetRedisFailover(context.TODO(), namespace, name)
+		require.NoError(err)
+		return rf
+	}
+
+	require.NoError(runPause(p, &options{}, []string{name}))
+	assert.Equal("true", get().Annotations[redisfailoverv1.PausedAnnotation])
+
+	require.NoError(runPause(p, &options{until: "2030-01-02T03:04:05Z"}, []string{name}))
+	assert.Equal("2030-01-02T03:04:05Z", get().Annotations[redisfailoverv1.PausedAnnotation])
+	assert.True(get().Paused(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.Error(runPause(p, &options{until: "tomorrow"}, []string{name}))
+
+	require.NoError(runResume(p, &options{}, []string{name}))
+	assert.NotContains(get().Annotations, redisfailoverv1.PausedAnnotation)
+}
+
+func TestCliConnectsToTheMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		"rfr-test-0: INFO replication": masterReplication,
+		"rfr-test-1: INFO replication": slaveReplication,
+		"rfr-test-0: GET key":          "value\n",
+	}}
+	p, stdout := newTestPlugin(executor, newTestRedisFailover())
+
+	assert.NoError(runCli(p, &options{}, []string{name, "GET", "key"}))
+	assert.Equal("value\n", stdout.String())
+	assert.Contains(executor.commands, `rfr-test-0/redis: sh -c if [ -n "${REDIS_PASSWORD}" ]; then export REDISCLI_AUTH="${REDIS_PASSWORD}"; fi; exec redis-cli -p 6379 "$@" -- GET key`)
+}
+
+func TestParseInterspersed(t *testing.T) {
+	assert := assert.New(t)
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts := &options{}
+	opts.register(flags, "cli")
+
+	args, err := parseInterspersed(flags, []string{"test", "-n", "other", "--pod", "rfr-test-1", "--", "GET", "-n"})
+	assert.NoError(err)
+	assert.Equal([]string{"test", "GET", "-n"}, args)
+	assert.Equal("other", opts.namespace)
+	assert.Equal("rfr-test-1", opts.pod)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/redis/info"
+)
+
+// sentinelView is the master a sentinel monitors for the RedisFailover
+type sentinelView struct {
+	sentinel string
+	master   info.SentinelMaster
+	found    bool
+	err      error
+}
+
+// sentinelViews returns the master every sentinel monitoring the RedisFailover has
+func (p *plugin) sentinelViews(rf *redisfailoverv1.RedisFailover) ([]sentinelView, error) {
+	sentinels, err := p.sentinels(rf)
+	if err != nil {
+		return nil, err
+	}
+	masterName := rfservice.GetSentinelMasterName(rf)
+	views := make([]sentinelView, 0, len(sentinels))
+	for _, s := range sentinels {
+		view := sentinelView{sentinel: s.name}
+		out, err := p.sentinel(rf, s, "INFO", "sentinel")
+		if err == nil {
+			var parsed *info.Info
+			if parsed, err = info.Parse(out); err == nil {
+				for _, master := range parsed.Sentinel.Masters {
+					if master.Name == masterName {
+						view.master, view.found = master, true
+					}
+				}
+			}
+		}
+		view.err = err
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// writeSentinelViews writes the view of every sentinel and whether they agree, which is returned
+func writeSentinelViews(w io.Writer, rf *redisfailoverv1.RedisFailover, views []sentinelView) bool {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SENTINEL\tMASTER\tSTATUS\tSLAVES\tSENTINELS")
+	masters := map[string][]string{}
+	for _, view := range views {
+		switch {
+		case view.err != nil:
+			fmt.Fprintf(tw, "%s\t<error: %s>\t\t\t\n", view.sentinel, view.err)
+			masters["<error>"] = append(masters["<error>"], view.sentinel)
+		case !view.found:
+			fmt.Fprintf(tw, "%s\t<not monitored>\t\t\t\n", view.sentinel)
+			masters["<not monitored>"] = append(masters["<not monitored>"], view.sentinel)
+		default:
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", view.sentinel, view.master.Address, view.master.Status, view.master.Slaves, view.master.Sentinels)
+			masters[view.master.Address] = append(masters[view.master.Address], view.sentinel)
+		}
+	}
+	tw.Flush()
+
+	masterName := rfservice.GetSentinelMasterName(rf)
+	if len(masters) == 1 && len(masters["<error>"]) == 0 && len(masters["<not monitored>"]) == 0 {
+		for address := range masters {
+			fmt.Fprintf(w, "All the sentinels agree on %s as master %s\n", address, masterName)
+		}
+		return true
+	}
+	if len(views) == 0 {
+		fmt.Fprintf(w, "No running sentinel found\n")
+		return false
+	}
+	addresses := make([]string, 0, len(masters))
+	for address, sentinels := range masters {
+		addresses = append(addresses, fmt.Sprintf("%s (%s)", address, strings.Join(sentinels, ", ")))
+	}
+	sort.Strings(addresses)
+	fmt.Fprintf(w, "The sentinels don't agree on master %s: %s\n", masterName, strings.Join(addresses, ", "))
+	return false
+}
+
+func runSentinels(p *plugin, _ *options, args []string) error {
+	name, err := requireName(args)
+	if err != nil {
+		return err
+	}
+	rf, err := p.getRedisFailover(name)
+	if err != nil {
+		return err
+	}
+	views, err := p.sentinelViews(rf)
+	if err != nil {
+		return err
+	}
+	if !writeSentinelViews(p.stdout, rf, views) {
+		return errDiffers
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"text/tabwriter"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/service/redis/info"
+)
+
+// redisState is the replication state of a redis pod
+type redisState struct {
+	pod         string
+	ip          string
+	replication info.Replication
+	err         error
+}
+
+func (r redisState) isMaster() bool {
+	return r.err == nil && r.replication.IsMaster()
+}
+
+func (r redisState) offset() int64 {
+	if r.isMaster() {
+		return r.replication.MasterReplOffset
+	}
+	return r.replication.SlaveReplOffset
+}
+
+func runStatus(p *plugin, _ *options, args []string) error {
+	name, err := requireName(args)
+	if err != nil {
+		return err
+	}
+	rf, err := p.getRedisFailover(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(p.stdout, "RedisFailover %s/%s\n", rf.Namespace, rf.Name)
+	fmt.Fprintf(p.stdout, "Paused: %t\n", rf.Status.Paused)
+	for _, op := range rf.Status.PendingOperations {
+		fmt.Fprintf(p.stdout, "Pending operation: %s (%s)\n", op.Type, op.Message)
+	}
+
+	redises, err := p.redisStates(rf)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(p.stdout)
+	writeRedisStates(p.stdout, redises)
+	masters := 0
+	for _, redis := range redises {
+		if redis.isMaster() {
+			masters++
+		}
+	}
+	if masters != 1 && !rf.Bootstrapping() {
+		fmt.Fprintf(p.stdout, "Warning: %d redis masters found, expected 1\n", masters)
+	}
+
+	if !rf.SentinelsAllowed() {
+		return nil
+	}
+	views, err := p.sentinelViews(rf)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(p.stdout)
+	writeSentinelViews(p.stdout, rf, views)
+	return nil
+}
+
+// redisStates returns the replication state of every running redis pod of the RedisFailover
+func (p *plugin) redisStates(rf *redisfailoverv1.RedisFailover) ([]redisState, error) {
+	pods, err := p.redisPods(rf)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]redisState, 0, len(pods))
+	for _, pod := range pods {
+		state := redisState{pod: pod.Name, ip: pod.Status.PodIP}
+		out, err := p.redis(rf, pod.Name, "INFO", "replication")
+		if err == nil {
+			var parsed *info.Info
+			if parsed, err = info.Parse(out); err == nil {
+				state.replication = parsed.Replication
+			}
+		}
+		state.err = err
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func writeRedisStates(w io.Writer, states []redisState) {
+	var master *redisState
+	for i := range states {
+		if states[i].isMaster() {
+			master = &states[i]
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tw.Flush()
+	fmt.Fprintln(tw, "REDIS\tIP\tROLE\tMASTER\tLINK\tOFFSET\tLAG")
+	for _, state := range states {
+		if state.err != nil {
+			fmt.Fprintf(tw, "%s\t%s\t<error: %s>\t\t\t\t\n", state.pod, state.ip, state.err)
+			continue
+		}
+		masterAddress, link, lag := "-", "-", "-"
+		if !state.isMaster() {
+			masterAddress = net.JoinHostPort(state.replication.MasterHost, state.replication.MasterPort)
+			link = state.replication.MasterLinkStatus
+			if master != nil && state.replication.MasterHost == master.ip {
+				lag = strconv.FormatInt(master.offset()-state.offset(), 10)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", state.pod, state.ip, state.replication.Role, masterAddress, link, state.offset(), lag)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/term"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// terminal is the local terminal an interactive command is attached to
+type terminal struct {
+	fd        int
+	sizeKnown bool
+}
+
+// newTerminal returns the terminal of the file, nil when it isn't one
+func newTerminal(f *os.File) *terminal {
+	fd := int(f.Fd())
+	if !term.IsTerminal(fd) {
+		return nil
+	}
+	return &terminal{fd: fd}
+}
+
+// run runs the function with the terminal in raw mode, so the keys are sent as they are typed
+func (t *terminal) run(f func() error) error {
+	state, err := term.MakeRaw(t.fd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = term.Restore(t.fd, state) }()
+	return f()
+}
+
+// Next returns the size of the terminal for the remote one, only once as the resizes are not followed
+func (t *terminal) Next() *remotecommand.TerminalSize {
+	if t.sizeKnown {
+		return nil
+	}
+	t.sizeKnown = true
+	width, height, err := term.GetSize(t.fd)
+	if err != nil {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+}
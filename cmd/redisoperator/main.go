@@ -10,6 +10,8 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	// The time zones of the maintenance windows are loaded from the binary, the image doesn't have them
+	_ "time/tzdata"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -73,22 +75,35 @@ func (m *Main) Run() error {
 	}()
 
 	// Kubernetes clients.
-	k8sClient, customClient, aeClientset, err := utils.CreateKubernetesClients(m.flags)
+	k8sClient, customClient, aeClientset, dynamicClient, err := utils.CreateKubernetesClients(m.flags)
 	if err != nil {
 		return err
 	}
 
 	// Create kubernetes service.
-	k8sservice := k8s.New(k8sClient, customClient, aeClientset, m.logger, metricsRecorder)
+	k8sservice := k8s.New(k8sClient, customClient, aeClientset, dynamicClient, m.logger, metricsRecorder)
+	if m.flags.DryRun {
+		log.Infof("Running in dry run mode, the changes will be logged and not applied")
+		k8sservice = k8s.NewDryRun(k8sservice, m.logger, metricsRecorder)
+	}
 
 	// Create the redis clients
-	redisClient := redis.New(metricsRecorder)
+	redisClient := redis.New(m.flags.ToRedisClientConfig(), metricsRecorder)
 
 	// Get lease lock resource namespace
 	lockNamespace := getNamespace()
 
+	// The operator pods are allowed by the network policies of the redis failovers
+	operatorConfig := m.flags.ToRedisOperatorConfig()
+	operatorConfig.OperatorNamespace = lockNamespace
+
 	// Create operator and run.
-	redisfailoverOperator, err := redisfailover.New(m.flags.ToRedisOperatorConfig(), k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
+	redisfailoverOperator, err := redisfailover.New(operatorConfig, k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
+	if err != nil {
+		return err
+	}
+
+	snapshotController, err := redisfailover.NewSnapshotController(operatorConfig, k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
 	if err != nil {
 		return err
 	}
@@ -96,6 +111,9 @@ func (m *Main) Run() error {
 	go func() {
 		errC <- redisfailoverOperator.Run(context.Background())
 	}()
+	go func() {
+		errC <- snapshotController.Run(context.Background())
+	}()
 
 	// Await signals.
 	sigC := m.createSignalCapturer()
@@ -148,6 +166,14 @@ func getNamespace() string {
 
 // Run app.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderCommand {
+		if err := render(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error rendering: %s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	logger := log.Base()
 	m := New(logger)
 
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spotahome/redis-operator/operator/redisfailover"
+)
+
+const renderCommand = "render"
+
+// render runs the render subcommand, it prints the objects generated for the RedisFailovers of a file
+// without a cluster.
+func render(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet(renderCommand, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: redis-operator %s -f FILE\n\nPrints the objects generated for the RedisFailovers of the file.\n\n", renderCommand)
+		flags.PrintDefaults()
+	}
+	var file string
+	flags.StringVar(&file, "f", "", "File with the RedisFailovers to render, - for the standard input")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if file == "" {
+		flags.Usage()
+		return fmt.Errorf("a file is required")
+	}
+
+	in := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return redisfailover.RenderManifests(in, stdout)
+}
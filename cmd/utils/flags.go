@@ -5,23 +5,33 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/spotahome/redis-operator/operator/redisfailover"
+	"github.com/spotahome/redis-operator/service/redis"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/homedir"
 )
 
 // CMDFlags are the flags used by the cmd
 // TODO: improve flags.
 type CMDFlags struct {
-	KubeConfig               string
-	SupportedNamespacesRegex string
-	Development              bool
-	ListenAddr               string
-	MetricsPath              string
-	K8sQueriesPerSecond      int
-	K8sQueriesBurstable      int
-	Concurrency              int
-	LogLevel                 string
+	KubeConfig                 string
+	SupportedNamespacesRegex   string
+	Development                bool
+	ListenAddr                 string
+	MetricsPath                string
+	K8sQueriesPerSecond        int
+	K8sQueriesBurstable        int
+	Concurrency                int
+	LogLevel                   string
+	RedisDialTimeout           time.Duration
+	RedisReadTimeout           time.Duration
+	RedisWriteTimeout          time.Duration
+	RedisIdleTimeout           time.Duration
+	RedisBackgroundSaveTimeout time.Duration
+	DryRun                     bool
+	OperatorPodLabels          string
 }
 
 // Init initializes and parse the flags
@@ -39,20 +49,46 @@ func (c *CMDFlags) Init() {
 	// reference: https://github.com/spotahome/kooper/blob/master/controller/controller.go#L89
 	flag.IntVar(&c.Concurrency, "concurrency", 3, "Number of conccurent workers meant to process events")
 	flag.StringVar(&c.LogLevel, "log-level", "info", "set log level")
+	redisDefaults := redis.DefaultConfig()
+	flag.DurationVar(&c.RedisDialTimeout, "redis-dial-timeout", redisDefaults.DialTimeout, "Timeout for establishing connections to redis and sentinel")
+	flag.DurationVar(&c.RedisReadTimeout, "redis-read-timeout", redisDefaults.ReadTimeout, "Timeout for reading replies from redis and sentinel")
+	flag.DurationVar(&c.RedisWriteTimeout, "redis-write-timeout", redisDefaults.WriteTimeout, "Timeout for sending commands to redis and sentinel")
+	flag.DurationVar(&c.RedisIdleTimeout, "redis-idle-timeout", redisDefaults.IdleTimeout, "Time after which unused redis and sentinel connections are closed")
+	flag.DurationVar(&c.RedisBackgroundSaveTimeout, "redis-background-save-timeout", redisDefaults.BackgroundSaveTimeout, "Maximum time waited for redis to save its dataset before a volume snapshot")
+	flag.BoolVar(&c.DryRun, "dry-run", false, "Log and record on the metrics the changes to the redis failovers instead of applying them")
+	flag.StringVar(&c.OperatorPodLabels, "operator-pod-labels", "", "Labels of the operator pods, like app=redisoperator, allowed by the network policies of the redis failovers. Every pod of the operator namespace when empty")
 	// Parse flags
 	flag.Parse()
 
 	if _, err := regexp.Compile(c.SupportedNamespacesRegex); err != nil {
 		panic(fmt.Errorf("supported namespaces Regex is not valid: %w", err))
 	}
+	if _, err := labels.ConvertSelectorToLabelsMap(c.OperatorPodLabels); err != nil {
+		panic(fmt.Errorf("operator pod labels are not valid: %w", err))
+	}
 }
 
 // ToRedisOperatorConfig convert the flags to redisfailover config
 func (c *CMDFlags) ToRedisOperatorConfig() redisfailover.Config {
+	// Already validated when the flags are parsed
+	operatorPodLabels, _ := labels.ConvertSelectorToLabelsMap(c.OperatorPodLabels)
 	return redisfailover.Config{
 		ListenAddress:            c.ListenAddr,
 		MetricsPath:              c.MetricsPath,
 		Concurrency:              c.Concurrency,
 		SupportedNamespacesRegex: c.SupportedNamespacesRegex,
+		DryRun:                   c.DryRun,
+		OperatorPodLabels:        operatorPodLabels,
+	}
+}
+
+// ToRedisClientConfig convert the flags to redis client config
+func (c *CMDFlags) ToRedisClientConfig() redis.Config {
+	return redis.Config{
+		DialTimeout:           c.RedisDialTimeout,
+		ReadTimeout:           c.RedisReadTimeout,
+		WriteTimeout:          c.RedisWriteTimeout,
+		IdleTimeout:           c.RedisIdleTimeout,
+		BackgroundSaveTimeout: c.RedisBackgroundSaveTimeout,
 	}
 }
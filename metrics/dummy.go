@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	koopercontroller "github.com/spotahome/kooper/v2/controller"
 )
 
@@ -29,3 +31,19 @@ func (d dummy) RecordK8sOperation(namespace string, kind string, object string,
 }
 func (d dummy) RecordRedisOperation(kind string, IP string, operation string, status string, err string) {
 }
+func (d dummy) RecordConfigDrift(namespace string, resource string, kind string, instance string, parameter string) {
+}
+func (d dummy) SetPendingOperation(namespace string, name string, operation string, pending bool) {
+}
+func (d dummy) RecordDryRunChange(namespace string, resource string, kind string, object string, action string) {
+}
+func (d dummy) SetReplicationLags(namespace string, name string, lags map[string]int64) {
+}
+func (d dummy) SetMaster(namespace string, name string, pod string) {
+}
+func (d dummy) RecordMasterChange(namespace string, name string, reason string) {
+}
+func (d dummy) ObserveReconcileDuration(namespace string, name string, duration time.Duration) {
+}
+func (d dummy) SetLastSuccessfulReconcile(namespace string, name string, t time.Time) {
+}
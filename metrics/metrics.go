@@ -41,6 +41,7 @@ const (
 	MISC                                   = "MISC_ERROR"
 	SENTINEL_NUMBER_IN_MEMORY_MISMATCH     = "SENTINEL_NUMBER_IN_MEMORY_MISMATCH"
 	REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH = "REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH"
+	EMPTY_MASTER                           = "MASTER_RESTARTED_WITHOUT_DATA"
 	// redis connection related errors
 	WRONG_PASSWORD_USED = "WRONG_PASSWORD_USED"
 	NOAUTH              = "AUTH_CREDENTIALS_NOT_PROVIDED"
@@ -60,6 +61,7 @@ const (
 	APPLY_SENTINEL_CONFIG       = "APPLY_SENTINEL_CONFIG"
 	MONITOR_REDIS_WITH_PORT     = "SET_SENTINEL_TO_MONITOR_REDIS_WITH_GIVEN_PORT"
 	RESET_SENTINEL              = "RESET_ALL_SENTINEL_CONFIG"
+	REMOVE_SENTINEL_MONITOR     = "REMOVE_SENTINEL_MONITOR"
 	GET_NUM_SENTINELS_IN_MEM    = "GET_NUMBER_OF_SENTINELS_IN_MEMORY"    // `info sentinel` command on a sentinel machine > grep sentinel
 	GET_NUM_REDIS_SLAVES_IN_MEM = "GET_NUMBER_OF_REDIS_SLAVES_IN_MEMORY" // `info sentinel` command on a sentinel machine > grep slaves
 	GET_SLAVE_OF                = "GET_MASTER_OF_GIVEN_SLAVE_INSTANCE"
@@ -69,6 +71,17 @@ const (
 	GET_SENTINEL_MONITOR        = "SENTINEL_GET_MASTER_INSTANCE"
 	CHECK_SENTINEL_QUORUM       = "SENTINEL_CKQUORUM"
 	SLAVE_IS_READY              = "CHECK_IF_SLAVE_IS_READY"
+	BACKGROUND_SAVE             = "BACKGROUND_SAVE"
+	GET_INFO                    = "GET_INFO_OF_INSTANCE"
+	GET_SENTINEL_INFO           = "GET_SENTINEL_INFO_OF_INSTANCE" // `info sentinel` command on a sentinel machine
+	GET_REDIS_CONFIG            = "GET_REDIS_CONFIG"
+	REWRITE_REDIS_CONFIG        = "REWRITE_REDIS_CONFIG"
+	GET_SENTINEL_CONFIG         = "SENTINEL_GET_MASTER_CONFIG"
+	WATCH_SENTINEL_EVENTS       = "SUBSCRIBE_SENTINEL_EVENTS"
+
+	// reasons of the master changes
+	MASTER_CHANGE_BY_SENTINEL = "sentinel"
+	MASTER_CHANGE_BY_OPERATOR = "operator"
 )
 
 var ( // used for grabage collection of metrics
@@ -95,6 +108,24 @@ type Recorder interface {
 
 	RecordK8sOperation(namespace string, kind string, name string, operation string, status string, err string)
 	RecordRedisOperation(kind string, IP string, operation string, status string, err string)
+
+	// Indicate a configuration parameter of a redis or sentinel changed out of the operator
+	RecordConfigDrift(namespace string, resource string, kind string, instance string, parameter string)
+
+	// Indicate a disruptive operation waits for a maintenance window
+	SetPendingOperation(namespace string, name string, operation string, pending bool)
+
+	// Indicate a change that the operator would apply, when it runs in dry run mode
+	RecordDryRunChange(namespace string, resource string, kind string, object string, action string)
+
+	// Indicate the replication state of a failover cluster
+	SetReplicationLags(namespace string, name string, lags map[string]int64 /* pod -> lag in bytes */)
+	SetMaster(namespace string, name string, pod string)
+	RecordMasterChange(namespace string, name string, reason string)
+
+	// Indicate how the reconciliations of a failover cluster go
+	ObserveReconcileDuration(namespace string, name string, duration time.Duration)
+	SetLastSuccessfulReconcile(namespace string, name string, t time.Time)
 }
 
 // PromMetrics implements the instrumenter so the metrics can be managed by Prometheus.
@@ -106,6 +137,14 @@ type recorder struct {
 	sentinelCheck        *prometheus.CounterVec // indicates any error encountered in managed sentinel instance(s)
 	k8sServiceOperations *prometheus.CounterVec // number of operations performed on k8s
 	redisOperations      *prometheus.CounterVec // number of operations performed on redis/sentinel instances
+	configDrift          *prometheus.CounterVec // number of configuration parameters found changed out of the operator
+	pendingOperations    *prometheus.GaugeVec   // disruptive operations waiting for a maintenance window
+	dryRunChanges        *prometheus.CounterVec // number of changes not applied as the operator runs in dry run mode
+	replicationLag       *prometheus.GaugeVec   // bytes of the replication stream each slave is behind its master
+	master               *prometheus.GaugeVec   // pod working as master
+	masterChanges        *prometheus.CounterVec // number of times the master changed
+	reconcileDuration    *prometheus.HistogramVec
+	lastReconcile        *prometheus.GaugeVec // time of the last successful reconciliation
 	koopercontroller.MetricsRecorder
 }
 
@@ -156,6 +195,63 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 			Help:      "number of operations performed on k8s",
 		}, []string{"namespace", "kind", "name", "operation", "status", "err"})
 
+	configDrift := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "config_drift_total",
+		Help:      "number of times a configuration parameter of a managed redis or sentinel was found changed out of the operator",
+	}, []string{"namespace", "resource", "kind", "instance", "parameter"})
+
+	pendingOperations := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "pending_operations",
+		Help:      "disruptive operations of a failover cluster waiting for a maintenance window",
+	}, []string{"namespace", "name", "operation"})
+
+	dryRunChanges := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "dry_run_changes_total",
+		Help:      "number of changes the operator would apply to a failover cluster, when it runs in dry run mode",
+	}, []string{"namespace", "resource", "kind", "object", "action"})
+
+	replicationLag := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "replication_lag_bytes",
+		Help:      "bytes of the replication stream a slave of a failover cluster is behind its master",
+	}, []string{"namespace", "name", "pod"})
+
+	master := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "master",
+		Help:      "pod working as master of a failover cluster, always 1",
+	}, []string{"namespace", "name", "pod"})
+
+	masterChanges := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "master_changes_total",
+		Help:      "number of times the master of a failover cluster changed, by who chose the new one: sentinel or operator",
+	}, []string{"namespace", "name", "reason"})
+
+	reconcileDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "reconcile_duration_seconds",
+		Help:      "duration of the reconciliations of a failover cluster",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"namespace", "name"})
+
+	lastReconcile := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "last_successful_reconcile_timestamp_seconds",
+		Help:      "unix time of the last successful reconciliation of a failover cluster",
+	}, []string{"namespace", "name"})
+
 	// Create the instance.
 	r := recorder{
 		clusterOK:            clusterOK,
@@ -164,6 +260,14 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 		sentinelCheck:        sentinelCheck,
 		k8sServiceOperations: k8sServiceOperations,
 		redisOperations:      redisOperations,
+		configDrift:          configDrift,
+		pendingOperations:    pendingOperations,
+		dryRunChanges:        dryRunChanges,
+		replicationLag:       replicationLag,
+		master:               master,
+		masterChanges:        masterChanges,
+		reconcileDuration:    reconcileDuration,
+		lastReconcile:        lastReconcile,
 		MetricsRecorder: kooperprometheus.New(kooperprometheus.Config{
 			Registerer: reg,
 		}),
@@ -177,6 +281,14 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 		r.sentinelCheck,
 		r.k8sServiceOperations,
 		r.redisOperations,
+		r.configDrift,
+		r.pendingOperations,
+		r.dryRunChanges,
+		r.replicationLag,
+		r.master,
+		r.masterChanges,
+		r.reconcileDuration,
+		r.lastReconcile,
 	)
 	recorders = append(recorders, r)
 	return r
@@ -195,6 +307,13 @@ func (r recorder) SetClusterError(namespace string, name string) {
 // DeleteCluster set the cluster status to Error
 func (r recorder) DeleteCluster(namespace string, name string) {
 	r.clusterOK.DeleteLabelValues(namespace, name)
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	r.pendingOperations.DeletePartialMatch(labels)
+	r.replicationLag.DeletePartialMatch(labels)
+	r.master.DeletePartialMatch(labels)
+	r.masterChanges.DeletePartialMatch(labels)
+	r.reconcileDuration.DeletePartialMatch(labels)
+	r.lastReconcile.DeletePartialMatch(labels)
 }
 
 func (r recorder) RecordEnsureOperation(objectNamespace string, objectName string, objectKind string, resourceName string, status string) {
@@ -222,6 +341,51 @@ func (r recorder) RecordRedisOperation(kind /*redis/sentinel? */ string, IP stri
 	updateInstanceMetricLastUpdatedTracker(IP)
 }
 
+func (r recorder) RecordConfigDrift(namespace string, resource string, kind string, instance string, parameter string) {
+	r.configDrift.WithLabelValues(namespace, resource, kind, instance, parameter).Add(1)
+	updateResourceMetricLastUpdatedTracker(namespace, "redisfailover", resource)
+}
+
+func (r recorder) SetPendingOperation(namespace string, name string, operation string, pending bool) {
+	value := 0.0
+	if pending {
+		value = 1
+	}
+	r.pendingOperations.WithLabelValues(namespace, name, operation).Set(value)
+}
+
+func (r recorder) RecordDryRunChange(namespace string, resource string, kind string, object string, action string) {
+	r.dryRunChanges.WithLabelValues(namespace, resource, kind, object, action).Add(1)
+	updateResourceMetricLastUpdatedTracker(namespace, "redisfailover", resource)
+}
+
+// SetReplicationLags replaces the replication lag of the slaves of the cluster, so the slaves that are gone
+// don't keep their last one
+func (r recorder) SetReplicationLags(namespace string, name string, lags map[string]int64) {
+	r.replicationLag.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	for pod, lag := range lags {
+		r.replicationLag.WithLabelValues(namespace, name, pod).Set(float64(lag))
+	}
+}
+
+// SetMaster sets the pod working as master of the cluster, replacing the previous one
+func (r recorder) SetMaster(namespace string, name string, pod string) {
+	r.master.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	r.master.WithLabelValues(namespace, name, pod).Set(1)
+}
+
+func (r recorder) RecordMasterChange(namespace string, name string, reason string) {
+	r.masterChanges.WithLabelValues(namespace, name, reason).Add(1)
+}
+
+func (r recorder) ObserveReconcileDuration(namespace string, name string, duration time.Duration) {
+	r.reconcileDuration.WithLabelValues(namespace, name).Observe(duration.Seconds())
+}
+
+func (r recorder) SetLastSuccessfulReconcile(namespace string, name string, t time.Time) {
+	r.lastReconcile.WithLabelValues(namespace, name).Set(float64(t.Unix()))
+}
+
 func updateResourceMetricLastUpdatedTracker(namespace string, kind string, name string) {
 	mutex.Lock()
 	resourceMetricLastUpdated[fmt.Sprintf("%v/%v/%v", namespace, kind, name)] = time.Now()
@@ -249,6 +413,8 @@ func removeStaleMetrics() {
 			for _, label := range customResourceBasedLabels {
 				metricsDeletedCount += recorder.redisCheck.DeletePartialMatch(label)
 				metricsDeletedCount += recorder.sentinelCheck.DeletePartialMatch(label)
+				metricsDeletedCount += recorder.configDrift.DeletePartialMatch(label)
+				metricsDeletedCount += recorder.dryRunChanges.DeletePartialMatch(label)
 				labelWithName := label
 				labelWithName["name"] = labelWithName["resource"]
 				delete(labelWithName, "resource")
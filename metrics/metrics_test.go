@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -109,6 +110,82 @@ func TestPrometheusMetrics(t *testing.T) {
 			},
 			expCode: http.StatusOK,
 		},
+		{
+			name: "Config drift should be counted",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.RecordConfigDrift("testns", "test", "redis", "0.0.0.0", "maxmemory")
+				rec.RecordConfigDrift("testns", "test", "redis", "0.0.0.0", "maxmemory")
+			},
+			expMetrics: []string{
+				`my_metrics_controller_config_drift_total{instance="0.0.0.0",kind="redis",namespace="testns",parameter="maxmemory",resource="test"} 2`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Pending operations should be set and removed with their cluster",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetPendingOperation("testns1", "test", "RedisPodsUpdate", true)
+				rec.SetPendingOperation("testns1", "test", "StorageExpansion", false)
+				rec.SetPendingOperation("testns2", "test", "RedisPodsUpdate", true)
+				rec.DeleteCluster("testns2", "test")
+			},
+			expMetrics: []string{
+				`my_metrics_controller_pending_operations{name="test",namespace="testns1",operation="RedisPodsUpdate"} 1`,
+				`my_metrics_controller_pending_operations{name="test",namespace="testns1",operation="StorageExpansion"} 0`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Dry run changes should be counted",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.RecordDryRunChange("testns", "test", "StatefulSet", "rfr-test", "update")
+				rec.RecordDryRunChange("testns", "test", "StatefulSet", "rfr-test", "update")
+				rec.RecordDryRunChange("testns", "test", "Redis", "0.0.0.0", "SLAVEOF NO ONE")
+			},
+			expMetrics: []string{
+				`my_metrics_controller_dry_run_changes_total{action="update",kind="StatefulSet",namespace="testns",object="rfr-test",resource="test"} 2`,
+				`my_metrics_controller_dry_run_changes_total{action="SLAVEOF NO ONE",kind="Redis",namespace="testns",object="0.0.0.0",resource="test"} 1`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Replication lags should replace the previous ones",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetReplicationLags("testns", "test", map[string]int64{"rfr-test-1": 10, "rfr-test-2": 20})
+				rec.SetReplicationLags("testns", "test", map[string]int64{"rfr-test-1": 0})
+			},
+			expMetrics: []string{
+				`my_metrics_controller_replication_lag_bytes{name="test",namespace="testns",pod="rfr-test-1"} 0`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Master should replace the previous one and its changes should be counted",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetMaster("testns", "test", "rfr-test-0")
+				rec.SetMaster("testns", "test", "rfr-test-1")
+				rec.RecordMasterChange("testns", "test", metrics.MASTER_CHANGE_BY_SENTINEL)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_master{name="test",namespace="testns",pod="rfr-test-1"} 1`,
+				`my_metrics_controller_master_changes_total{name="test",namespace="testns",reason="sentinel"} 1`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Reconciliations should be recorded",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.ObserveReconcileDuration("testns", "test", 300*time.Millisecond)
+				rec.SetLastSuccessfulReconcile("testns", "test", time.Unix(1700000000, 0))
+			},
+			expMetrics: []string{
+				`my_metrics_controller_reconcile_duration_seconds_bucket{name="test",namespace="testns",le="0.25"} 0`,
+				`my_metrics_controller_reconcile_duration_seconds_bucket{name="test",namespace="testns",le="0.5"} 1`,
+				`my_metrics_controller_reconcile_duration_seconds_count{name="test",namespace="testns"} 1`,
+				`my_metrics_controller_last_successful_reconcile_timestamp_seconds{name="test",namespace="testns"} 1.7e+09`,
+			},
+			expCode: http.StatusOK,
+		},
 	}
 
 	for _, test := range tests {
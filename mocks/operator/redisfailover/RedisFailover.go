@@ -5,11 +5,11 @@ package mocks
 import (
 	context "context"
 
-	mock "github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	mock "github.com/stretchr/testify/mock"
 
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 
 	watch "k8s.io/apimachinery/pkg/watch"
 )
@@ -19,24 +19,50 @@ type RedisFailover struct {
 	mock.Mock
 }
 
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *RedisFailover) GetRedisFailover(ctx context.Context, namespace string, name string) (*v1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *v1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*v1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRedisFailovers provides a mock function with given fields: ctx, namespace, opts
-func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace string, opts v1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
+func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*v1.RedisFailoverList, error) {
 	ret := _m.Called(ctx, namespace, opts)
 
-	var r0 *redisfailoverv1.RedisFailoverList
+	var r0 *v1.RedisFailoverList
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, v1.ListOptions) (*redisfailoverv1.RedisFailoverList, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (*v1.RedisFailoverList, error)); ok {
 		return rf(ctx, namespace, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, v1.ListOptions) *redisfailoverv1.RedisFailoverList); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *v1.RedisFailoverList); ok {
 		r0 = rf(ctx, namespace, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverList)
+			r0 = ret.Get(0).(*v1.RedisFailoverList)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, v1.ListOptions) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
 		r1 = rf(ctx, namespace, opts)
 	} else {
 		r1 = ret.Error(1)
@@ -45,16 +71,68 @@ func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace strin
 	return r0, r1
 }
 
+// UpdateRedisFailover provides a mock function with given fields: ctx, redisFailover
+func (_m *RedisFailover) UpdateRedisFailover(ctx context.Context, redisFailover *v1.RedisFailover) (*v1.RedisFailover, error) {
+	ret := _m.Called(ctx, redisFailover)
+
+	var r0 *v1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (*v1.RedisFailover, error)); ok {
+		return rf(ctx, redisFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) *v1.RedisFailover); ok {
+		r0 = rf(ctx, redisFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, redisFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverStatus provides a mock function with given fields: ctx, redisFailover
+func (_m *RedisFailover) UpdateRedisFailoverStatus(ctx context.Context, redisFailover *v1.RedisFailover) (*v1.RedisFailover, error) {
+	ret := _m.Called(ctx, redisFailover)
+
+	var r0 *v1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (*v1.RedisFailover, error)); ok {
+		return rf(ctx, redisFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) *v1.RedisFailover); ok {
+		r0 = rf(ctx, redisFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, redisFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WatchRedisFailovers provides a mock function with given fields: ctx, namespace, opts
-func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
 	ret := _m.Called(ctx, namespace, opts)
 
 	var r0 watch.Interface
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, v1.ListOptions) (watch.Interface, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
 		return rf(ctx, namespace, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, v1.ListOptions) watch.Interface); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
 		r0 = rf(ctx, namespace, opts)
 	} else {
 		if ret.Get(0) != nil {
@@ -62,7 +140,7 @@ func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace stri
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, v1.ListOptions) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
 		r1 = rf(ctx, namespace, opts)
 	} else {
 		r1 = ret.Error(1)
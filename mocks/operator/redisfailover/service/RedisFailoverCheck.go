@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+
+	service "github.com/spotahome/redis-operator/operator/redisfailover/service"
 	mock "github.com/stretchr/testify/mock"
 
 	time "time"
@@ -15,13 +18,13 @@ type RedisFailoverCheck struct {
 	mock.Mock
 }
 
-// CheckAllSlavesFromMaster provides a mock function with given fields: master, rFailover
-func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(master, rFailover)
+// CheckAllSlavesFromMaster provides a mock function with given fields: ctx, master, rFailover
+func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, master, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(master, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, master, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,23 +32,23 @@ func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover
 	return r0
 }
 
-// CheckIfMasterLocalhost provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(rFailover)
+// CheckIfMasterLocalhost provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(ctx context.Context, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (bool, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (bool, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -67,23 +70,23 @@ func (_m *RedisFailoverCheck) CheckRedisNumber(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// CheckRedisSlavesReady provides a mock function with given fields: slaveIP, rFailover
-func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(slaveIP, rFailover)
+// CheckRedisSlavesReady provides a mock function with given fields: ctx, slaveIP, rFailover
+func (_m *RedisFailoverCheck) CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, slaveIP, rFailover)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) (bool, error)); ok {
-		return rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (bool, error)); ok {
+		return rf(ctx, slaveIP, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) bool); ok {
-		r0 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, *v1.RedisFailover) error); ok {
-		r1 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,20 +94,20 @@ func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v
 	return r0, r1
 }
 
-// CheckSentinelMonitor provides a mock function with given fields: sentinel, monitor
-func (_m *RedisFailoverCheck) CheckSentinelMonitor(sentinel string, monitor ...string) error {
+// CheckSentinelMonitor provides a mock function with given fields: ctx, sentinel, rFailover, monitor
+func (_m *RedisFailoverCheck) CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *v1.RedisFailover, monitor ...string) error {
 	_va := make([]interface{}, len(monitor))
 	for _i := range monitor {
 		_va[_i] = monitor[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, sentinel)
+	_ca = append(_ca, ctx, sentinel, rFailover)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, ...string) error); ok {
-		r0 = rf(sentinel, monitor...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover, ...string) error); ok {
+		r0 = rf(ctx, sentinel, rFailover, monitor...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -126,13 +129,13 @@ func (_m *RedisFailoverCheck) CheckSentinelNumber(rFailover *v1.RedisFailover) e
 	return r0
 }
 
-// CheckSentinelNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -140,23 +143,23 @@ func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFail
 	return r0
 }
 
-// CheckSentinelQuorum provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// CheckSentinelQuorum provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelQuorum(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (int, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (int, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -164,13 +167,13 @@ func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -178,23 +181,23 @@ func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string,
 	return r0
 }
 
-// GetMasterIP provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetMasterIP(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetMasterIP provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetMasterIP(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -226,23 +229,49 @@ func (_m *RedisFailoverCheck) GetMaxRedisPodTime(rFailover *v1.RedisFailover) (t
 	return r0, r1
 }
 
-// GetNumberMasters provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetNumberMasters(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// GetNumberMasters provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetNumberMasters(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (int, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (int, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisConfigDrift provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverCheck) GetRedisConfigDrift(ctx context.Context, ip string, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -300,23 +329,23 @@ func (_m *RedisFailoverCheck) GetRedisesIPs(rFailover *v1.RedisFailover) ([]stri
 	return r0, r1
 }
 
-// GetRedisesMasterPod provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesMasterPod provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesMasterPod(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -324,25 +353,51 @@ func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// GetRedisesSlavesPods provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesSlavesPods(rFailover *v1.RedisFailover) ([]string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesSlavesPods provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesSlavesPods(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) ([]string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelConfigDrift provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverCheck) GetSentinelConfigDrift(ctx context.Context, ip string, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -376,6 +431,32 @@ func (_m *RedisFailoverCheck) GetSentinelsIPs(rFailover *v1.RedisFailover) ([]st
 	return r0, r1
 }
 
+// GetSnapshot provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetSnapshot(ctx context.Context, rFailover *v1.RedisFailover) (*service.RedisFailoverSnapshot, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 *service.RedisFailoverSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (*service.RedisFailoverSnapshot, error)); ok {
+		return rf(ctx, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) *service.RedisFailoverSnapshot); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.RedisFailoverSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStatefulSetUpdateRevision provides a mock function with given fields: rFailover
 func (_m *RedisFailoverCheck) GetStatefulSetUpdateRevision(rFailover *v1.RedisFailover) (string, error) {
 	ret := _m.Called(rFailover)
@@ -442,6 +523,20 @@ func (_m *RedisFailoverCheck) IsSentinelRunning(rFailover *v1.RedisFailover) boo
 	return r0
 }
 
+// UpdateRoleLabels provides a mock function with given fields: rFailover, snapshot, master
+func (_m *RedisFailoverCheck) UpdateRoleLabels(rFailover *v1.RedisFailover, snapshot *service.RedisFailoverSnapshot, master string) error {
+	ret := _m.Called(rFailover, snapshot, master)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, *service.RedisFailoverSnapshot, string) error); ok {
+		r0 = rf(rFailover, snapshot, master)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewRedisFailoverCheck interface {
 	mock.TestingT
 	Cleanup(func())
@@ -6,6 +6,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	networkingv1 "k8s.io/api/networking/v1"
+
 	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 )
 
@@ -14,6 +16,62 @@ type RedisFailoverClient struct {
 	mock.Mock
 }
 
+// DeleteRedisPersistentVolumeClaims provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) DeleteRedisPersistentVolumeClaims(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureFinalizer provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) EnsureFinalizer(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureMonitoring provides a mock function with given fields: rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureMonitoring(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureNetworkPolicies provides a mock function with given fields: rFailover, labels, ownerRefs, operator
+func (_m *RedisFailoverClient) EnsureNetworkPolicies(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) error {
+	ret := _m.Called(rFailover, labels, ownerRefs, operator)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference, networkingv1.NetworkPolicyPeer) error); ok {
+		r0 = rf(rFailover, labels, ownerRefs, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // EnsureNotPresentRedisService provides a mock function with given fields: rFailover
 func (_m *RedisFailoverClient) EnsureNotPresentRedisService(rFailover *v1.RedisFailover) error {
 	ret := _m.Called(rFailover)
@@ -28,6 +86,20 @@ func (_m *RedisFailoverClient) EnsureNotPresentRedisService(rFailover *v1.RedisF
 	return r0
 }
 
+// EnsureNotPresentSentinel provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) EnsureNotPresentSentinel(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // EnsureRedisConfigMap provides a mock function with given fields: rFailover, labels, ownerRefs
 func (_m *RedisFailoverClient) EnsureRedisConfigMap(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	ret := _m.Called(rFailover, labels, ownerRefs)
@@ -126,6 +198,20 @@ func (_m *RedisFailoverClient) EnsureRedisStatefulset(rFailover *v1.RedisFailove
 	return r0
 }
 
+// EnsureRedisStorageExpansion provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) EnsureRedisStorageExpansion(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // EnsureSentinelConfigMap provides a mock function with given fields: rFailover, labels, ownerRefs
 func (_m *RedisFailoverClient) EnsureSentinelConfigMap(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	ret := _m.Called(rFailover, labels, ownerRefs)
@@ -168,6 +254,48 @@ func (_m *RedisFailoverClient) EnsureSentinelService(rFailover *v1.RedisFailover
 	return r0
 }
 
+// RemoveFinalizer provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) RemoveFinalizer(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RetainRedisPersistentVolumeClaims provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) RetainRedisPersistentVolumeClaims(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SnapshotRedisPersistentVolumeClaims provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) SnapshotRedisPersistentVolumeClaims(rFailover *v1.RedisFailover) error {
+	ret := _m.Called(rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewRedisFailoverClient interface {
 	mock.TestingT
 	Cleanup(func())
@@ -11,12 +11,22 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	networkingv1 "k8s.io/api/networking/v1"
+
 	policyv1 "k8s.io/api/policy/v1"
 
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 
+	runtime "k8s.io/apimachinery/pkg/runtime"
+
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+
+	storagev1 "k8s.io/api/storage/v1"
+
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
 	v1 "k8s.io/api/core/v1"
 
 	watch "k8s.io/apimachinery/pkg/watch"
@@ -69,6 +79,20 @@ func (_m *Services) CreateIfNotExistsService(namespace string, service *v1.Servi
 	return r0
 }
 
+// CreateNetworkPolicy provides a mock function with given fields: namespace, networkPolicy
+func (_m *Services) CreateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ret := _m.Called(namespace, networkPolicy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *networkingv1.NetworkPolicy) error); ok {
+		r0 = rf(namespace, networkPolicy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateOrUpdateConfigMap provides a mock function with given fields: namespace, np
 func (_m *Services) CreateOrUpdateConfigMap(namespace string, np *v1.ConfigMap) error {
 	ret := _m.Called(namespace, np)
@@ -97,6 +121,34 @@ func (_m *Services) CreateOrUpdateDeployment(namespace string, deployment *appsv
 	return r0
 }
 
+// CreateOrUpdateMonitoringObject provides a mock function with given fields: resource, namespace, object
+func (_m *Services) CreateOrUpdateMonitoringObject(resource schema.GroupVersionResource, namespace string, object *unstructured.Unstructured) error {
+	ret := _m.Called(resource, namespace, object)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(schema.GroupVersionResource, string, *unstructured.Unstructured) error); ok {
+		r0 = rf(resource, namespace, object)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrUpdateNetworkPolicy provides a mock function with given fields: namespace, networkPolicy
+func (_m *Services) CreateOrUpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ret := _m.Called(namespace, networkPolicy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *networkingv1.NetworkPolicy) error); ok {
+		r0 = rf(namespace, networkPolicy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateOrUpdatePod provides a mock function with given fields: namespace, pod
 func (_m *Services) CreateOrUpdatePod(namespace string, pod *v1.Pod) error {
 	ret := _m.Called(namespace, pod)
@@ -265,6 +317,20 @@ func (_m *Services) CreateStatefulSet(namespace string, statefulSet *appsv1.Stat
 	return r0
 }
 
+// CreateVolumeSnapshot provides a mock function with given fields: namespace, volumeSnapshot
+func (_m *Services) CreateVolumeSnapshot(namespace string, volumeSnapshot *unstructured.Unstructured) error {
+	ret := _m.Called(namespace, volumeSnapshot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *unstructured.Unstructured) error); ok {
+		r0 = rf(namespace, volumeSnapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteConfigMap provides a mock function with given fields: namespace, name
 func (_m *Services) DeleteConfigMap(namespace string, name string) error {
 	ret := _m.Called(namespace, name)
@@ -293,6 +359,48 @@ func (_m *Services) DeleteDeployment(namespace string, name string) error {
 	return r0
 }
 
+// DeleteMonitoringObject provides a mock function with given fields: resource, namespace, name
+func (_m *Services) DeleteMonitoringObject(resource schema.GroupVersionResource, namespace string, name string) error {
+	ret := _m.Called(resource, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(schema.GroupVersionResource, string, string) error); ok {
+		r0 = rf(resource, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteNetworkPolicy provides a mock function with given fields: namespace, name
+func (_m *Services) DeleteNetworkPolicy(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePersistentVolumeClaim provides a mock function with given fields: namespace, name
+func (_m *Services) DeletePersistentVolumeClaim(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeletePod provides a mock function with given fields: namespace, name
 func (_m *Services) DeletePod(namespace string, name string) error {
 	ret := _m.Called(namespace, name)
@@ -349,6 +457,20 @@ func (_m *Services) DeleteStatefulSet(namespace string, name string) error {
 	return r0
 }
 
+// DeleteVolumeSnapshot provides a mock function with given fields: namespace, name
+func (_m *Services) DeleteVolumeSnapshot(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetClusterRole provides a mock function with given fields: name
 func (_m *Services) GetClusterRole(name string) (*rbacv1.ClusterRole, error) {
 	ret := _m.Called(name)
@@ -453,6 +575,84 @@ func (_m *Services) GetDeploymentPods(namespace string, name string) (*v1.PodLis
 	return r0, r1
 }
 
+// GetMonitoringObject provides a mock function with given fields: resource, namespace, name
+func (_m *Services) GetMonitoringObject(resource schema.GroupVersionResource, namespace string, name string) (*unstructured.Unstructured, error) {
+	ret := _m.Called(resource, namespace, name)
+
+	var r0 *unstructured.Unstructured
+	var r1 error
+	if rf, ok := ret.Get(0).(func(schema.GroupVersionResource, string, string) (*unstructured.Unstructured, error)); ok {
+		return rf(resource, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(schema.GroupVersionResource, string, string) *unstructured.Unstructured); ok {
+		r0 = rf(resource, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.Unstructured)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(schema.GroupVersionResource, string, string) error); ok {
+		r1 = rf(resource, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNetworkPolicy provides a mock function with given fields: namespace, name
+func (_m *Services) GetNetworkPolicy(namespace string, name string) (*networkingv1.NetworkPolicy, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *networkingv1.NetworkPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*networkingv1.NetworkPolicy, error)); ok {
+		return rf(namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *networkingv1.NetworkPolicy); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*networkingv1.NetworkPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPersistentVolumeClaim provides a mock function with given fields: namespace, name
+func (_m *Services) GetPersistentVolumeClaim(namespace string, name string) (*v1.PersistentVolumeClaim, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *v1.PersistentVolumeClaim
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*v1.PersistentVolumeClaim, error)); ok {
+		return rf(namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *v1.PersistentVolumeClaim); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.PersistentVolumeClaim)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPod provides a mock function with given fields: namespace, name
 func (_m *Services) GetPod(namespace string, name string) (*v1.Pod, error) {
 	ret := _m.Called(namespace, name)
@@ -505,6 +705,58 @@ func (_m *Services) GetPodDisruptionBudget(namespace string, name string) (*poli
 	return r0, r1
 }
 
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisFailoverSnapshot provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailoverSnapshot(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverSnapshot, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *redisfailoverv1.RedisFailoverSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailoverSnapshot, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailoverSnapshot); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRole provides a mock function with given fields: namespace, name
 func (_m *Services) GetRole(namespace string, name string) (*rbacv1.Role, error) {
 	ret := _m.Called(namespace, name)
@@ -661,6 +913,58 @@ func (_m *Services) GetStatefulSetPods(namespace string, name string) (*v1.PodLi
 	return r0, r1
 }
 
+// GetStorageClass provides a mock function with given fields: name
+func (_m *Services) GetStorageClass(name string) (*storagev1.StorageClass, error) {
+	ret := _m.Called(name)
+
+	var r0 *storagev1.StorageClass
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*storagev1.StorageClass, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) *storagev1.StorageClass); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storagev1.StorageClass)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVolumeSnapshot provides a mock function with given fields: namespace, name
+func (_m *Services) GetVolumeSnapshot(namespace string, name string) (*unstructured.Unstructured, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *unstructured.Unstructured
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*unstructured.Unstructured, error)); ok {
+		return rf(namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *unstructured.Unstructured); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.Unstructured)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListConfigMaps provides a mock function with given fields: namespace
 func (_m *Services) ListConfigMaps(namespace string) (*v1.ConfigMapList, error) {
 	ret := _m.Called(namespace)
@@ -713,6 +1017,32 @@ func (_m *Services) ListDeployments(namespace string) (*appsv1.DeploymentList, e
 	return r0, r1
 }
 
+// ListPersistentVolumeClaims provides a mock function with given fields: namespace, selector
+func (_m *Services) ListPersistentVolumeClaims(namespace string, selector map[string]string) (*v1.PersistentVolumeClaimList, error) {
+	ret := _m.Called(namespace, selector)
+
+	var r0 *v1.PersistentVolumeClaimList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string) (*v1.PersistentVolumeClaimList, error)); ok {
+		return rf(namespace, selector)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]string) *v1.PersistentVolumeClaimList); ok {
+		r0 = rf(namespace, selector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.PersistentVolumeClaimList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]string) error); ok {
+		r1 = rf(namespace, selector)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListPods provides a mock function with given fields: namespace
 func (_m *Services) ListPods(namespace string) (*v1.PodList, error) {
 	ret := _m.Called(namespace)
@@ -739,6 +1069,32 @@ func (_m *Services) ListPods(namespace string) (*v1.PodList, error) {
 	return r0, r1
 }
 
+// ListRedisFailoverSnapshots provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) ListRedisFailoverSnapshots(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverSnapshotList, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	var r0 *redisfailoverv1.RedisFailoverSnapshotList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (*redisfailoverv1.RedisFailoverSnapshotList, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *redisfailoverv1.RedisFailoverSnapshotList); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverSnapshotList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRedisFailovers provides a mock function with given fields: ctx, namespace, opts
 func (_m *Services) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
 	ret := _m.Called(ctx, namespace, opts)
@@ -817,6 +1173,11 @@ func (_m *Services) ListStatefulSets(namespace string) (*appsv1.StatefulSetList,
 	return r0, r1
 }
 
+// RecordEvent provides a mock function with given fields: object, eventType, reason, message
+func (_m *Services) RecordEvent(object runtime.Object, eventType string, reason string, message string) {
+	_m.Called(object, eventType, reason, message)
+}
+
 // UpdateConfigMap provides a mock function with given fields: namespace, configMap
 func (_m *Services) UpdateConfigMap(namespace string, configMap *v1.ConfigMap) error {
 	ret := _m.Called(namespace, configMap)
@@ -845,6 +1206,34 @@ func (_m *Services) UpdateDeployment(namespace string, deployment *appsv1.Deploy
 	return r0
 }
 
+// UpdateNetworkPolicy provides a mock function with given fields: namespace, networkPolicy
+func (_m *Services) UpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ret := _m.Called(namespace, networkPolicy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *networkingv1.NetworkPolicy) error); ok {
+		r0 = rf(namespace, networkPolicy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePersistentVolumeClaim provides a mock function with given fields: namespace, pvc
+func (_m *Services) UpdatePersistentVolumeClaim(namespace string, pvc *v1.PersistentVolumeClaim) error {
+	ret := _m.Called(namespace, pvc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.PersistentVolumeClaim) error); ok {
+		r0 = rf(namespace, pvc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePod provides a mock function with given fields: namespace, pod
 func (_m *Services) UpdatePod(namespace string, pod *v1.Pod) error {
 	ret := _m.Called(namespace, pod)
@@ -859,6 +1248,20 @@ func (_m *Services) UpdatePod(namespace string, pod *v1.Pod) error {
 	return r0
 }
 
+// UpdatePodAnnotations provides a mock function with given fields: namespace, podName, annotations
+func (_m *Services) UpdatePodAnnotations(namespace string, podName string, annotations map[string]string) error {
+	ret := _m.Called(namespace, podName, annotations)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, map[string]string) error); ok {
+		r0 = rf(namespace, podName, annotations)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePodDisruptionBudget provides a mock function with given fields: namespace, podDisruptionBudget
 func (_m *Services) UpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
 	ret := _m.Called(namespace, podDisruptionBudget)
@@ -887,6 +1290,84 @@ func (_m *Services) UpdatePodLabels(namespace string, podName string, labels map
 	return r0
 }
 
+// UpdateRedisFailover provides a mock function with given fields: ctx, redisFailover
+func (_m *Services) UpdateRedisFailover(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, redisFailover)
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, redisFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, redisFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *redisfailoverv1.RedisFailover) error); ok {
+		r1 = rf(ctx, redisFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverSnapshotStatus provides a mock function with given fields: ctx, redisFailoverSnapshot
+func (_m *Services) UpdateRedisFailoverSnapshotStatus(ctx context.Context, redisFailoverSnapshot *redisfailoverv1.RedisFailoverSnapshot) (*redisfailoverv1.RedisFailoverSnapshot, error) {
+	ret := _m.Called(ctx, redisFailoverSnapshot)
+
+	var r0 *redisfailoverv1.RedisFailoverSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailoverSnapshot) (*redisfailoverv1.RedisFailoverSnapshot, error)); ok {
+		return rf(ctx, redisFailoverSnapshot)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailoverSnapshot) *redisfailoverv1.RedisFailoverSnapshot); ok {
+		r0 = rf(ctx, redisFailoverSnapshot)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *redisfailoverv1.RedisFailoverSnapshot) error); ok {
+		r1 = rf(ctx, redisFailoverSnapshot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverStatus provides a mock function with given fields: ctx, redisFailover
+func (_m *Services) UpdateRedisFailoverStatus(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, redisFailover)
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, redisFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, redisFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *redisfailoverv1.RedisFailover) error); ok {
+		r1 = rf(ctx, redisFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateRole provides a mock function with given fields: namespace, role
 func (_m *Services) UpdateRole(namespace string, role *rbacv1.Role) error {
 	ret := _m.Called(namespace, role)
@@ -943,6 +1424,32 @@ func (_m *Services) UpdateStatefulSet(namespace string, statefulSet *appsv1.Stat
 	return r0
 }
 
+// WatchRedisFailoverSnapshots provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchRedisFailoverSnapshots(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WatchRedisFailovers provides a mock function with given fields: ctx, namespace, opts
 func (_m *Services) WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
 	ret := _m.Called(ctx, namespace, opts)
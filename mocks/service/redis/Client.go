@@ -2,30 +2,82 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	info "github.com/spotahome/redis-operator/service/redis/info"
+	mock "github.com/stretchr/testify/mock"
+
+	redis "github.com/spotahome/redis-operator/service/redis"
+)
 
 // Client is an autogenerated mock type for the Client type
 type Client struct {
 	mock.Mock
 }
 
-// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// BackgroundSave provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) BackgroundSave(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloseFailover provides a mock function with given fields: namespace, name
+func (_m *Client) CloseFailover(namespace string, name string) {
+	_m.Called(namespace, name)
+}
+
+// GetInfo provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetInfo(ctx context.Context, ip string, port string, password string) (*info.Info, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 *info.Info
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*info.Info, error)); ok {
+		return rf(ctx, ip, port, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *info.Info); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*info.Info)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ctx, ip
+func (_m *Client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string) (int32, error) {
+	ret := _m.Called(ctx, ip)
 
 	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int32, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int32, error)); ok {
+		return rf(ctx, ip)
 	}
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) int32); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,23 +85,99 @@ func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetNumberSentinelsInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// GetNumberSentinelsInMemory provides a mock function with given fields: ctx, ip
+func (_m *Client) GetNumberSentinelsInMemory(ctx context.Context, ip string) (int32, error) {
+	ret := _m.Called(ctx, ip)
 
 	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int32, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int32, error)); ok {
+		return rf(ctx, ip)
 	}
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) int32); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisConfigDrift provides a mock function with given fields: ctx, ip, port, configs, password
+func (_m *Client) GetRedisConfigDrift(ctx context.Context, ip string, port string, configs []string, password string) ([]string, error) {
+	ret := _m.Called(ctx, ip, port, configs, password)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) ([]string, error)); ok {
+		return rf(ctx, ip, port, configs, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) []string); ok {
+		r0 = rf(ctx, ip, port, configs, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, string) error); ok {
+		r1 = rf(ctx, ip, port, configs, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelConfigDrift provides a mock function with given fields: ctx, ip, configs
+func (_m *Client) GetSentinelConfigDrift(ctx context.Context, ip string, configs []string) ([]string, error) {
+	ret := _m.Called(ctx, ip, configs)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) ([]string, error)); ok {
+		return rf(ctx, ip, configs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []string); ok {
+		r0 = rf(ctx, ip, configs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, ip, configs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelMaster provides a mock function with given fields: ctx, ip
+func (_m *Client) GetSentinelMaster(ctx context.Context, ip string) (info.SentinelMaster, error) {
+	ret := _m.Called(ctx, ip)
+
+	var r0 info.SentinelMaster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (info.SentinelMaster, error)); ok {
+		return rf(ctx, ip)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) info.SentinelMaster); ok {
+		r0 = rf(ctx, ip)
+	} else {
+		r0 = ret.Get(0).(info.SentinelMaster)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -57,30 +185,30 @@ func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetSentinelMonitor provides a mock function with given fields: ip
-func (_m *Client) GetSentinelMonitor(ip string) (string, string, error) {
-	ret := _m.Called(ip)
+// GetSentinelMonitor provides a mock function with given fields: ctx, ip
+func (_m *Client) GetSentinelMonitor(ctx context.Context, ip string) (string, string, error) {
+	ret := _m.Called(ctx, ip)
 
 	var r0 string
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(string) (string, string, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, string, error)); ok {
+		return rf(ctx, ip)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) string); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(string) error); ok {
-		r2 = rf(ip)
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, ip)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -88,23 +216,23 @@ func (_m *Client) GetSentinelMonitor(ip string) (string, string, error) {
 	return r0, r1, r2
 }
 
-// GetSlaveOf provides a mock function with given fields: ip, port, password
-func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, error) {
-	ret := _m.Called(ip, port, password)
+// GetSlaveOf provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetSlaveOf(ctx context.Context, ip string, port string, password string) (string, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,23 +240,23 @@ func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, e
 	return r0, r1
 }
 
-// IsMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) IsMaster(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// IsMaster provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) IsMaster(ctx context.Context, ip string, port string, password string) (bool, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -136,13 +264,27 @@ func (_m *Client) IsMaster(ip string, port string, password string) (bool, error
 	return r0, r1
 }
 
-// MakeMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) MakeMaster(ip string, port string, password string) error {
-	ret := _m.Called(ip, port, password)
+// MakeMaster provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) MakeMaster(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MakeSlaveOf provides a mock function with given fields: ctx, ip, masterIP, password
+func (_m *Client) MakeSlaveOf(ctx context.Context, ip string, masterIP string, password string) error {
+	ret := _m.Called(ctx, ip, masterIP, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, masterIP, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -150,13 +292,13 @@ func (_m *Client) MakeMaster(ip string, port string, password string) error {
 	return r0
 }
 
-// MakeSlaveOf provides a mock function with given fields: ip, masterIP, password
-func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error {
-	ret := _m.Called(ip, masterIP, password)
+// MakeSlaveOfWithPort provides a mock function with given fields: ctx, ip, masterIP, masterPort, password
+func (_m *Client) MakeSlaveOfWithPort(ctx context.Context, ip string, masterIP string, masterPort string, password string) error {
+	ret := _m.Called(ctx, ip, masterIP, masterPort, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, masterIP, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, masterIP, masterPort, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -164,13 +306,13 @@ func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error
 	return r0
 }
 
-// MakeSlaveOfWithPort provides a mock function with given fields: ip, masterIP, masterPort, password
-func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort string, password string) error {
-	ret := _m.Called(ip, masterIP, masterPort, password)
+// MonitorRedis provides a mock function with given fields: ctx, ip, monitor, quorum, password
+func (_m *Client) MonitorRedis(ctx context.Context, ip string, monitor string, quorum string, password string) error {
+	ret := _m.Called(ctx, ip, monitor, quorum, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, masterIP, masterPort, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, monitor, quorum, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -178,13 +320,13 @@ func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort str
 	return r0
 }
 
-// MonitorRedis provides a mock function with given fields: ip, monitor, quorum, password
-func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, password string) error {
-	ret := _m.Called(ip, monitor, quorum, password)
+// MonitorRedisWithPort provides a mock function with given fields: ctx, ip, monitor, port, quorum, password
+func (_m *Client) MonitorRedisWithPort(ctx context.Context, ip string, monitor string, port string, quorum string, password string) error {
+	ret := _m.Called(ctx, ip, monitor, port, quorum, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, quorum, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, monitor, port, quorum, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -192,13 +334,13 @@ func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, passwor
 	return r0
 }
 
-// MonitorRedisWithPort provides a mock function with given fields: ip, monitor, port, quorum, password
-func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, quorum string, password string) error {
-	ret := _m.Called(ip, monitor, port, quorum, password)
+// RemoveSentinelMonitor provides a mock function with given fields: ctx, ip
+func (_m *Client) RemoveSentinelMonitor(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, port, quorum, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -206,13 +348,13 @@ func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, q
 	return r0
 }
 
-// ResetSentinel provides a mock function with given fields: ip
-func (_m *Client) ResetSentinel(ip string) error {
-	ret := _m.Called(ip)
+// ResetSentinel provides a mock function with given fields: ctx, ip
+func (_m *Client) ResetSentinel(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -220,13 +362,13 @@ func (_m *Client) ResetSentinel(ip string) error {
 	return r0
 }
 
-// SentinelCheckQuorum provides a mock function with given fields: ip
-func (_m *Client) SentinelCheckQuorum(ip string) error {
-	ret := _m.Called(ip)
+// SentinelCheckQuorum provides a mock function with given fields: ctx, ip
+func (_m *Client) SentinelCheckQuorum(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -234,13 +376,13 @@ func (_m *Client) SentinelCheckQuorum(ip string) error {
 	return r0
 }
 
-// SetCustomRedisConfig provides a mock function with given fields: ip, port, configs, password
-func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	ret := _m.Called(ip, port, configs, password)
+// SetCustomRedisConfig provides a mock function with given fields: ctx, ip, port, configs, password
+func (_m *Client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error {
+	ret := _m.Called(ctx, ip, port, configs, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, []string, string) error); ok {
-		r0 = rf(ip, port, configs, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) error); ok {
+		r0 = rf(ctx, ip, port, configs, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -248,13 +390,13 @@ func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string,
 	return r0
 }
 
-// SetCustomSentinelConfig provides a mock function with given fields: ip, configs
-func (_m *Client) SetCustomSentinelConfig(ip string, configs []string) error {
-	ret := _m.Called(ip, configs)
+// SetCustomSentinelConfig provides a mock function with given fields: ctx, ip, configs
+func (_m *Client) SetCustomSentinelConfig(ctx context.Context, ip string, configs []string) error {
+	ret := _m.Called(ctx, ip, configs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
-		r0 = rf(ip, configs)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, ip, configs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -262,23 +404,56 @@ func (_m *Client) SetCustomSentinelConfig(ip string, configs []string) error {
 	return r0
 }
 
-// SlaveIsReady provides a mock function with given fields: ip, port, password
-func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// SlaveIsReady provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) SlaveIsReady(ctx context.Context, ip string, port string, password string) (bool, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchSentinelEvents provides a mock function with given fields: ctx, ip, patterns
+func (_m *Client) WatchSentinelEvents(ctx context.Context, ip string, patterns ...string) (<-chan redis.SentinelEvent, error) {
+	_va := make([]interface{}, len(patterns))
+	for _i := range patterns {
+		_va[_i] = patterns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, ip)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 <-chan redis.SentinelEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) (<-chan redis.SentinelEvent, error)); ok {
+		return rf(ctx, ip, patterns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) <-chan redis.SentinelEvent); ok {
+		r0 = rf(ctx, ip, patterns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan redis.SentinelEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = rf(ctx, ip, patterns...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,35 +1,41 @@
 package redisfailover
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
 )
 
-// UpdateRedisesPods if the running version of pods are equal to the statefulset one
-func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailover) error {
-	redises, err := r.rfChecker.GetRedisesIPs(rf)
-	if err != nil {
-		return err
-	}
+const (
+	// maxConcurrentNodeOperations is the maximum number of nodes configured at the same time
+	maxConcurrentNodeOperations = 10
+	// emptyMasterMaxUptime is the uptime until a master without data is considered restarted, it
+	// covers a couple of resyncs
+	emptyMasterMaxUptime = 2 * time.Minute
+)
 
+// UpdateRedisesPods if the running version of pods are equal to the statefulset one. Out of the maintenance
+// windows, the outdated pods are left pending.
+func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailover, snapshot *rfservice.RedisFailoverSnapshot) error {
 	masterIP := ""
 	if !rf.Bootstrapping() {
-		masterIP, _ = r.rfChecker.GetMasterIP(rf)
+		masterIP, _ = snapshot.MasterAddress()
 	}
 	// No perform updates when nodes are syncing, still not connected, etc.
-	for _, rip := range redises {
-		if rip != masterIP {
-			ready, err := r.rfChecker.CheckRedisSlavesReady(rip, rf)
-			if err != nil {
-				return err
-			}
-			if !ready {
-				return nil
-			}
+	for _, node := range snapshot.Redises {
+		if node.Err != nil {
+			return node.Err
+		}
+		if node.Address != masterIP && !node.SlaveReady {
+			return nil
 		}
 	}
 
@@ -38,44 +44,36 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 		return err
 	}
 
-	redisesPods, err := r.rfChecker.GetRedisesSlavesPods(rf)
-	if err != nil {
+	outdated := 0
+	for _, node := range snapshot.Redises {
+		if node.RevisionHash != ssUR {
+			outdated++
+		}
+	}
+	pending := ""
+	if outdated > 0 && !rf.InMaintenanceWindow(time.Now()) {
+		pending = fmt.Sprintf("restart of %d outdated redis pods", outdated)
+	}
+	if err := r.setPendingOperation(rf, redisfailoverv1.PendingOperationRedisPodsUpdate, pending); err != nil || pending != "" {
 		return err
 	}
 
 	// Update stale pods with slave role
-	for _, pod := range redisesPods {
-		revision, err := r.rfChecker.GetRedisRevisionHash(pod, rf)
-		if err != nil {
-			return err
-		}
-		if revision != ssUR {
+	for _, node := range snapshot.Redises {
+		if !node.IsMaster && node.RevisionHash != ssUR {
 			//Delete pod and wait next round to check if the new one is synced
-			err = r.rfHealer.DeletePod(pod, rf)
-			if err != nil {
-				return err
-			}
-			return nil
+			return r.rfHealer.DeletePod(node.PodName, rf)
 		}
 	}
 
 	if !rf.Bootstrapping() {
 		// Update stale pod with role master
-		master, err := r.rfChecker.GetRedisesMasterPod(rf)
+		master, err := snapshot.Master()
 		if err != nil {
 			return err
 		}
-
-		masterRevision, err := r.rfChecker.GetRedisRevisionHash(master, rf)
-		if err != nil {
-			return err
-		}
-		if masterRevision != ssUR {
-			err = r.rfHealer.DeletePod(master, rf)
-			if err != nil {
-				return err
-			}
-			return nil
+		if master.RevisionHash != ssUR {
+			return r.rfHealer.DeletePod(master.PodName, rf)
 		}
 	}
 
@@ -84,9 +82,10 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 
 // CheckAndHeal runs verifcation checks to ensure the RedisFailover is in an expected and healthy state.
 // If the checks do not match up to expectations, an attempt will be made to "heal" the RedisFailover into a healthy state.
-func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) error {
+// The state of all the nodes is collected concurrently once, and the checks are made against that snapshot.
+func (r *RedisFailoverHandler) CheckAndHeal(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	if rf.Bootstrapping() {
-		return r.checkAndHealBootstrapMode(rf)
+		return r.checkAndHealBootstrapMode(ctx, rf)
 	}
 
 	// Number of redis is equal as the set on the RF spec
@@ -110,24 +109,26 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 		return nil
 	}
 
-	nMasters, err := r.rfChecker.GetNumberMasters(rf)
+	snapshot, err := r.rfChecker.GetSnapshot(ctx, rf)
 	if err != nil {
 		return err
 	}
+	r.watchSentinelEvents(rf, snapshot)
 
-	switch nMasters {
+	switch snapshot.NumberMasters() {
 	case 0:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no masters detected"))
 		//when number of redis replicas is 1 , the redis is configured for standalone master mode
 		//Configure to master
 		if rf.Spec.Redis.Replicas == 1 {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Resource spec with standalone master - operator will set the master")
-			err = r.rfHealer.SetOldestAsMaster(rf)
+			err = r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
 			if err != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
 				return err
 			}
+			r.masterChosen(rf)
 			return nil
 		}
 		//During the First boot(New deployment or all pods of the statefulsets have restarted),
@@ -143,31 +144,33 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("No master avaiable but max pod up time is : %f", maxUptime.Round(time.Second).Seconds())
 		//Check If Sentinel has quorum to take a failover decision
-		noqrm_cnt, err := r.rfChecker.CheckSentinelQuorum(rf)
+		noqrm_cnt, err := r.rfChecker.CheckSentinelQuorum(ctx, rf)
 		if err != nil {
 			// Sentinels are not in a situation to choose a master we pick one
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Quorum not available for sentinel to choose master,estimated unhealthy sentinels :%d , Operator to step-in", noqrm_cnt)
-			err2 := r.rfHealer.SetOldestAsMaster(rf)
+			err2 := r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err2)
 			if err2 != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
 				return err2
 			}
+			r.masterChosen(rf)
 		} else {
 			//sentinels are having a quorum to make a failover , but check if redis are not having local hostip (first boot) as master
-			status, err2 := r.rfChecker.CheckIfMasterLocalhost(rf)
+			status, err2 := snapshot.CheckIfMasterLocalhost()
 			if err2 != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("CheckIfMasterLocalhost failed retry later")
 				return err2
 			} else if status {
 				// all avaialable redis pods have local host ip as master
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("all available redis is having local loop back as master , operator initiates master selection")
-				err3 := r.rfHealer.SetOldestAsMaster(rf)
+				err3 := r.rfHealer.SetOldestAsMaster(ctx, rf)
 				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err3)
 				if err3 != nil {
 					r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
 					return err3
 				}
+				r.masterChosen(rf)
 
 			} else {
 
@@ -179,6 +182,12 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 
 		}
 
+		// A new master has been set, the snapshot is outdated
+		snapshot, err = r.rfChecker.GetSnapshot(ctx, rf)
+		if err != nil {
+			return err
+		}
+
 	case 1:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, nil)
 	default:
@@ -186,51 +195,90 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 		return errors.New("more than one master, fix manually")
 	}
 
-	master, err := r.rfChecker.GetMasterIP(rf)
+	emptyMaster, candidate, found := snapshot.EmptyRestartedMaster(emptyMasterMaxUptime)
+	if found {
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.EMPTY_MASTER, metrics.NOT_APPLICABLE, errors.New("master restarted empty"))
+		action := "failing over to it"
+		if r.paused || r.config.DryRun {
+			action = "would fail over to it"
+		}
+		message := fmt.Sprintf("Master %s restarted without data while %s has %d keys, %s", emptyMaster.PodName, candidate.PodName, candidate.Keys, action)
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warn(message)
+		r.k8sservice.RecordEvent(rf, corev1.EventTypeWarning, emptyMasterFailoverReason, message)
+		// The empty master becomes a slave of the new one, so it gets the data back instead of
+		// wiping the slaves
+		if err := r.rfHealer.MakeMaster(ctx, candidate.Address, rf); err != nil {
+			return err
+		}
+		r.masterChosen(rf)
+		if err := r.rfHealer.SetMasterOnAll(ctx, candidate.Address, rf); err != nil {
+			return err
+		}
+
+		snapshot, err = r.rfChecker.GetSnapshot(ctx, rf)
+		if err != nil {
+			return err
+		}
+	} else {
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.EMPTY_MASTER, metrics.NOT_APPLICABLE, nil)
+	}
+
+	master, err := snapshot.MasterAddress()
 	if err != nil {
 		return err
 	}
+	r.recordReplicationState(rf, snapshot)
 
-	err = r.rfChecker.CheckAllSlavesFromMaster(master, rf)
+	// The pods of a paused RedisFailover are left as they are, labels included
+	if !r.paused {
+		if err := r.rfChecker.UpdateRoleLabels(rf, snapshot, master); err != nil {
+			return err
+		}
+	}
+
+	err = snapshot.CheckAllSlavesFromMaster(master)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SLAVE_WRONG_MASTER, metrics.NOT_APPLICABLE, err)
+	slavesHealed := false
 	if err != nil {
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Slave not associated to master: %s", err.Error())
-		if err = r.rfHealer.SetMasterOnAll(master, rf); err != nil {
+		if err = r.rfHealer.SetMasterOnAll(ctx, master, rf); err != nil {
 			return err
 		}
+		slavesHealed = true
 	}
 
-	err = r.applyRedisCustomConfig(rf)
+	err = r.applyRedisCustomConfig(ctx, rf, snapshot)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
 
-	err = r.UpdateRedisesPods(rf)
-	if err != nil {
-		return err
-	}
-
-	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
-	if err != nil {
-		return err
+	// Slaves that have just been attached to the master are still syncing, so no pod is
+	// updated until the next round
+	if !slavesHealed {
+		err = r.UpdateRedisesPods(rf, snapshot)
+		if err != nil {
+			return err
+		}
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
-	for _, sip := range sentinels {
-		err = r.rfChecker.CheckSentinelMonitor(sip, master, port)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
+	port := getRedisPort(rf.RedisAnnouncePort())
+	monitorChanged := make([]bool, len(snapshot.Sentinels))
+	for i, sentinel := range snapshot.Sentinels {
+		err = sentinel.CheckMonitor(master, port)
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sentinel.Address, err)
 		if err != nil {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-			if err := r.rfHealer.NewSentinelMonitor(sip, master, rf); err != nil {
+			if err := r.rfHealer.NewSentinelMonitor(ctx, sentinel.Address, master, rf); err != nil {
 				return err
 			}
+			monitorChanged[i] = true
 		}
 	}
-	return r.checkAndHealSentinels(rf, sentinels)
+	return r.checkAndHealSentinels(ctx, rf, snapshot, monitorChanged)
 }
 
-func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) checkAndHealBootstrapMode(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 
 	if !r.rfChecker.IsRedisRunning(rf) {
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
@@ -238,18 +286,23 @@ func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.Red
 		return nil
 	}
 
-	err := r.UpdateRedisesPods(rf)
+	snapshot, err := r.rfChecker.GetSnapshot(ctx, rf)
 	if err != nil {
 		return err
 	}
-	err = r.applyRedisCustomConfig(rf)
+
+	err = r.UpdateRedisesPods(rf, snapshot)
+	if err != nil {
+		return err
+	}
+	err = r.applyRedisCustomConfig(ctx, rf, snapshot)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
 
 	bootstrapSettings := rf.Spec.BootstrapNode
-	err = r.rfHealer.SetExternalMasterOnAll(bootstrapSettings.Host, bootstrapSettings.Port, rf)
+	err = r.rfHealer.SetExternalMasterOnAll(ctx, bootstrapSettings.Host, bootstrapSettings.Port, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
@@ -262,63 +315,95 @@ func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.Red
 			return nil
 		}
 
-		sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
-		if err != nil {
-			return err
-		}
-		for _, sip := range sentinels {
-			err = r.rfChecker.CheckSentinelMonitor(sip, bootstrapSettings.Host, bootstrapSettings.Port)
-			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
+		monitorChanged := make([]bool, len(snapshot.Sentinels))
+		for i, sentinel := range snapshot.Sentinels {
+			err = sentinel.CheckMonitor(bootstrapSettings.Host, bootstrapSettings.Port)
+			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sentinel.Address, err)
 			if err != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-				if err := r.rfHealer.NewSentinelMonitorWithPort(sip, bootstrapSettings.Host, bootstrapSettings.Port, rf); err != nil {
+				if err := r.rfHealer.NewSentinelMonitorWithPort(ctx, sentinel.Address, bootstrapSettings.Host, bootstrapSettings.Port, rf); err != nil {
 					return err
 				}
+				monitorChanged[i] = true
 			}
 		}
-		return r.checkAndHealSentinels(rf, sentinels)
+		return r.checkAndHealSentinels(ctx, rf, snapshot, monitorChanged)
 	}
 	return nil
 }
 
-func (r *RedisFailoverHandler) applyRedisCustomConfig(rf *redisfailoverv1.RedisFailover) error {
-	redises, err := r.rfChecker.GetRedisesIPs(rf)
-	if err != nil {
-		return err
-	}
-	for _, rip := range redises {
-		if err := r.rfHealer.SetRedisCustomConfig(rip, rf); err != nil {
-			return err
-		}
+func (r *RedisFailoverHandler) applyRedisCustomConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover, snapshot *rfservice.RedisFailoverSnapshot) error {
+	nodes := make([]configNode, len(snapshot.Redises))
+	for i, redis := range snapshot.Redises {
+		nodes[i] = configNode{podName: redis.PodName, address: redis.Address, checksum: redis.ConfigChecksum}
 	}
-	return nil
+	errs := r.applyCustomConfig(ctx, rf, redisConfigKind, rf.Spec.Redis.CustomConfig, nodes, r.rfChecker.GetRedisConfigDrift, r.rfHealer.SetRedisCustomConfig)
+	return firstError(errs)
 }
 
-func (r *RedisFailoverHandler) checkAndHealSentinels(rf *redisfailoverv1.RedisFailover, sentinels []string) error {
-	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelNumberInMemory(sip, rf)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sip, err)
+// checkAndHealSentinels checks the sentinels memory against the snapshot. The sentinels marked in skip
+// are not checked, as their memory has been renewed after the snapshot was taken.
+func (r *RedisFailoverHandler) checkAndHealSentinels(ctx context.Context, rf *redisfailoverv1.RedisFailover, snapshot *rfservice.RedisFailoverSnapshot, skip []bool) error {
+	// Sentinels are restored one by one, so the rest keep the knowledge of the topology meanwhile. Only the
+	// master of the RedisFailover is restored, the rest of masters monitored by shared sentinels are untouched.
+	for i, sentinel := range snapshot.Sentinels {
+		if skip[i] {
+			continue
+		}
+		err := sentinel.CheckSentinelsInMemory(snapshot.SentinelReplicas)
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sentinel.Address, err)
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of sentinels in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of sentinels in memory. resetting", sentinel.Address)
+			if err := r.rfHealer.RestoreSentinel(ctx, sentinel.Address, rf); err != nil {
 				return err
 			}
+			// Already restored, no need to check the slaves
+			skip[i] = true
 		}
-
 	}
-	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelSlavesNumberInMemory(sip, rf)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip, err)
+	for i, sentinel := range snapshot.Sentinels {
+		if skip[i] {
+			continue
+		}
+		err := sentinel.CheckSlavesInMemory(rf)
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sentinel.Address, err)
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sentinel.Address)
+			if err := r.rfHealer.RestoreSentinel(ctx, sentinel.Address, rf); err != nil {
 				return err
 			}
 		}
 	}
-	for _, sip := range sentinels {
-		err := r.rfHealer.SetSentinelCustomConfig(sip, rf)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.APPLY_SENTINEL_CONFIG, sip, err)
+
+	return r.applySentinelCustomConfig(ctx, rf, snapshot)
+}
+
+func (r *RedisFailoverHandler) applySentinelCustomConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover, snapshot *rfservice.RedisFailoverSnapshot) error {
+	nodes := make([]configNode, len(snapshot.Sentinels))
+	for i, sentinel := range snapshot.Sentinels {
+		nodes[i] = configNode{podName: sentinel.PodName, address: sentinel.Address, checksum: sentinel.ConfigChecksum}
+	}
+	errs := r.applyCustomConfig(ctx, rf, sentinelConfigKind, rf.Spec.Sentinel.CustomConfig, nodes, r.rfChecker.GetSentinelConfigDrift, r.rfHealer.SetSentinelCustomConfig)
+	for i, node := range nodes {
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.APPLY_SENTINEL_CONFIG, node.address, errs[i])
+	}
+	return firstError(errs)
+}
+
+// watchSentinelEvents keeps the subscriptions to the events of the sentinels of the snapshot
+func (r *RedisFailoverHandler) watchSentinelEvents(rf *redisfailoverv1.RedisFailover, snapshot *rfservice.RedisFailoverSnapshot) {
+	if r.sentinelEvents == nil {
+		return
+	}
+	sentinels := make([]string, len(snapshot.Sentinels))
+	for i, sentinel := range snapshot.Sentinels {
+		sentinels[i] = sentinel.Address
+	}
+	r.sentinelEvents.watch(rf, sentinels)
+}
+
+func firstError(errs []error) error {
+	for _, err := range errs {
 		if err != nil {
 			return err
 		}
@@ -1,22 +1,25 @@
 package redisfailover_test
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/log"
 	"github.com/spotahome/redis-operator/metrics"
 	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
 	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
 	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
 )
 
 func TestCheckAndHeal(t *testing.T) {
@@ -312,111 +315,136 @@ func TestCheckAndHeal(t *testing.T) {
 				mrfc.On("IsSentinelRunning", rf).Once().Return(true)
 			}
 
+			sentinelNode := rfservice.SentinelNodeState{
+				Address:           sentinel,
+				MonitorIP:         master,
+				MonitorPort:       "0",
+				SentinelsInMemory: rf.Spec.Sentinel.Replicas,
+				SlavesInMemory:    rf.Spec.Redis.Replicas - 1,
+			}
+			if bootstrappingTests {
+				sentinelNode.MonitorIP = bootstrapMaster
+				sentinelNode.MonitorPort = bootstrapMasterPort
+				sentinelNode.SlavesInMemory = rf.Spec.Redis.Replicas
+			}
+			if !test.sentinelMonitorOK {
+				sentinelNode.MonitorIP = "9.9.9.9"
+			}
+			if !test.sentinelNumberInMemoryOK {
+				sentinelNode.SentinelsInMemory = 0
+			}
+			if !test.sentinelSlavesNumberInMemoryOK {
+				sentinelNode.SlavesInMemory = 0
+			}
+
+			snapshot := &rfservice.RedisFailoverSnapshot{SentinelReplicas: rf.Spec.Sentinel.Replicas}
+			if allowSentinels {
+				snapshot.Sentinels = []rfservice.SentinelNodeState{sentinelNode}
+			}
+
 			if bootstrappingTests && continueTests {
-				// once to get ips for config update, once for the UpdateRedisesPods go right
-				mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.1", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.2", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.3", rf).Once().Return(nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.1", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.2", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.3", rf).Once().Return(true, nil)
+				for _, ip := range []string{"0.0.0.1", "0.0.0.2", "0.0.0.3"} {
+					snapshot.Redises = append(snapshot.Redises, rfservice.RedisNodeState{Address: ip, SlaveOf: bootstrapMaster, SlaveReady: true, RevisionHash: "1"})
+					mrfh.On("SetRedisCustomConfig", mock.Anything, ip, rf).Once().Return(nil)
+				}
+				mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(snapshot, nil)
 				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
 
 				if test.redisSetMasterOnAllOK {
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 				} else {
 					expErr = true
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
 				}
 			} else if continueTests {
-				mrfc.On("GetNumberMasters", rf).Once().Return(test.nMasters, nil)
+				slave := "0.0.0.1"
+				slaveOf := master
+				if !test.slavesOK {
+					slaveOf = "9.9.9.9"
+				}
+				snapshot.Redises = []rfservice.RedisNodeState{
+					{PodName: "master", Address: master, IsMaster: true, RevisionHash: "1"},
+					{PodName: "slave", Address: slave, SlaveOf: slaveOf, SlaveReady: true, RevisionHash: "1"},
+				}
+
 				switch test.nMasters {
 				case 0:
-					//mrfc.On("GetRedisesIPs", rf).Once().Return(make([]string, test.nRedis), nil)
+					slaveOf := "0.0.0.9"
+					if test.forceNewMasterFirstBoot {
+						slaveOf = "127.0.0.1"
+					}
+					noMasterSnapshot := &rfservice.RedisFailoverSnapshot{
+						Redises: []rfservice.RedisNodeState{
+							{PodName: "master", Address: master, SlaveOf: slaveOf},
+							{PodName: "slave", Address: slave, SlaveOf: slaveOf},
+						},
+						Sentinels:        snapshot.Sentinels,
+						SentinelReplicas: snapshot.SentinelReplicas,
+					}
+					mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(noMasterSnapshot, nil)
 					if rf.Spec.Redis.Replicas == 1 {
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 						continueTests = false
 						break
 					}
 					mrfc.On("GetMaxRedisPodTime", rf).Once().Return(1*time.Hour, nil)
 					if test.forceNewMasterNoQrm {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(1, errors.New(""))
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(1, errors.New(""))
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					} else if test.forceNewMasterFirstBoot {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(true, nil)
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(3, nil)
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(false, nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(3, nil)
 						continueTests = false
+						break
 					}
-
+					// The snapshot is taken again once the master is set
+					mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(snapshot, nil)
 				case 1:
-					break
+					mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(snapshot, nil)
 				default:
+					snapshot.Redises[1].IsMaster = true
+					mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(snapshot, nil)
 					// always expect error
 					expErr = true
 				}
 				if !expErr && continueTests {
-					mrfc.On("GetMasterIP", rf).Twice().Return(master, nil)
+					mrfc.On("UpdateRoleLabels", rf, snapshot, master).Once().Return(nil)
 					if test.slavesOK {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(nil)
+						mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
 					} else {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(errors.New(""))
 						if test.redisSetMasterOnAllOK {
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(nil)
+							mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(nil)
 						} else {
 							expErr = true
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(errors.New(""))
+							mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(errors.New(""))
 						}
-
 					}
-					mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{master}, nil)
-					mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-					mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
-					mrfc.On("GetRedisesMasterPod", rf).Once().Return(master, nil)
-					mrfc.On("GetRedisRevisionHash", master, rf).Once().Return("1", nil)
-					mrfh.On("SetRedisCustomConfig", master, rf).Once().Return(nil)
+					if !expErr {
+						mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+						mrfh.On("SetRedisCustomConfig", mock.Anything, slave, rf).Once().Return(nil)
+						mk.On("UpdatePodAnnotations", rf.Namespace, "master", mock.Anything).Once().Return(nil)
+						mk.On("UpdatePodAnnotations", rf.Namespace, "slave", mock.Anything).Once().Return(nil)
+					}
 				}
 			}
 
 			if allowSentinels && !expErr && continueTests {
-				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{sentinel}, nil)
-				if test.sentinelMonitorOK {
+				if !test.sentinelMonitorOK {
 					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, bootstrapMaster, bootstrapMasterPort).Once().Return(nil)
+						mrfh.On("NewSentinelMonitorWithPort", mock.Anything, sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, master, "0").Once().Return(nil)
+						mrfh.On("NewSentinelMonitor", mock.Anything, sentinel, master, rf).Once().Return(nil)
 					}
-				} else {
-					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, bootstrapMaster, bootstrapMasterPort).Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitorWithPort", sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
-					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, master, "0").Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitor", sentinel, master, rf).Once().Return(nil)
-					}
-				}
-				if test.sentinelNumberInMemoryOK {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(nil)
-				} else {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
-				}
-				if test.sentinelSlavesNumberInMemoryOK {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(nil)
-				} else {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+				} else if !test.sentinelNumberInMemoryOK || !test.sentinelSlavesNumberInMemoryOK {
+					mrfh.On("RestoreSentinel", mock.Anything, sentinel, rf).Once().Return(nil)
 				}
-				mrfh.On("SetSentinelCustomConfig", sentinel, rf).Once().Return(nil)
+				mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
 			}
 
 			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.CheckAndHeal(rf)
+			err := handler.CheckAndHeal(context.TODO(), rf)
 
 			if expErr {
 				assert.Error(err)
@@ -429,6 +457,220 @@ func TestCheckAndHeal(t *testing.T) {
 	}
 }
 
+func TestCheckAndHealEmptyRestartedMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	master := "0.0.0.0"
+	slave := "0.0.0.1"
+	sentinel := "1.1.1.1"
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	sentinelNode := rfservice.SentinelNodeState{
+		Address:           sentinel,
+		MonitorIP:         master,
+		MonitorPort:       "0",
+		SentinelsInMemory: rf.Spec.Sentinel.Replicas,
+		SlavesInMemory:    rf.Spec.Redis.Replicas - 1,
+	}
+	emptyMasterSnapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "master", Address: master, IsMaster: true, Uptime: time.Second, RevisionHash: "1"},
+			{PodName: "slave", Address: slave, SlaveOf: master, Keys: 10, RevisionHash: "1"},
+		},
+		Sentinels:        []rfservice.SentinelNodeState{sentinelNode},
+		SentinelReplicas: rf.Spec.Sentinel.Replicas,
+	}
+	failedOverSnapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "master", Address: master, SlaveOf: slave, SlaveReady: true, RevisionHash: "1"},
+			{PodName: "slave", Address: slave, IsMaster: true, Uptime: time.Hour, Keys: 10, RevisionHash: "1"},
+		},
+		Sentinels:        []rfservice.SentinelNodeState{sentinelNode},
+		SentinelReplicas: rf.Spec.Sentinel.Replicas,
+	}
+
+	mrfc.On("IsRedisRunning", rf).Once().Return(true)
+	mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(emptyMasterSnapshot, nil)
+	mk.On("RecordEvent", rf, corev1.EventTypeWarning, "EmptyMasterFailover", "Master master restarted without data while slave has 10 keys, failing over to it").Once()
+	mrfh.On("MakeMaster", mock.Anything, slave, rf).Once().Return(nil)
+	mrfh.On("SetMasterOnAll", mock.Anything, slave, rf).Once().Return(nil)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(failedOverSnapshot, nil)
+	mrfc.On("UpdateRoleLabels", rf, failedOverSnapshot, slave).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, slave, rf).Once().Return(nil)
+	mk.On("UpdatePodAnnotations", rf.Namespace, "master", mock.Anything).Once().Return(nil)
+	mk.On("UpdatePodAnnotations", rf.Namespace, "slave", mock.Anything).Once().Return(nil)
+	mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+	mrfh.On("NewSentinelMonitor", mock.Anything, sentinel, slave, rf).Once().Return(nil)
+	mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	err := handler.CheckAndHeal(context.TODO(), rf)
+
+	assert.NoError(err)
+	mk.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+func TestCheckAndHealEmptyRestartedMasterDryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	master := "0.0.0.0"
+	slave := "0.0.0.1"
+	sentinel := "1.1.1.1"
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	emptyMasterSnapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "master", Address: master, IsMaster: true, Uptime: time.Second, RevisionHash: "1"},
+			{PodName: "slave", Address: slave, SlaveOf: master, SlaveReady: true, Keys: 10, RevisionHash: "1"},
+		},
+		Sentinels: []rfservice.SentinelNodeState{
+			{
+				Address:           sentinel,
+				MonitorIP:         master,
+				MonitorPort:       "0",
+				SentinelsInMemory: rf.Spec.Sentinel.Replicas,
+				SlavesInMemory:    rf.Spec.Redis.Replicas - 1,
+			},
+		},
+		SentinelReplicas: rf.Spec.Sentinel.Replicas,
+	}
+
+	// The dry run healer only logs the failover, so the master stays empty
+	mrfc.On("IsRedisRunning", rf).Once().Return(true)
+	mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Times(2).Return(emptyMasterSnapshot, nil)
+	mk.On("RecordEvent", rf, corev1.EventTypeWarning, "EmptyMasterFailover", "Master master restarted without data while slave has 10 keys, would fail over to it").Once()
+	mrfh.On("MakeMaster", mock.Anything, slave, rf).Once().Return(nil)
+	mrfh.On("SetMasterOnAll", mock.Anything, slave, rf).Once().Return(nil)
+	mrfc.On("UpdateRoleLabels", rf, emptyMasterSnapshot, master).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, slave, rf).Once().Return(nil)
+	mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+	mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+
+	config := generateConfig()
+	config.DryRun = true
+	handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	assert.NoError(handler.CheckAndHeal(context.TODO(), rf))
+
+	mk.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+func TestCheckAndHealConfigDrift(t *testing.T) {
+	tests := []struct {
+		name        string
+		driftPolicy redisfailoverv1.DriftPolicy
+		expMessage  string
+		expReverted bool
+	}{
+		{
+			name:        "Report keeps the changed values",
+			driftPolicy: redisfailoverv1.DriftPolicyReport,
+			expMessage:  "Configuration of redis 0.0.0.0 changed out of the operator (maxmemory), keeping the changed values",
+		},
+		{
+			name:        "Enforce reverts the changed values",
+			driftPolicy: redisfailoverv1.DriftPolicyEnforce,
+			expMessage:  "Configuration of redis 0.0.0.0 changed out of the operator (maxmemory), reverting them",
+			expReverted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.DriftPolicy = test.driftPolicy
+			master := "0.0.0.0"
+			slave := "0.0.0.1"
+			sentinel := "1.1.1.1"
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			newSnapshot := func(checksums map[string]string) *rfservice.RedisFailoverSnapshot {
+				return &rfservice.RedisFailoverSnapshot{
+					Redises: []rfservice.RedisNodeState{
+						{PodName: "master", PodUID: "1", Address: master, IsMaster: true, RevisionHash: "1", ConfigChecksum: checksums["master"]},
+						{PodName: "slave", PodUID: "2", Address: slave, SlaveOf: master, SlaveReady: true, RevisionHash: "1", ConfigChecksum: checksums["slave"]},
+					},
+					Sentinels: []rfservice.SentinelNodeState{
+						{
+							PodName:           "sentinel",
+							PodUID:            "3",
+							Address:           sentinel,
+							ConfigChecksum:    checksums["sentinel"],
+							MonitorIP:         master,
+							MonitorPort:       "0",
+							SentinelsInMemory: rf.Spec.Sentinel.Replicas,
+							SlavesInMemory:    rf.Spec.Redis.Replicas - 1,
+						},
+					},
+					SentinelReplicas: rf.Spec.Sentinel.Replicas,
+				}
+			}
+			applied := newSnapshot(nil)
+
+			setMasterConfigTimes := 1
+			if test.expReverted {
+				setMasterConfigTimes = 2
+			}
+			mrfc.On("IsRedisRunning", rf).Times(2).Return(true)
+			mrfc.On("IsSentinelRunning", rf).Times(2).Return(true)
+			mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(applied, nil)
+			mrfc.On("UpdateRoleLabels", rf, applied, master).Once().Return(nil)
+			mrfc.On("GetStatefulSetUpdateRevision", rf).Times(2).Return("1", nil)
+			// First round, the configuration is applied to every node, keeping its checksum on the pods
+			checksums := map[string]string{}
+			mk.On("UpdatePodAnnotations", rf.Namespace, mock.Anything, mock.Anything).Times(3).Run(func(args mock.Arguments) {
+				checksums[args.String(1)] = args.Get(2).(map[string]string)["config-checksum.redisfailovers.databases.spotahome.com/test"]
+			}).Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Times(setMasterConfigTimes).Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, slave, rf).Once().Return(nil)
+			mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.CheckAndHeal(context.TODO(), rf))
+			assert.Len(checksums, 3)
+
+			// Second round, after the operator restarts, the configuration is checked against the applied one
+			configured := newSnapshot(checksums)
+			mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(configured, nil)
+			mrfc.On("UpdateRoleLabels", rf, configured, master).Once().Return(nil)
+			mrfc.On("GetRedisConfigDrift", mock.Anything, master, rf).Once().Return([]string{"maxmemory"}, nil)
+			mrfc.On("GetRedisConfigDrift", mock.Anything, slave, rf).Once().Return([]string{}, nil)
+			mrfc.On("GetSentinelConfigDrift", mock.Anything, sentinel, rf).Once().Return([]string{}, nil)
+			mk.On("RecordEvent", rf, corev1.EventTypeWarning, "ConfigDrift", test.expMessage).Once()
+
+			handler = rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.CheckAndHeal(context.TODO(), rf))
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	type podStatus struct {
 		pod    corev1.Pod
@@ -878,53 +1120,43 @@ func TestUpdate(t *testing.T) {
 			config := generateConfig()
 			mrfs := &mRFService.RedisFailoverClient{}
 
+			snapshot := &rfservice.RedisFailoverSnapshot{}
+			for _, pod := range test.pods {
+				snapshot.Redises = append(snapshot.Redises, rfservice.RedisNodeState{
+					PodName:      pod.pod.ObjectMeta.Name,
+					Address:      pod.pod.Status.PodIP,
+					RevisionHash: pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey],
+					IsMaster:     pod.master,
+					SlaveReady:   pod.ready && !pod.master,
+				})
+			}
+
 			mrfc := &mRFService.RedisFailoverCheck{}
-			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.0", "0.0.0.1", "1.1.1.1"}, nil)
+			mrfh := &mRFService.RedisFailoverHeal{}
 
 			next := true
-			if !test.bootstrapping {
-				master := "1.1.1.1"
-				if test.noMaster {
-					master = ""
-				}
-				mrfc.On("GetMasterIP", rf).Once().Return(master, nil)
-			}
-
 			for _, pod := range test.pods {
-				if !pod.master {
-					mrfc.On("CheckRedisSlavesReady", pod.pod.Status.PodIP, rf).Once().Return(pod.ready, nil)
-				}
 				if !pod.ready {
 					next = false
 					break
 				}
 			}
-			mrfh := &mRFService.RedisFailoverHeal{}
 
 			if next {
-				replicas := []string{"slave1", "slave2"}
-				if test.bootstrapping || test.noMaster {
-					replicas = append(replicas, "slave3")
-				}
 				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return(test.ssVersion, nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return(replicas, nil)
 
 				for _, pod := range test.pods {
-					mrfc.On("GetRedisRevisionHash", pod.pod.ObjectMeta.Name, rf).Once().Return(pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey], nil)
-					if pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey] != test.ssVersion {
+					if !pod.master && pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey] != test.ssVersion {
 						mrfh.On("DeletePod", pod.pod.ObjectMeta.Name, rf).Once().Return(nil)
-						if pod.master == false {
-							next = false
-							break
-						}
+						next = false
+						break
 					}
 				}
-				fmt.Printf("%v - %v\n", test.name, next)
 				if next && !test.bootstrapping {
-					if test.noMaster {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("", errors.New(""))
-					} else {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("master", nil)
+					for _, pod := range test.pods {
+						if pod.master && pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey] != test.ssVersion {
+							mrfh.On("DeletePod", pod.pod.ObjectMeta.Name, rf).Once().Return(nil)
+						}
 					}
 				}
 			}
@@ -932,7 +1164,7 @@ func TestUpdate(t *testing.T) {
 			mk := &mK8SService.Services{}
 
 			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.UpdateRedisesPods(rf)
+			err := handler.UpdateRedisesPods(rf, snapshot)
 
 			if test.errExpected {
 				assert.Error(err)
@@ -946,3 +1178,48 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateOutOfMaintenanceWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Weekday().String()[:3]
+	rf.Spec.MaintenanceWindows = []redisfailoverv1.MaintenanceWindow{
+		{Days: []string{tomorrow}, Start: "00:00", Duration: metav1.Duration{Duration: time.Minute}},
+	}
+	snapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "master", Address: "0.0.0.0", IsMaster: true, RevisionHash: "1"},
+			{PodName: "slave", Address: "0.0.0.1", SlaveOf: "0.0.0.0", SlaveReady: true, RevisionHash: "0"},
+		},
+	}
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	mrfc.On("GetStatefulSetUpdateRevision", rf).Times(2).Return("1", nil)
+	// The outdated slave is not deleted, but reported as pending once
+	mk.On("UpdateRedisFailoverStatus", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		pending := updated.Status.PendingOperations
+		return len(pending) == 1 && pending[0].Type == redisfailoverv1.PendingOperationRedisPodsUpdate && pending[0].Message == "restart of 1 outdated redis pods"
+	})).Once().Return(nil, nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	assert.NoError(handler.UpdateRedisesPods(rf, snapshot))
+	assert.NoError(handler.UpdateRedisesPods(rf, snapshot))
+
+	// Once the window opens, the slave is deleted and nothing is pending anymore
+	rf.Spec.MaintenanceWindows = nil
+	mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+	mk.On("UpdateRedisFailoverStatus", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		return updated.Status.PendingOperations == nil
+	})).Once().Return(nil, nil)
+	mrfh.On("DeletePod", "slave", rf).Once().Return(nil)
+	assert.NoError(handler.UpdateRedisesPods(rf, snapshot))
+
+	mk.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
@@ -6,4 +6,9 @@ type Config struct {
 	MetricsPath              string
 	Concurrency              int
 	SupportedNamespacesRegex string
+	DryRun                   bool
+	// OperatorNamespace is the namespace the operator runs in. Its pods are allowed by the network policies.
+	OperatorNamespace string
+	// OperatorPodLabels select the operator pods allowed by the network policies, every pod of its namespace when empty
+	OperatorPodLabels map[string]string
 }
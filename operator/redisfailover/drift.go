@@ -0,0 +1,90 @@
+package redisfailover
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+)
+
+const (
+	redisConfigKind    = "redis"
+	sentinelConfigKind = "sentinel"
+)
+
+func configChecksum(configs []string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(configs, "\n"))))
+}
+
+// configNode is a redis or sentinel pod whose custom configuration is handled
+type configNode struct {
+	podName string
+	address string
+	// checksum is the checksum of the configuration last applied to the pod, kept on its annotations
+	checksum string
+}
+
+// applyCustomConfig sets the custom configuration on the nodes that don't have it yet, keeping its checksum on
+// their pods. On the nodes that already have it, any difference is reported as a drift and reverted when the
+// drift policy is Enforce. While the RedisFailover is paused or in dry run mode nothing is set, so the
+// checksums are left as they were.
+func (r *RedisFailoverHandler) applyCustomConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover, kind string, configs []string, nodes []configNode,
+	getDrift func(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) ([]string, error),
+	setConfig func(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error) []error {
+	checksum := configChecksum(configs)
+
+	errs := make([]error, len(nodes))
+	util.RunConcurrently(len(nodes), maxConcurrentNodeOperations, func(i int) {
+		node := nodes[i]
+		if node.podName == "" || node.checksum != checksum {
+			errs[i] = r.setCustomConfig(ctx, rf, node, checksum, setConfig)
+			return
+		}
+		drift, err := getDrift(ctx, node.address, rf)
+		if err != nil || len(drift) == 0 {
+			errs[i] = err
+			return
+		}
+		r.reportConfigDrift(rf, kind, node.address, drift)
+		if rf.Spec.DriftPolicy == redisfailoverv1.DriftPolicyEnforce {
+			errs[i] = setConfig(ctx, node.address, rf)
+		}
+	})
+	return errs
+}
+
+// setCustomConfig sets the custom configuration on the node, and its checksum on the annotations of its pod
+func (r *RedisFailoverHandler) setCustomConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover, node configNode, checksum string,
+	setConfig func(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error) error {
+	if err := setConfig(ctx, node.address, rf); err != nil {
+		return err
+	}
+	if node.podName == "" || r.paused || r.config.DryRun {
+		return nil
+	}
+	return r.k8sservice.UpdatePodAnnotations(rf.Namespace, node.podName, map[string]string{
+		rfservice.GetConfigChecksumAnnotation(rf): checksum,
+	})
+}
+
+func (r *RedisFailoverHandler) reportConfigDrift(rf *redisfailoverv1.RedisFailover, kind string, address string, drift []string) {
+	for _, parameter := range drift {
+		r.mClient.RecordConfigDrift(rf.Namespace, rf.Name, kind, address, parameter)
+	}
+	action := "keeping the changed values"
+	if rf.Spec.DriftPolicy == redisfailoverv1.DriftPolicyEnforce {
+		action = "reverting them"
+		if r.paused {
+			action = "reverting them once resumed"
+		}
+	}
+	message := fmt.Sprintf("Configuration of %s %s changed out of the operator (%s), %s", kind, address, strings.Join(drift, ", "), action)
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warn(message)
+	r.k8sservice.RecordEvent(rf, corev1.EventTypeWarning, configDriftReason, message)
+}
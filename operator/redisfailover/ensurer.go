@@ -1,15 +1,19 @@
 package redisfailover
 
 import (
+	"errors"
+
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
 )
 
 // Ensure is called to ensure all of the resources associated with a RedisFailover are created
 func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels map[string]string, or []metav1.OwnerReference, metricsClient metrics.Recorder) error {
-	if rf.Spec.Redis.Exporter.Enabled {
+	if rf.DeployRedisService() {
 		if err := w.rfService.EnsureRedisService(rf, labels, or); err != nil {
 			return err
 		}
@@ -19,14 +23,18 @@ func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels
 		}
 	}
 
-	sentinelsAllowed := rf.SentinelsAllowed()
-	if sentinelsAllowed {
+	deploySentinels := rf.DeploySentinels()
+	if deploySentinels {
 		if err := w.rfService.EnsureSentinelService(rf, labels, or); err != nil {
 			return err
 		}
 		if err := w.rfService.EnsureSentinelConfigMap(rf, labels, or); err != nil {
 			return err
 		}
+	} else if rf.SharedSentinels() {
+		if err := w.rfService.EnsureNotPresentSentinel(rf); err != nil {
+			return err
+		}
 	}
 
 	if err := w.rfService.EnsureRedisMasterService(rf, labels, or); err != nil {
@@ -46,15 +54,38 @@ func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels
 	if err := w.rfService.EnsureRedisConfigMap(rf, labels, or); err != nil {
 		return err
 	}
+	// The network policies are ensured before the pods, so they are never reachable without them
+	if err := w.rfService.EnsureNetworkPolicies(rf, labels, or, w.operatorNetworkPolicyPeer()); err != nil {
+		return err
+	}
 	if err := w.rfService.EnsureRedisStatefulset(rf, labels, or); err != nil {
 		return err
 	}
+	if err := w.rfService.EnsureRedisStorageExpansion(rf); err != nil {
+		return err
+	}
 
-	if sentinelsAllowed {
+	if deploySentinels {
 		if err := w.rfService.EnsureSentinelDeployment(rf, labels, or); err != nil {
 			return err
 		}
 	}
 
+	if err := w.rfService.EnsureMonitoring(rf, labels, or); err != nil {
+		var notInstalled *rfservice.MonitoringNotInstalledError
+		if !errors.As(err, &notInstalled) {
+			return err
+		}
+		// The rest of the RedisFailover doesn't need the monitoring, so it is only warned about
+		w.recordMonitoringNotInstalled(rf, notInstalled)
+	} else {
+		w.monitoringWarned.forget(rf)
+	}
+
 	return nil
 }
+
+// operatorNetworkPolicyPeer returns the peer of the network policies selecting the operator pods
+func (w *RedisFailoverHandler) operatorNetworkPolicyPeer() networkingv1.NetworkPolicyPeer {
+	return rfservice.OperatorNetworkPolicyPeer(w.config.OperatorNamespace, w.config.OperatorPodLabels)
+}
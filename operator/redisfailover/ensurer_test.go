@@ -64,6 +64,8 @@ func TestEnsure(t *testing.T) {
 		exporter                    bool
 		bootstrapping               bool
 		bootstrappingAllowSentinels bool
+		sharedSentinels             bool
+		announceHostnames           bool
 	}{
 		{
 			name:                        "Call everything, use exporter",
@@ -89,6 +91,15 @@ func TestEnsure(t *testing.T) {
 			bootstrapping:               true,
 			bootstrappingAllowSentinels: true,
 		},
+		{
+			name:            "remove the sentinels when using a shared sentinel group",
+			sharedSentinels: true,
+		},
+		{
+			name:              "keep the redis service resolving the announced hostnames without exporter",
+			exporter:          false,
+			announceHostnames: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -99,19 +110,25 @@ func TestEnsure(t *testing.T) {
 			if test.bootstrapping {
 				rf.Spec.BootstrapNode.AllowSentinels = test.bootstrappingAllowSentinels
 			}
+			if test.sharedSentinels {
+				rf.Spec.Sentinel.SharedGroup = &redisfailoverv1.SharedSentinelGroup{Addresses: []string{"sentinel"}}
+			}
+			rf.Spec.AnnounceHostnames = test.announceHostnames
 
 			config := generateConfig()
 			mk := &mK8SService.Services{}
 			mrfc := &mRFService.RedisFailoverCheck{}
 			mrfh := &mRFService.RedisFailoverHeal{}
 			mrfs := &mRFService.RedisFailoverClient{}
-			if test.exporter {
+			if test.exporter || test.announceHostnames {
 				mrfs.On("EnsureRedisService", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			} else {
 				mrfs.On("EnsureNotPresentRedisService", rf).Once().Return(nil)
 			}
 
-			if !test.bootstrapping || test.bootstrappingAllowSentinels {
+			if test.sharedSentinels {
+				mrfs.On("EnsureNotPresentSentinel", rf).Once().Return(nil)
+			} else if !test.bootstrapping || test.bootstrappingAllowSentinels {
 				mrfs.On("EnsureSentinelService", rf, mock.Anything, mock.Anything).Once().Return(nil)
 				mrfs.On("EnsureSentinelConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
 				mrfs.On("EnsureSentinelDeployment", rf, mock.Anything, mock.Anything).Once().Return(nil)
@@ -122,7 +139,10 @@ func TestEnsure(t *testing.T) {
 			mrfs.On("EnsureRedisConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisShutdownConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisReadinessConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureNetworkPolicies", rf, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisStatefulset", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisStorageExpansion", rf).Once().Return(nil)
+			mrfs.On("EnsureMonitoring", rf, mock.Anything, mock.Anything).Once().Return(nil)
 
 			// Create the Kops client and call the valid logic.
 			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
@@ -23,9 +23,13 @@ import (
 )
 
 const (
-	resync       = 30 * time.Second
-	operatorName = "redis-operator"
-	lockKey      = "redis-failover-lease"
+	resync          = 30 * time.Second
+	operatorName    = "redis-operator"
+	lockKey         = "redis-failover-lease"
+	snapshotLockKey = "redis-failover-snapshot-lease"
+	// dryRunLockSuffix is added to the lock keys in dry run mode, so a dry run operator never takes
+	// the leadership from the one applying the changes.
+	dryRunLockSuffix = "-dry-run"
 )
 
 // New will create an operator that is responsible of managing all the required stuff
@@ -34,21 +38,35 @@ func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lo
 	// Create internal services.
 	rfService := rfservice.NewRedisFailoverKubeClient(k8sService, logger, kooperMetricsRecorder)
 	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
-	rfHealer := rfservice.NewRedisFailoverHealer(k8sService, redisClient, logger)
+	rfHealer := newRedisFailoverHealer(cfg, k8sService, redisClient, kooperMetricsRecorder, logger)
 
 	// Create the handlers.
 	rfHandler := NewRedisFailoverHandler(cfg, rfService, rfChecker, rfHealer, k8sService, kooperMetricsRecorder, logger)
-	rfRetriever := NewRedisFailoverRetriever(cfg, k8sService)
+	// The sentinel events trigger the handling of their RedisFailover through the watches of the retriever,
+	// so a failover done by the sentinels is reflected without waiting for the resync.
+	trigger := newReconcileTrigger(logger)
+	rfHandler.sentinelEvents = newSentinelEvents(redisClient, trigger.trigger, logger)
+	rfRetriever := trigger.retriever(NewRedisFailoverRetriever(cfg, k8sService, func(rf *redisfailoverv1.RedisFailover) {
+		// Deleted failovers are not handled anymore, release their redis connections.
+		rfHandler.sentinelEvents.forget(rf)
+		redisClient.CloseFailover(rf.Namespace, rf.Name)
+	}))
 
 	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailover")}
 	// Leader election service.
-	leSVC, err := leaderelection.NewDefault(lockKey, lockNamespace, k8sClient, kooperLogger)
+	leSVC, err := leaderelection.NewDefault(leaseLockKey(cfg, lockKey), lockNamespace, k8sClient, kooperLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	// The changes of the resources generated for the RedisFailovers trigger their handling too.
+	watches, err := newOwnedWatches(cfg, k8sClient, trigger.trigger, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create our controller.
-	return controller.New(&controller.Config{
+	ctrl, err := controller.New(&controller.Config{
 		Handler:           rfHandler,
 		Retriever:         rfRetriever,
 		LeaderElector:     leSVC,
@@ -58,9 +76,15 @@ func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lo
 		ResyncInterval:    resync,
 		ConcurrentWorkers: cfg.Concurrency,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return withOwnedWatches{Controller: ctrl, watches: watches}, nil
 }
 
-func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retriever {
+// NewRedisFailoverRetriever returns the retriever of the RedisFailovers in the supported namespaces.
+// onDelete, if not nil, is called for every RedisFailover deletion seen on the watch.
+func NewRedisFailoverRetriever(cfg Config, cli k8s.Services, onDelete func(rf *redisfailoverv1.RedisFailover)) controller.Retriever {
 	isNamespaceSupported := func(rf redisfailoverv1.RedisFailover) bool {
 		match, _ := regexp.Match(cfg.SupportedNamespacesRegex, []byte(rf.Namespace))
 		return match
@@ -91,13 +115,100 @@ func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retrieve
 				if !ok {
 					return event, false
 				}
-				return event, isNamespaceSupported(*rf)
+				if !isNamespaceSupported(*rf) {
+					return event, false
+				}
+				if event.Type == watch.Deleted && onDelete != nil {
+					onDelete(rf)
+				}
+				return event, true
+			})
+			return watcher, err
+		},
+	})
+}
+
+// NewSnapshotController will create a controller that is responsible of taking the snapshots of the
+// redis failovers requested through RedisFailoverSnapshots.
+func NewSnapshotController(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
+	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
+	rfHealer := newRedisFailoverHealer(cfg, k8sService, redisClient, kooperMetricsRecorder, logger)
+
+	rfsHandler := NewRedisFailoverSnapshotHandler(k8sService, rfChecker, rfHealer, logger)
+	rfsRetriever := NewRedisFailoverSnapshotRetriever(cfg, k8sService)
+
+	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailoversnapshot")}
+	leSVC, err := leaderelection.NewDefault(leaseLockKey(cfg, snapshotLockKey), lockNamespace, k8sClient, kooperLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	return controller.New(&controller.Config{
+		Handler:           rfsHandler,
+		Retriever:         rfsRetriever,
+		LeaderElector:     leSVC,
+		MetricsRecorder:   kooperMetricsRecorder,
+		Logger:            kooperLogger,
+		Name:              "redisfailoversnapshot",
+		ResyncInterval:    resync,
+		ConcurrentWorkers: cfg.Concurrency,
+	})
+}
+
+// NewRedisFailoverSnapshotRetriever returns the retriever of the RedisFailoverSnapshots in the supported namespaces.
+func NewRedisFailoverSnapshotRetriever(cfg Config, cli k8s.Services) controller.Retriever {
+	isNamespaceSupported := func(namespace string) bool {
+		match, _ := regexp.Match(cfg.SupportedNamespacesRegex, []byte(namespace))
+		return match
+	}
+
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			rfsList, err := cli.ListRedisFailoverSnapshots(context.Background(), "", options)
+			if err != nil {
+				return rfsList, err
+			}
+
+			targetRFSList := make([]redisfailoverv1.RedisFailoverSnapshot, 0)
+			for _, rfs := range rfsList.Items {
+				if isNamespaceSupported(rfs.Namespace) {
+					targetRFSList = append(targetRFSList, rfs)
+				}
+			}
+			rfsList.Items = targetRFSList
+
+			return rfsList, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watcher, err := cli.WatchRedisFailoverSnapshots(context.Background(), "", options)
+			watcher = watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+				rfs, ok := event.Object.(*redisfailoverv1.RedisFailoverSnapshot)
+				if !ok {
+					return event, false
+				}
+				return event, isNamespaceSupported(rfs.Namespace)
 			})
 			return watcher, err
 		},
 	})
 }
 
+// newRedisFailoverHealer returns the healer of the redis failovers, the one that only logs the commands
+// in dry run mode.
+func newRedisFailoverHealer(cfg Config, k8sService k8s.Services, redisClient redis.Client, metricsRecorder metrics.Recorder, logger log.Logger) rfservice.RedisFailoverHeal {
+	if cfg.DryRun {
+		return rfservice.NewRedisFailoverDryRunHealer(logger, metricsRecorder)
+	}
+	return rfservice.NewRedisFailoverHealer(k8sService, redisClient, logger)
+}
+
+func leaseLockKey(cfg Config, key string) string {
+	if cfg.DryRun {
+		return key + dryRunLockSuffix
+	}
+	return key
+}
+
 type kooperlogger struct {
 	log.Logger
 }
@@ -0,0 +1,85 @@
+package redisfailover
+
+import (
+	"context"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+)
+
+// finalize cleans up a RedisFailover being deleted following its deletion policy, and removes the
+// finalizer afterwards so the deletion can go on.
+func (r *RedisFailoverHandler) finalize(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if !rfservice.HasFinalizer(rf) {
+		return nil
+	}
+
+	// Work with the defaults set, but keep the stored object untouched for the finalizer removal.
+	defaulted := rf.DeepCopy()
+	if err := defaulted.Validate(); err != nil {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Invalid RedisFailover, skipping the deletion policy: %s", err)
+	} else {
+		if err := r.applyDeletionPolicy(ctx, defaulted); err != nil {
+			return err
+		}
+		if defaulted.SharedSentinels() {
+			r.removeSharedSentinelMonitor(ctx, defaulted)
+		}
+	}
+
+	r.masters.forget(rf)
+	r.warned.forget(rf)
+	r.monitoringWarned.forget(rf)
+	if r.sentinelEvents != nil {
+		r.sentinelEvents.forget(rf)
+	}
+	r.mClient.DeleteCluster(rf.Namespace, rf.Name)
+	return r.rfService.RemoveFinalizer(rf)
+}
+
+func (r *RedisFailoverHandler) applyDeletionPolicy(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Applying deletion policy %s", rf.Spec.DeletionPolicy)
+	switch rf.Spec.DeletionPolicy {
+	case redisfailoverv1.DeletionPolicyRetain:
+		return r.rfService.RetainRedisPersistentVolumeClaims(rf)
+	case redisfailoverv1.DeletionPolicySnapshot:
+		r.saveRedises(ctx, rf)
+		if err := r.rfService.SnapshotRedisPersistentVolumeClaims(rf); err != nil {
+			return err
+		}
+		return r.rfService.DeleteRedisPersistentVolumeClaims(rf)
+	default:
+		return r.rfService.DeleteRedisPersistentVolumeClaims(rf)
+	}
+}
+
+// saveRedises takes a final save of the dataset of every redis before their volumes are snapshotted.
+// A failing save doesn't stop the deletion, the volume keeps the last one the redis did.
+func (r *RedisFailoverHandler) saveRedises(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	redises, err := r.rfChecker.GetRedisesIPs(rf)
+	if err != nil {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Unable to get the redises for the final save: %s", err)
+		return
+	}
+	util.RunConcurrently(len(redises), maxConcurrentNodeOperations, func(i int) {
+		if err := r.rfHealer.BackgroundSave(ctx, redises[i], rf); err != nil {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Final save of redis %s failed: %s", redises[i], err)
+		}
+	})
+}
+
+// removeSharedSentinelMonitor makes the shared sentinels forget the master, as they outlive the RedisFailover.
+// A failing removal doesn't stop the deletion, the master is left down on those sentinels.
+func (r *RedisFailoverHandler) removeSharedSentinelMonitor(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	if err != nil {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Unable to get the shared sentinels to remove the master: %s", err)
+		return
+	}
+	util.RunConcurrently(len(sentinels), maxConcurrentNodeOperations, func(i int) {
+		if err := r.rfHealer.RemoveSentinelMonitor(ctx, sentinels[i], rf); err != nil {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Removing the master from sentinel %s failed: %s", sentinels[i], err)
+		}
+	})
+}
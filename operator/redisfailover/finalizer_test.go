@@ -0,0 +1,112 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestFinalize(t *testing.T) {
+	tests := []struct {
+		name           string
+		deletionPolicy redisfailoverv1.DeletionPolicy
+		storage        redisfailoverv1.RedisStorage
+		poolRef        *redisfailoverv1.SentinelPoolReference
+		finalizer      bool
+	}{
+		{
+			name:      "Delete policy deletes the volume claims",
+			finalizer: true,
+		},
+		{
+			name:      "Retain policy by keepAfterDeletion retains the volume claims",
+			storage:   redisfailoverv1.RedisStorage{KeepAfterDeletion: true},
+			finalizer: true,
+		},
+		{
+			name:           "Snapshot policy saves and snapshots the volume claims before deleting them",
+			deletionPolicy: redisfailoverv1.DeletionPolicySnapshot,
+			storage: redisfailoverv1.RedisStorage{
+				PersistentVolumeClaim: &redisfailoverv1.EmbeddedPersistentVolumeClaim{},
+			},
+			finalizer: true,
+		},
+		{
+			name:      "Pooled sentinels forget the master",
+			poolRef:   &redisfailoverv1.SentinelPoolReference{Name: "pool"},
+			finalizer: true,
+		},
+		{
+			name:           "Invalid policy only removes the finalizer",
+			deletionPolicy: "Orphan",
+			finalizer:      true,
+		},
+		{
+			name:      "Without finalizer nothing is done",
+			finalizer: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.DeletionPolicy = test.deletionPolicy
+			rf.Spec.Redis.Storage = test.storage
+			if test.poolRef != nil {
+				rf.Spec.Sentinel.MasterName = "cache"
+				rf.Spec.Sentinel.PoolRef = test.poolRef
+			}
+			now := metav1.Now()
+			rf.DeletionTimestamp = &now
+			if test.finalizer {
+				rf.Finalizers = []string{rfservice.RedisFailoverFinalizer}
+			}
+
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			if test.finalizer {
+				switch {
+				case test.deletionPolicy == redisfailoverv1.DeletionPolicySnapshot:
+					mrfc.On("GetRedisesIPs", mock.Anything).Once().Return([]string{"0.0.0.1", "0.0.0.2"}, nil)
+					mrfh.On("BackgroundSave", mock.Anything, "0.0.0.1", mock.Anything).Once().Return(nil)
+					mrfh.On("BackgroundSave", mock.Anything, "0.0.0.2", mock.Anything).Once().Return(nil)
+					mrfs.On("SnapshotRedisPersistentVolumeClaims", mock.Anything).Once().Return(nil)
+					mrfs.On("DeleteRedisPersistentVolumeClaims", mock.Anything).Once().Return(nil)
+				case test.storage.KeepAfterDeletion:
+					mrfs.On("RetainRedisPersistentVolumeClaims", mock.Anything).Once().Return(nil)
+				case test.deletionPolicy == "":
+					mrfs.On("DeleteRedisPersistentVolumeClaims", mock.Anything).Once().Return(nil)
+				}
+				if test.poolRef != nil {
+					mrfc.On("GetSentinelsIPs", mock.Anything).Once().Return([]string{"0.0.0.3", "0.0.0.4"}, nil)
+					mrfh.On("RemoveSentinelMonitor", mock.Anything, "0.0.0.3", mock.Anything).Once().Return(nil)
+					mrfh.On("RemoveSentinelMonitor", mock.Anything, "0.0.0.4", mock.Anything).Once().Return(nil)
+				}
+				mrfs.On("RemoveFinalizer", rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mK8SService.Services{}, metrics.Dummy, log.Dummy)
+			err := handler.Handle(context.Background(), rf)
+
+			assert.NoError(err)
+			mrfs.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +22,18 @@ const (
 	rfLabelNameKey      = "redisfailovers.databases.spotahome.com/name"
 )
 
+// Reasons of the events recorded on the RedisFailovers
+const (
+	emptyMasterFailoverReason = "EmptyMasterFailover"
+	configDriftReason         = "ConfigDrift"
+	pausedReason              = "Paused"
+	resumedReason             = "Resumed"
+	specWarningReason         = "SpecWarning"
+	// monitoringNotInstalledReason is the reason of the event recorded when the prometheus-operator CRDs are
+	// not installed
+	monitoringNotInstalledReason = "MonitoringNotInstalled"
+)
+
 var (
 	defaultLabels = map[string]string{
 		rfLabelManagedByKey: operatorName,
@@ -31,38 +44,82 @@ var (
 // resources that a RF needs.
 type RedisFailoverHandler struct {
 	config     Config
-	k8sservice k8s.Service
+	k8sservice k8s.Services
 	rfService  rfservice.RedisFailoverClient
 	rfChecker  rfservice.RedisFailoverCheck
 	rfHealer   rfservice.RedisFailoverHeal
 	mClient    metrics.Recorder
 	logger     log.Logger
+	masters    *knownMasters
+	warned     *warnedGenerations
+	// monitoringWarned keeps the generations whose missing prometheus-operator CRDs were recorded
+	monitoringWarned *warnedGenerations
+	// sentinelEvents, when set, makes the events of the sentinels trigger the handling of their RedisFailover
+	sentinelEvents *sentinelEvents
+	// paused is set on the handler of the paused RedisFailovers, see pausedHandler
+	paused bool
 }
 
 // NewRedisFailoverHandler returns a new RF handler
-func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, k8sservice k8s.Service, mClient metrics.Recorder, logger log.Logger) *RedisFailoverHandler {
+func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, k8sservice k8s.Services, mClient metrics.Recorder, logger log.Logger) *RedisFailoverHandler {
 	return &RedisFailoverHandler{
-		config:     config,
-		rfService:  rfService,
-		rfChecker:  rfChecker,
-		rfHealer:   rfHealer,
-		mClient:    mClient,
-		k8sservice: k8sservice,
-		logger:     logger,
+		config:           config,
+		rfService:        rfService,
+		rfChecker:        rfChecker,
+		rfHealer:         rfHealer,
+		mClient:          mClient,
+		k8sservice:       k8sservice,
+		logger:           logger,
+		masters:          newKnownMasters(),
+		warned:           newWarnedGenerations(),
+		monitoringWarned: newWarnedGenerations(),
 	}
 }
 
 // Handle will ensure the redis failover is in the expected state.
-func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) error {
+func (r *RedisFailoverHandler) Handle(ctx context.Context, obj runtime.Object) error {
 	rf, ok := obj.(*redisfailoverv1.RedisFailover)
 	if !ok {
 		return fmt.Errorf("can't handle the received object: not a redisfailover")
 	}
 
-	if err := rf.Validate(); err != nil {
+	if rf.DeletionTimestamp != nil {
+		return r.finalize(ctx, rf)
+	}
+
+	start := time.Now()
+	err := r.reconcile(ctx, rf)
+	r.mClient.ObserveReconcileDuration(rf.Namespace, rf.Name, time.Since(start))
+	if err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
+	r.mClient.SetClusterOK(rf.Namespace, rf.Name)
+	r.mClient.SetLastSuccessfulReconcile(rf.Namespace, rf.Name, time.Now())
+	return nil
+}
+
+// reconcile brings the resources and the nodes of the RedisFailover to the expected state
+func (r *RedisFailoverHandler) reconcile(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if err := r.rfService.EnsureFinalizer(rf); err != nil {
+		return err
+	}
+
+	if err := rf.Validate(); err != nil {
+		return err
+	}
+
+	r.recordWarnings(rf)
+
+	paused := rf.Paused(time.Now())
+	if err := r.updatePausedStatus(rf, paused); err != nil {
+		return err
+	}
+
+	// A paused RedisFailover is only checked, its resources are left as they are
+	if paused {
+		return r.pausedHandler().CheckAndHeal(ctx, rf)
+	}
 
 	// Create owner refs so the objects manager by this handler have ownership to the
 	// received RF.
@@ -72,17 +129,10 @@ func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) err
 	labels := r.getLabels(rf)
 
 	if err := r.Ensure(rf, labels, oRefs, r.mClient); err != nil {
-		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
 
-	if err := r.CheckAndHeal(rf); err != nil {
-		r.mClient.SetClusterError(rf.Namespace, rf.Name)
-		return err
-	}
-
-	r.mClient.SetClusterOK(rf.Namespace, rf.Name)
-	return nil
+	return r.CheckAndHeal(ctx, rf)
 }
 
 // getLabels merges the labels (dynamic and operator static ones).
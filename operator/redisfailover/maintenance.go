@@ -0,0 +1,29 @@
+package redisfailover
+
+import (
+	"context"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+)
+
+// setPendingOperation records on the status and the metrics of the RedisFailover whether the disruptive
+// operation waits for a maintenance window. An empty message means it is not pending.
+func (r *RedisFailoverHandler) setPendingOperation(rf *redisfailoverv1.RedisFailover, operation redisfailoverv1.PendingOperationType, message string) error {
+	r.mClient.SetPendingOperation(rf.Namespace, rf.Name, string(operation), message != "")
+	rfCopy := rf.DeepCopy()
+	if !rfCopy.Status.SetPendingOperation(operation, message) {
+		return nil
+	}
+	if message != "" {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Waiting for a maintenance window for the %s", message)
+	}
+	updated, err := r.k8sservice.UpdateRedisFailoverStatus(context.TODO(), rfCopy)
+	if err != nil {
+		return err
+	}
+	if updated != nil {
+		rf.ResourceVersion = updated.ResourceVersion
+	}
+	rf.Status.PendingOperations = rfCopy.Status.PendingOperations
+	return nil
+}
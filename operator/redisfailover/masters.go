@@ -0,0 +1,85 @@
+package redisfailover
+
+import (
+	"fmt"
+	"sync"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// knownMasters keeps the pod last seen working as master of every RedisFailover, so the changes of
+// master can be counted along with who made them.
+type knownMasters struct {
+	mu               sync.Mutex
+	pod              map[string]string // RedisFailover key -> master pod name
+	chosenByOperator map[string]bool   // RedisFailover key -> the operator has just chosen a new master
+}
+
+func newKnownMasters() *knownMasters {
+	return &knownMasters{
+		pod:              map[string]string{},
+		chosenByOperator: map[string]bool{},
+	}
+}
+
+func knownMastersKey(rf *redisfailoverv1.RedisFailover) string {
+	return fmt.Sprintf("%s/%s", rf.Namespace, rf.Name)
+}
+
+// choose records that the operator has chosen the master, so its change is not taken as done by the
+// sentinels once it is seen
+func (k *knownMasters) choose(rf *redisfailoverv1.RedisFailover) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.chosenByOperator[knownMastersKey(rf)] = true
+}
+
+// set records the pod working as master, returning the reason of the change when it replaces a different
+// one. The first master seen is not a change, as it could be the same the operator saw before restarting.
+func (k *knownMasters) set(rf *redisfailoverv1.RedisFailover, pod string) (reason string, changed bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key := knownMastersKey(rf)
+	previous, known := k.pod[key]
+	k.pod[key] = pod
+	reason = metrics.MASTER_CHANGE_BY_SENTINEL
+	if k.chosenByOperator[key] {
+		reason = metrics.MASTER_CHANGE_BY_OPERATOR
+	}
+	delete(k.chosenByOperator, key)
+	return reason, known && previous != pod
+}
+
+// forget removes everything known about the RedisFailover
+func (k *knownMasters) forget(rf *redisfailoverv1.RedisFailover) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.pod, knownMastersKey(rf))
+	delete(k.chosenByOperator, knownMastersKey(rf))
+}
+
+// masterChosen records that the operator has just chosen the master of the RedisFailover. Nothing is
+// chosen while paused or in dry run mode, as the healing actions are skipped.
+func (r *RedisFailoverHandler) masterChosen(rf *redisfailoverv1.RedisFailover) {
+	if r.paused || r.config.DryRun {
+		return
+	}
+	r.masters.choose(rf)
+}
+
+// recordReplicationState records the master of the snapshot, counting its changes, and the replication
+// lag of its slaves
+func (r *RedisFailoverHandler) recordReplicationState(rf *redisfailoverv1.RedisFailover, snapshot *rfservice.RedisFailoverSnapshot) {
+	master, err := snapshot.Master()
+	if err != nil {
+		return
+	}
+	if reason, changed := r.masters.set(rf, master.PodName); changed {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Master changed to %s, chosen by the %s", master.PodName, reason)
+		r.mClient.RecordMasterChange(rf.Namespace, rf.Name, reason)
+	}
+	r.mClient.SetMaster(rf.Namespace, rf.Name, master.PodName)
+	r.mClient.SetReplicationLags(rf.Namespace, rf.Name, snapshot.ReplicationLags())
+}
@@ -0,0 +1,92 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// replicationRecorder keeps the replication state recorded for a RedisFailover
+type replicationRecorder struct {
+	metrics.Recorder
+	master        string
+	masterChanges []string
+	lags          map[string]int64
+}
+
+func (r *replicationRecorder) SetMaster(_, _, pod string) {
+	r.master = pod
+}
+
+func (r *replicationRecorder) RecordMasterChange(_, _, reason string) {
+	r.masterChanges = append(r.masterChanges, reason)
+}
+
+func (r *replicationRecorder) SetReplicationLags(_, _ string, lags map[string]int64) {
+	r.lags = lags
+}
+
+func TestRecordMasterChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Spec.Redis.Replicas = 1
+	stop := errors.New("stop")
+
+	withMaster := func(master string) *rfservice.RedisFailoverSnapshot {
+		snapshot := &rfservice.RedisFailoverSnapshot{}
+		for _, pod := range []string{"rfr-0", "rfr-1"} {
+			node := rfservice.RedisNodeState{PodName: pod, Address: pod, SlaveOf: master, ReplOffset: 90}
+			if pod == master {
+				node = rfservice.RedisNodeState{PodName: pod, Address: pod, IsMaster: true, ReplOffset: 100, Keys: 1}
+			}
+			snapshot.Redises = append(snapshot.Redises, node)
+		}
+		return snapshot
+	}
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfc.On("IsRedisRunning", rf).Return(true)
+	mrfc.On("IsSentinelRunning", rf).Return(true)
+	// The first master known, then the operator sets a new one and at last the sentinels fail over
+	mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(withMaster("rfr-0"), nil)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(withMaster(""), nil)
+	mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(withMaster("rfr-1"), nil)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Once().Return(withMaster("rfr-0"), nil)
+	// The check is stopped once the replication state is recorded
+	mrfc.On("UpdateRoleLabels", rf, mock.Anything, mock.Anything).Return(stop)
+
+	recorder := &replicationRecorder{Recorder: metrics.Dummy}
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, recorder, log.Dummy)
+
+	assert.ErrorIs(handler.CheckAndHeal(context.TODO(), rf), stop)
+	assert.Equal("rfr-0", recorder.master)
+	assert.Empty(recorder.masterChanges)
+	assert.Equal(map[string]int64{"rfr-1": 10}, recorder.lags)
+
+	assert.NoError(handler.CheckAndHeal(context.TODO(), rf))
+	assert.ErrorIs(handler.CheckAndHeal(context.TODO(), rf), stop)
+	assert.Equal("rfr-1", recorder.master)
+	assert.Equal([]string{metrics.MASTER_CHANGE_BY_OPERATOR}, recorder.masterChanges)
+
+	assert.ErrorIs(handler.CheckAndHeal(context.TODO(), rf), stop)
+	assert.Equal("rfr-0", recorder.master)
+	assert.Equal([]string{metrics.MASTER_CHANGE_BY_OPERATOR, metrics.MASTER_CHANGE_BY_SENTINEL}, recorder.masterChanges)
+
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package redisfailover
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/spotahome/kooper/v2/controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/spotahome/redis-operator/log"
+)
+
+// ownedWatches watches the resources generated for the RedisFailovers, found by the label with the name of their
+// RedisFailover, and triggers the handling of the RedisFailover when they are changed or deleted. The additions
+// are not watched, as the resources are created by the operator itself.
+type ownedWatches struct {
+	factory              informers.SharedInformerFactory
+	isNamespaceSupported func(namespace string) bool
+	trigger              func(namespace, name string)
+	logger               log.Logger
+}
+
+func newOwnedWatches(cfg Config, k8sClient kubernetes.Interface, trigger func(namespace, name string), logger log.Logger) (*ownedWatches, error) {
+	namespaceRE, err := regexp.Compile(cfg.SupportedNamespacesRegex)
+	if err != nil {
+		return nil, err
+	}
+	o := &ownedWatches{
+		factory: informers.NewSharedInformerFactoryWithOptions(k8sClient, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = rfLabelNameKey
+		})),
+		isNamespaceSupported: namespaceRE.MatchString,
+		trigger:              trigger,
+		logger:               logger,
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldMeta, err := meta.Accessor(oldObj)
+			if err != nil {
+				return
+			}
+			newMeta, err := meta.Accessor(newObj)
+			if err != nil || oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+				return
+			}
+			o.enqueue(newMeta)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			objMeta, err := meta.Accessor(obj)
+			if err != nil {
+				return
+			}
+			o.enqueue(objMeta)
+		},
+	}
+	for _, informer := range []cache.SharedIndexInformer{
+		o.factory.Apps().V1().StatefulSets().Informer(),
+		o.factory.Apps().V1().Deployments().Informer(),
+		o.factory.Core().V1().Services().Informer(),
+		o.factory.Core().V1().ConfigMaps().Informer(),
+		o.factory.Core().V1().Pods().Informer(),
+		o.factory.Policy().V1().PodDisruptionBudgets().Informer(),
+	} {
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *ownedWatches) enqueue(obj metav1.Object) {
+	name := obj.GetLabels()[rfLabelNameKey]
+	if name == "" || !o.isNamespaceSupported(obj.GetNamespace()) {
+		return
+	}
+	o.logger.WithField("redisfailover", name).WithField("namespace", obj.GetNamespace()).Debugf("Owned resource %s changed", obj.GetName())
+	o.trigger(obj.GetNamespace(), name)
+}
+
+// withOwnedWatches runs the watches of the owned resources along with the controller
+type withOwnedWatches struct {
+	controller.Controller
+	watches *ownedWatches
+}
+
+func (c withOwnedWatches) Run(ctx context.Context) error {
+	c.watches.factory.Start(ctx.Done())
+	defer c.watches.factory.Shutdown()
+	return c.Controller.Run(ctx)
+}
@@ -0,0 +1,75 @@
+package redisfailover
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spotahome/redis-operator/log"
+)
+
+func TestOwnedWatches(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "rfr-test",
+			Namespace:       "testns",
+			ResourceVersion: "1",
+			Labels:          map[string]string{rfLabelNameKey: "test"},
+		},
+	}
+	unsupported := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "rfr-other",
+			Namespace:       "otherns",
+			ResourceVersion: "1",
+			Labels:          map[string]string{rfLabelNameKey: "other"},
+		},
+	}
+	k8sClient := fake.NewSimpleClientset(ss, unsupported)
+
+	triggered := make(chan string, 10)
+	watches, err := newOwnedWatches(Config{SupportedNamespacesRegex: "^testns$"}, k8sClient, func(namespace, name string) {
+		triggered <- namespace + "/" + name
+	}, log.Dummy)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watches.factory.Start(ctx.Done())
+	for _, synced := range watches.factory.WaitForCacheSync(ctx.Done()) {
+		require.True(synced)
+	}
+
+	// The existing resources don't trigger anything
+	assert.Empty(triggered)
+
+	ss.ResourceVersion = "2"
+	ss.Spec.Replicas = new(int32)
+	_, err = k8sClient.AppsV1().StatefulSets("testns").Update(ctx, ss, metav1.UpdateOptions{})
+	require.NoError(err)
+	unsupported.ResourceVersion = "2"
+	unsupported.Data = map[string]string{"changed": "true"}
+	_, err = k8sClient.CoreV1().ConfigMaps("otherns").Update(ctx, unsupported, metav1.UpdateOptions{})
+	require.NoError(err)
+	require.NoError(k8sClient.AppsV1().StatefulSets("testns").Delete(ctx, ss.Name, metav1.DeleteOptions{}))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case key := <-triggered:
+			assert.Equal("testns/test", key)
+		case <-time.After(5 * time.Second):
+			assert.Fail("RedisFailover not triggered")
+		}
+	}
+	assert.Empty(triggered)
+}
@@ -0,0 +1,106 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// pausedHandler returns a copy of the handler that runs the checks of a paused RedisFailover, recording
+// their metrics, while every healing action is skipped
+func (r *RedisFailoverHandler) pausedHandler() *RedisFailoverHandler {
+	paused := *r
+	paused.paused = true
+	paused.rfHealer = &pausedHealer{logger: r.logger}
+	return &paused
+}
+
+// updatePausedStatus records the pause and the resume of the RedisFailover on its status, with an event
+func (r *RedisFailoverHandler) updatePausedStatus(rf *redisfailoverv1.RedisFailover, paused bool) error {
+	if rf.Status.Paused == paused {
+		return nil
+	}
+	rfCopy := rf.DeepCopy()
+	rfCopy.Status.Paused = paused
+	updated, err := r.k8sservice.UpdateRedisFailoverStatus(context.TODO(), rfCopy)
+	if err != nil {
+		return err
+	}
+	if updated != nil {
+		rf.ResourceVersion = updated.ResourceVersion
+	}
+	rf.Status.Paused = paused
+
+	reason, message := resumedReason, "Healing resumed"
+	if paused {
+		reason, message = pausedReason, "Healing paused, the checks keep running but nothing is fixed until it is resumed"
+	}
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Info(message)
+	r.k8sservice.RecordEvent(rf, corev1.EventTypeNormal, reason, message)
+	return nil
+}
+
+// pausedHealer is the healer of the paused RedisFailovers, it only logs the actions that would be taken
+type pausedHealer struct {
+	logger log.Logger
+}
+
+var _ rfservice.RedisFailoverHeal = &pausedHealer{}
+
+func (p *pausedHealer) skip(rFailover *redisfailoverv1.RedisFailover, format string, args ...interface{}) error {
+	p.logger.WithField("redisfailover", rFailover.ObjectMeta.Name).WithField("namespace", rFailover.ObjectMeta.Namespace).Infof("Paused, skipping: %s", fmt.Sprintf(format, args...))
+	return nil
+}
+
+func (p *pausedHealer) MakeMaster(_ context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "make %s master", ip)
+}
+
+func (p *pausedHealer) SetOldestAsMaster(_ context.Context, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "set oldest redis as master")
+}
+
+func (p *pausedHealer) SetMasterOnAll(_ context.Context, masterIP string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "set %s as master of all the redis", masterIP)
+}
+
+func (p *pausedHealer) SetExternalMasterOnAll(_ context.Context, masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "set %s:%s as master of all the redis", masterIP, masterPort)
+}
+
+func (p *pausedHealer) NewSentinelMonitor(_ context.Context, ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "make sentinel %s monitor %s", ip, monitor)
+}
+
+func (p *pausedHealer) NewSentinelMonitorWithPort(_ context.Context, ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "make sentinel %s monitor %s:%s", ip, monitor, port)
+}
+
+func (p *pausedHealer) RestoreSentinel(_ context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "restore sentinel %s", ip)
+}
+
+func (p *pausedHealer) RemoveSentinelMonitor(_ context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "remove the monitor of sentinel %s", ip)
+}
+
+func (p *pausedHealer) SetSentinelCustomConfig(_ context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "set custom config of sentinel %s", ip)
+}
+
+func (p *pausedHealer) SetRedisCustomConfig(_ context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "set custom config of redis %s", ip)
+}
+
+func (p *pausedHealer) DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "delete pod %s", podName)
+}
+
+func (p *pausedHealer) BackgroundSave(_ context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error {
+	return p.skip(rFailover, "save redis %s", ip)
+}
@@ -0,0 +1,101 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestHandlePaused(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Annotations = map[string]string{redisfailoverv1.PausedAnnotation: "true"}
+	master := "0.0.0.0"
+	slave := "0.0.0.1"
+	sentinel := "1.1.1.1"
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	// The slave is outdated and the sentinel monitors another master, but nothing is healed nor deleted
+	snapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "master", PodUID: "1", Address: master, IsMaster: true, RevisionHash: "1"},
+			{PodName: "slave", PodUID: "2", Address: slave, SlaveOf: master, SlaveReady: true, RevisionHash: "0"},
+		},
+		Sentinels: []rfservice.SentinelNodeState{
+			{
+				PodUID:            "3",
+				Address:           sentinel,
+				MonitorIP:         "0.0.0.2",
+				MonitorPort:       "0",
+				SentinelsInMemory: rf.Spec.Sentinel.Replicas,
+				SlavesInMemory:    rf.Spec.Redis.Replicas - 1,
+			},
+		},
+		SentinelReplicas: rf.Spec.Sentinel.Replicas,
+	}
+
+	mrfs.On("EnsureFinalizer", rf).Return(nil)
+	mk.On("UpdateRedisFailoverStatus", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		return updated.Status.Paused
+	})).Once().Return(nil, nil)
+	mk.On("RecordEvent", rf, corev1.EventTypeNormal, "Paused", mock.Anything).Once()
+	mrfc.On("IsRedisRunning", rf).Times(2).Return(true)
+	mrfc.On("IsSentinelRunning", rf).Times(2).Return(true)
+	mrfc.On("GetSnapshot", mock.Anything, rf).Times(2).Return(snapshot, nil)
+	mrfc.On("GetStatefulSetUpdateRevision", rf).Times(2).Return("1", nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	assert.NoError(handler.Handle(context.Background(), rf))
+	assert.True(rf.Status.Paused)
+	// The pause is only recorded once
+	assert.NoError(handler.Handle(context.Background(), rf))
+
+	mk.AssertExpectations(t)
+	mrfs.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+func TestHandleResumed(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Status.Paused = true
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	mrfs.On("EnsureFinalizer", rf).Once().Return(nil)
+	mk.On("UpdateRedisFailoverStatus", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		return !updated.Status.Paused
+	})).Once().Return(nil, nil)
+	mk.On("RecordEvent", rf, corev1.EventTypeNormal, "Resumed", "Healing resumed").Once()
+	// The resources are ensured again
+	mrfs.On("EnsureNotPresentRedisService", rf).Once().Return(errors.New("stop"))
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	assert.EqualError(handler.Handle(context.Background(), rf), "stop")
+	assert.False(rf.Status.Paused)
+
+	mk.AssertExpectations(t)
+	mrfs.AssertExpectations(t)
+}
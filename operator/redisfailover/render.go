@@ -0,0 +1,71 @@
+package redisfailover
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// Render validates the RedisFailover and returns the objects the operator generates for it, with the labels
+// and owner references they get when it is handled. The operator pods allowed by the network policies are
+// every pod of the namespace of the RedisFailover, as the namespace of the operator is not known.
+func Render(rf *redisfailoverv1.RedisFailover) ([]runtime.Object, error) {
+	if err := rf.Validate(); err != nil {
+		return nil, err
+	}
+	r := &RedisFailoverHandler{logger: log.Dummy}
+	objects := rfservice.Render(rf, r.getLabels(rf), r.createOwnerReferences(rf), r.operatorNetworkPolicyPeer())
+	for _, object := range objects {
+		kinds, _, err := scheme.Scheme.ObjectKinds(object)
+		if err != nil {
+			return nil, err
+		}
+		object.GetObjectKind().SetGroupVersionKind(kinds[0])
+	}
+	return objects, nil
+}
+
+// RenderManifests reads the RedisFailovers of a YAML or JSON stream, with one or more documents, and writes
+// the YAML of the objects generated for them. The documents of other kinds are ignored.
+func RenderManifests(in io.Reader, out io.Writer) error {
+	decoder := yaml.NewYAMLOrJSONDecoder(in, 4096)
+	encoder := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, json.SerializerOptions{Yaml: true})
+	rendered := 0
+	for document := 1; ; document++ {
+		rf := &redisfailoverv1.RedisFailover{}
+		if err := decoder.Decode(rf); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("document %d: %w", document, err)
+		}
+		if rf.Kind != redisfailoverv1.RFKind {
+			continue
+		}
+
+		objects, err := Render(rf)
+		if err != nil {
+			return fmt.Errorf("redisfailover %s: %w", rf.Name, err)
+		}
+		for _, object := range objects {
+			if rendered > 0 {
+				if _, err := io.WriteString(out, "---\n"); err != nil {
+					return err
+				}
+			}
+			if err := encoder.Encode(object, out); err != nil {
+				return err
+			}
+			rendered++
+		}
+	}
+	return nil
+}
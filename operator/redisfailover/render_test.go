@@ -0,0 +1,119 @@
+package redisfailover_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the rendered examples")
+
+// TestRenderExamples renders the examples of RedisFailovers and compares the objects with the golden files
+// of testdata/render, regenerated with `go test ./operator/redisfailover/ -run TestRenderExamples -update`.
+func TestRenderExamples(t *testing.T) {
+	examples, err := filepath.Glob("../../example/redisfailover/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, examples)
+
+	for _, example := range examples {
+		name := strings.TrimSuffix(filepath.Base(example), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			in, err := os.Open(example)
+			require.NoError(err)
+			defer in.Close()
+			out := &bytes.Buffer{}
+			require.NoError(rfOperator.RenderManifests(in, out))
+
+			golden := filepath.Join("testdata", "render", name+".golden")
+			if *update {
+				require.NoError(os.MkdirAll(filepath.Dir(golden), 0o755))
+				require.NoError(os.WriteFile(golden, out.Bytes(), 0o644))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(err)
+			assert.Equal(string(expected), out.String())
+		})
+	}
+}
+
+func TestRenderManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expKinds []string
+		expErr   bool
+	}{
+		{
+			name: "The documents that are not RedisFailovers should be ignored.",
+			manifest: `apiVersion: v1
+kind: Secret
+metadata:
+  name: redis-auth
+---
+apiVersion: databases.spotahome.com/v1
+kind: RedisFailover
+metadata:
+  name: test
+spec:
+  redis:
+    exporter:
+      enabled: true
+`,
+			expKinds: []string{"Service", "Service", "ConfigMap", "Service", "Service", "ConfigMap", "ConfigMap", "ConfigMap", "PodDisruptionBudget", "StatefulSet", "PodDisruptionBudget", "Deployment"},
+		},
+		{
+			name: "The sentinels should not be rendered for a bootstrapping RedisFailover.",
+			manifest: `apiVersion: databases.spotahome.com/v1
+kind: RedisFailover
+metadata:
+  name: test
+spec:
+  bootstrapNode:
+    host: 127.0.0.1
+`,
+			expKinds: []string{"Service", "Service", "ConfigMap", "ConfigMap", "ConfigMap", "PodDisruptionBudget", "StatefulSet"},
+		},
+		{
+			name: "An invalid RedisFailover should fail.",
+			manifest: `apiVersion: databases.spotahome.com/v1
+kind: RedisFailover
+metadata:
+  name: test-with-a-name-that-is-too-long-to-be-valid-for-a-redisfailover
+`,
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			out := &bytes.Buffer{}
+			err := rfOperator.RenderManifests(strings.NewReader(test.manifest), out)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+
+			kinds := []string{}
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, "kind: ") {
+					kinds = append(kinds, strings.TrimPrefix(line, "kind: "))
+				}
+			}
+			assert.Equal(test.expKinds, kinds)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package redisfailover
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+const switchMasterChannel = "+switch-master"
+
+// sentinelEventPatterns are the channels of the sentinel events that make a RedisFailover be handled right away,
+// as its master or its role labels may have changed
+var sentinelEventPatterns = []string{switchMasterChannel, "+sdown", "+odown", "-failover-abort-*"}
+
+// sentinelEvents subscribes to the events of the sentinels monitoring every RedisFailover, and triggers
+// the handling of the RedisFailover when its master is involved in one of them.
+type sentinelEvents struct {
+	redisClient redis.Client
+	trigger     func(namespace, name string)
+	logger      log.Logger
+
+	mu            sync.Mutex
+	subscriptions map[string]map[string]*sentinelSubscription // RedisFailover key -> sentinel address -> subscription
+}
+
+type sentinelSubscription struct {
+	masterName string
+	cancel     context.CancelFunc
+}
+
+func newSentinelEvents(redisClient redis.Client, trigger func(namespace, name string), logger log.Logger) *sentinelEvents {
+	return &sentinelEvents{
+		redisClient:   redisClient,
+		trigger:       trigger,
+		logger:        logger,
+		subscriptions: map[string]map[string]*sentinelSubscription{},
+	}
+}
+
+func sentinelEventsKey(rf *redisfailoverv1.RedisFailover) string {
+	return rf.Namespace + "/" + rf.Name
+}
+
+// watch subscribes to the events of the given sentinels of the RedisFailover, and cancels the subscriptions
+// to the sentinels that are gone. The sentinels that can't be subscribed are retried on the next call.
+func (s *sentinelEvents) watch(rf *redisfailoverv1.RedisFailover, sentinels []string) {
+	key := sentinelEventsKey(rf)
+	masterName := rf.Spec.Sentinel.MasterName
+
+	s.mu.Lock()
+	current := s.subscriptions[key]
+	if current == nil {
+		current = map[string]*sentinelSubscription{}
+		s.subscriptions[key] = current
+	}
+	wanted := make(map[string]bool, len(sentinels))
+	missing := []string{}
+	for _, address := range sentinels {
+		wanted[address] = true
+		if sub, ok := current[address]; !ok || sub.masterName != masterName {
+			missing = append(missing, address)
+		}
+	}
+	for address, sub := range current {
+		if !wanted[address] || sub.masterName != masterName {
+			sub.cancel()
+			delete(current, address)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, address := range missing {
+		ctx, cancel := context.WithCancel(redis.WithMasterName(redis.WithFailover(context.Background(), rf.Namespace, rf.Name), masterName))
+		events, err := s.redisClient.WatchSentinelEvents(ctx, address, sentinelEventPatterns...)
+		if err != nil {
+			cancel()
+			s.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Unable to subscribe to the events of sentinel %s: %s", address, err)
+			continue
+		}
+		sub := &sentinelSubscription{masterName: masterName, cancel: cancel}
+
+		s.mu.Lock()
+		if current, ok := s.subscriptions[key]; ok {
+			if previous, ok := current[address]; ok {
+				previous.cancel()
+			}
+			current[address] = sub
+		} else {
+			// Forgotten meanwhile
+			cancel()
+		}
+		s.mu.Unlock()
+
+		go s.receive(rf.Namespace, rf.Name, address, sub, events)
+	}
+}
+
+// receive triggers the RedisFailover for the events of its master until the subscription is cancelled or
+// its events are closed, as when the sentinel restarts. The subscription is then removed, so the next
+// watch subscribes again.
+func (s *sentinelEvents) receive(namespace, name, address string, sub *sentinelSubscription, events <-chan redis.SentinelEvent) {
+	for event := range events {
+		if sentinelEventMasterName(event) != sub.masterName {
+			continue
+		}
+		s.logger.WithField("redisfailover", name).WithField("namespace", namespace).Debugf("Sentinel event %s %s", event.Channel, event.Payload)
+		s.trigger(namespace, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub.cancel()
+	// It may have been replaced meanwhile
+	if current := s.subscriptions[namespace+"/"+name]; current[address] == sub {
+		delete(current, address)
+	}
+}
+
+// forget cancels every subscription of the RedisFailover
+func (s *sentinelEvents) forget(rf *redisfailoverv1.RedisFailover) {
+	key := sentinelEventsKey(rf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subscriptions[key] {
+		sub.cancel()
+	}
+	delete(s.subscriptions, key)
+}
+
+// sentinelEventMasterName returns the name of the master the event is about. The switch of master is
+// published as "<master name> <old ip> <old port> <new ip> <new port>", and the rest of events as
+// "<instance type> <name> <ip> <port>", followed by "@ <master name> <master ip> <master port>" when the
+// instance is not a master.
+func sentinelEventMasterName(event redis.SentinelEvent) string {
+	fields := strings.Fields(event.Payload)
+	if event.Channel == switchMasterChannel {
+		if len(fields) > 0 {
+			return fields[0]
+		}
+		return ""
+	}
+	for i, field := range fields {
+		if field == "@" && i+1 < len(fields) {
+			return fields[i+1]
+		}
+	}
+	if len(fields) > 1 && fields[0] == "master" {
+		return fields[1]
+	}
+	return ""
+}
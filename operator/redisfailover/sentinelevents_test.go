@@ -0,0 +1,196 @@
+package redisfailover
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+func TestSentinelEventMasterName(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    redis.SentinelEvent
+		expected string
+	}{
+		{
+			name:     "switch master",
+			event:    redis.SentinelEvent{Channel: "+switch-master", Payload: "cache 10.0.0.1 6379 10.0.0.2 6379"},
+			expected: "cache",
+		},
+		{
+			name:     "master down",
+			event:    redis.SentinelEvent{Channel: "+odown", Payload: "master cache 10.0.0.1 6379 #quorum 2/2"},
+			expected: "cache",
+		},
+		{
+			name:     "slave down",
+			event:    redis.SentinelEvent{Channel: "+sdown", Payload: "slave 10.0.0.2:6379 10.0.0.2 6379 @ cache 10.0.0.1 6379"},
+			expected: "cache",
+		},
+		{
+			name:     "failover aborted",
+			event:    redis.SentinelEvent{Channel: "-failover-abort-no-good-slave", Payload: "master cache 10.0.0.1 6379"},
+			expected: "cache",
+		},
+		{
+			name:  "empty payload",
+			event: redis.SentinelEvent{Channel: "+switch-master"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, sentinelEventMasterName(test.event))
+		})
+	}
+}
+
+func TestSentinelEventsTriggerTheirMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "cache"},
+		},
+	}
+
+	events := make(chan redis.SentinelEvent)
+	var subscription context.Context
+	mr := &mRedisService.Client{}
+	mr.On("WatchSentinelEvents", mock.Anything, "0.0.0.1", "+switch-master", "+sdown", "+odown", "-failover-abort-*").Once().Run(func(args mock.Arguments) {
+		subscription = args.Get(0).(context.Context)
+	}).Return((<-chan redis.SentinelEvent)(events), nil)
+
+	triggered := make(chan string, 2)
+	s := newSentinelEvents(mr, func(namespace, name string) {
+		triggered <- namespace + "/" + name
+	}, log.Dummy)
+
+	s.watch(rf, []string{"0.0.0.1"})
+	// Already subscribed
+	s.watch(rf, []string{"0.0.0.1"})
+	mr.AssertExpectations(t)
+
+	events <- redis.SentinelEvent{Channel: "+switch-master", Payload: "other 10.0.0.1 6379 10.0.0.2 6379"}
+	events <- redis.SentinelEvent{Channel: "+switch-master", Payload: "cache 10.0.0.1 6379 10.0.0.2 6379"}
+	select {
+	case key := <-triggered:
+		assert.Equal("testns/test", key)
+	case <-time.After(time.Second):
+		assert.Fail("RedisFailover not triggered")
+	}
+	assert.Empty(triggered)
+
+	// The sentinel is gone
+	s.watch(rf, []string{})
+	assert.Error(subscription.Err())
+}
+
+func TestSentinelEventsResubscribeWhenClosed(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "cache"},
+		},
+	}
+
+	first := make(chan redis.SentinelEvent)
+	second := make(chan redis.SentinelEvent)
+	mr := &mRedisService.Client{}
+	mr.On("WatchSentinelEvents", mock.Anything, "0.0.0.1", "+switch-master", "+sdown", "+odown", "-failover-abort-*").Once().Return((<-chan redis.SentinelEvent)(first), nil)
+	mr.On("WatchSentinelEvents", mock.Anything, "0.0.0.1", "+switch-master", "+sdown", "+odown", "-failover-abort-*").Once().Return((<-chan redis.SentinelEvent)(second), nil)
+
+	s := newSentinelEvents(mr, func(namespace, name string) {}, log.Dummy)
+	s.watch(rf, []string{"0.0.0.1"})
+
+	// The sentinel restarts
+	close(first)
+	assert.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.subscriptions["testns/test"]) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	s.watch(rf, []string{"0.0.0.1"})
+	mr.AssertExpectations(t)
+	s.forget(rf)
+}
+
+// fakeRetriever lists and watches the given RedisFailovers
+type fakeRetriever struct {
+	list  *redisfailoverv1.RedisFailoverList
+	watch watch.Interface
+}
+
+func (f fakeRetriever) List(_ context.Context, _ metav1.ListOptions) (runtime.Object, error) {
+	return f.list, nil
+}
+
+func (f fakeRetriever) Watch(_ context.Context, _ metav1.ListOptions) (watch.Interface, error) {
+	return f.watch, nil
+}
+
+func TestReconcileTrigger(t *testing.T) {
+	assert := assert.New(t)
+
+	listed := redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns", ResourceVersion: "1"},
+	}
+	upstream := watch.NewFake()
+	trigger := newReconcileTrigger(log.Dummy)
+	retriever := trigger.retriever(fakeRetriever{
+		list:  &redisfailoverv1.RedisFailoverList{Items: []redisfailoverv1.RedisFailover{listed}},
+		watch: upstream,
+	})
+	_, err := retriever.List(context.Background(), metav1.ListOptions{})
+	assert.NoError(err)
+	w, err := retriever.Watch(context.Background(), metav1.ListOptions{})
+	assert.NoError(err)
+
+	// The listed state is sent again, so the resource version seen by the informer doesn't move
+	go trigger.trigger("testns", "test")
+	event := <-w.ResultChan()
+	assert.Equal(watch.Modified, event.Type)
+	assert.Equal("1", event.Object.(*redisfailoverv1.RedisFailover).ResourceVersion)
+
+	// Then the last state seen on the watch
+	modified := listed.DeepCopy()
+	modified.ResourceVersion = "2"
+	go upstream.Modify(modified)
+	event = <-w.ResultChan()
+	assert.Equal(modified, event.Object)
+	go trigger.send("testns/test")
+	event = <-w.ResultChan()
+	assert.Equal(watch.Modified, event.Type)
+	assert.Equal(modified, event.Object)
+
+	// Nothing is sent for a deleted RedisFailover
+	go upstream.Delete(modified)
+	event = <-w.ResultChan()
+	assert.Equal(watch.Deleted, event.Type)
+	trigger.send("testns/test")
+	select {
+	case event := <-w.ResultChan():
+		assert.Failf("unexpected event", "%v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	w.Stop()
+	_, ok := <-w.ResultChan()
+	assert.False(ok)
+	assert.True(upstream.IsStopped())
+}
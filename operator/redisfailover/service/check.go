@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -21,25 +22,29 @@ import (
 type RedisFailoverCheck interface {
 	CheckRedisNumber(rFailover *redisfailoverv1.RedisFailover) error
 	CheckSentinelNumber(rFailover *redisfailoverv1.RedisFailover) error
-	CheckAllSlavesFromMaster(master string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error)
-	CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error)
-	CheckSentinelMonitor(sentinel string, monitor ...string) error
-	GetMasterIP(rFailover *redisfailoverv1.RedisFailover) (string, error)
-	GetNumberMasters(rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover, monitor ...string) error
+	GetMasterIP(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetNumberMasters(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
 	GetRedisesIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	GetSentinelsIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	GetMaxRedisPodTime(rFailover *redisfailoverv1.RedisFailover) (time.Duration, error)
-	GetRedisesSlavesPods(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
-	GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetRedisesSlavesPods(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
 	GetStatefulSetUpdateRevision(rFailover *redisfailoverv1.RedisFailover) (string, error)
 	GetRedisRevisionHash(podName string, rFailover *redisfailoverv1.RedisFailover) (string, error)
-	CheckRedisSlavesReady(slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	GetRedisConfigDrift(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetSentinelConfigDrift(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	IsRedisRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	IsClusterRunning(rFailover *redisfailoverv1.RedisFailover) bool
+	GetSnapshot(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (*RedisFailoverSnapshot, error)
+	UpdateRoleLabels(rFailover *redisfailoverv1.RedisFailover, snapshot *RedisFailoverSnapshot, master string) error
 }
 
 // RedisFailoverChecker is our implementation of RedisFailoverCheck interface
@@ -103,7 +108,7 @@ func (r *RedisFailoverChecker) setSlaveLabelIfNecessary(namespace string, pod co
 }
 
 // CheckAllSlavesFromMaster controlls that all slaves have the same master (the real one)
-func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(ctx context.Context, master string, rf *redisfailoverv1.RedisFailover) error {
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
@@ -114,9 +119,10 @@ func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redis
 		return err
 	}
 
-	rport := getRedisPort(rf.Spec.Redis.Port)
+	rport := getRedisPort(rf.RedisAnnouncePort())
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == master {
+		raddr := getRedisAddress(rf, rp)
+		if raddr == master {
 			err = r.setMasterLabelIfNecessary(rf.Namespace, rp)
 			if err != nil {
 				return err
@@ -128,24 +134,28 @@ func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redis
 			}
 		}
 
-		slave, err := r.redisClient.GetSlaveOf(rp.Status.PodIP, rport, password)
+		slave, err := r.redisClient.GetSlaveOf(failoverContext(ctx, rf), raddr, rport, password)
 		if err != nil {
-			r.logger.Errorf("Get slave of master failed, maybe this node is not ready, pod ip: %s", rp.Status.PodIP)
+			r.logger.Errorf("Get slave of master failed, maybe this node is not ready, pod address: %s", raddr)
 			return err
 		}
 		if slave != "" && slave != master {
-			return fmt.Errorf("slave %s don't have the master %s, has %s", rp.Status.PodIP, master, slave)
+			return fmt.Errorf("slave %s don't have the master %s, has %s", raddr, master, slave)
 		}
 	}
 	return nil
 }
 
 // CheckSentinelNumberInMemory controls that the provided sentinel has only the living sentinels on its memory.
-func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(failoverContext(ctx, rf), sentinel)
 	if err != nil {
 		return err
-	} else if nSentinels != rf.Spec.Sentinel.Replicas {
+	}
+	replicas, err := getSentinelReplicas(r.k8sService, rf)
+	if err != nil {
+		return err
+	} else if nSentinels != replicas {
 		return errors.New("sentinels in memory mismatch")
 	}
 	return nil
@@ -156,7 +166,7 @@ func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *
 // This function returns true if it all available pods have local host ip as master,
 // false if atleast one of the ip is not local hostip
 // false and error if any function fails
-func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+func (r *RedisFailoverChecker) CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
 
 	var lhmaster int = 0
 	redisIps, err := r.GetRedisesIPs(rFailover)
@@ -169,9 +179,9 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 		r.logger.Errorf("CheckIfMasterLocalhost -- GetRedisPassword Failed")
 		return false, err
 	}
-	rport := getRedisPort(rFailover.Spec.Redis.Port)
+	rport := getRedisPort(rFailover.RedisAnnouncePort())
 	for _, sip := range redisIps {
-		master, err := r.redisClient.GetSlaveOf(sip, rport, password)
+		master, err := r.redisClient.GetSlaveOf(failoverContext(ctx, rFailover), sip, rport, password)
 		if err != nil {
 			r.logger.Warningf("CheckIfMasterLocalhost -- GetSlaveOf Failed")
 			return false, err
@@ -194,7 +204,7 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 
 // This function will call the sentinel client apis to check with sentinel if the sentinel is in a state
 // to heal the redis system
-func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error) {
 
 	var unhealthyCnt int = -1
 
@@ -203,22 +213,26 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 		r.logger.Warningf("CheckSentinelQuorum Error in getting sentinel Ip's")
 		return unhealthyCnt, err
 	}
-	if len(sentinels) < int(getQuorum(rFailover)) {
-		unhealthyCnt = int(getQuorum(rFailover)) - len(sentinels)
+	quorum, err := getSentinelQuorum(r.k8sService, rFailover)
+	if err != nil {
+		return unhealthyCnt, err
+	}
+	if len(sentinels) < int(quorum) {
+		unhealthyCnt = int(quorum) - len(sentinels)
 		r.logger.Warningf("insufficnet sentinel to reach Quorum - Unhealthy count: %d", unhealthyCnt)
 		return unhealthyCnt, errors.New("insufficnet sentinel to reach Quorum")
 	}
 
 	unhealthyCnt = 0
 	for _, sip := range sentinels {
-		err = r.redisClient.SentinelCheckQuorum(sip)
+		err = r.redisClient.SentinelCheckQuorum(failoverContext(ctx, rFailover), sip)
 		if err != nil {
 			unhealthyCnt += 1
 		} else {
 			continue
 		}
 	}
-	if unhealthyCnt < int(getQuorum(rFailover)) {
+	if unhealthyCnt < int(quorum) {
 		return unhealthyCnt, nil
 	} else {
 		r.logger.Errorf("insufficnet sentinel to reach Quorum - Unhealthy count: %d", unhealthyCnt)
@@ -227,8 +241,8 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 }
 
 // CheckSentinelSlavesNumberInMemory controls that the provided sentinel has only the expected slaves number.
-func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(failoverContext(ctx, rf), sentinel)
 	if err != nil {
 		return err
 	} else {
@@ -247,13 +261,13 @@ func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string
 }
 
 // CheckSentinelMonitor controls if the sentinels are monitoring the expected master
-func (r *RedisFailoverChecker) CheckSentinelMonitor(sentinel string, monitor ...string) error {
+func (r *RedisFailoverChecker) CheckSentinelMonitor(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover, monitor ...string) error {
 	monitorIP := monitor[0]
 	monitorPort := ""
 	if len(monitor) > 1 {
 		monitorPort = monitor[1]
 	}
-	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(sentinel)
+	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(failoverContext(ctx, rf), sentinel)
 	if err != nil {
 		return err
 	}
@@ -264,7 +278,7 @@ func (r *RedisFailoverChecker) CheckSentinelMonitor(sentinel string, monitor ...
 }
 
 // GetMasterIP connects to all redis and returns the master of the redis failover
-func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (string, error) {
+func (r *RedisFailoverChecker) GetMasterIP(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
 	rips, err := r.GetRedisesIPs(rf)
 	if err != nil {
 		return "", err
@@ -276,9 +290,9 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 	}
 
 	masters := []string{}
-	rport := getRedisPort(rf.Spec.Redis.Port)
+	rport := getRedisPort(rf.RedisAnnouncePort())
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := r.redisClient.IsMaster(failoverContext(ctx, rf), rip, rport, password)
 		if err != nil {
 			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
@@ -295,7 +309,7 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 }
 
 // GetNumberMasters returns the number of redis nodes that are working as a master
-func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) GetNumberMasters(ctx context.Context, rf *redisfailoverv1.RedisFailover) (int, error) {
 	nMasters := 0
 	rips, err := r.GetRedisesIPs(rf)
 	if err != nil {
@@ -309,9 +323,9 @@ func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailove
 		return nMasters, err
 	}
 
-	rport := getRedisPort(rf.Spec.Redis.Port)
+	rport := getRedisPort(rf.RedisAnnouncePort())
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := r.redisClient.IsMaster(failoverContext(ctx, rf), rip, rport, password)
 		if err != nil {
 			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
@@ -323,7 +337,8 @@ func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailove
 	return nMasters, nil
 }
 
-// GetRedisesIPs returns the IPs of the Redis nodes
+// GetRedisesIPs returns the addresses of the Redis nodes. Those are the pod IPs, or the pod
+// hostnames when the RedisFailover announces hostnames.
 func (r *RedisFailoverChecker) GetRedisesIPs(rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	redises := []string{}
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
@@ -332,7 +347,7 @@ func (r *RedisFailoverChecker) GetRedisesIPs(rf *redisfailoverv1.RedisFailover)
 	}
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running pods
-			redises = append(redises, rp.Status.PodIP)
+			redises = append(redises, getRedisAddress(rf, rp))
 		}
 	}
 	return redises, nil
@@ -340,8 +355,11 @@ func (r *RedisFailoverChecker) GetRedisesIPs(rf *redisfailoverv1.RedisFailover)
 
 // GetSentinelsIPs returns the IPs of the Sentinel nodes
 func (r *RedisFailoverChecker) GetSentinelsIPs(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	if rf.SharedSentinels() && !rf.PooledSentinels() {
+		return rf.Spec.Sentinel.SharedGroup.Addresses, nil
+	}
 	sentinels := []string{}
-	rps, err := r.k8sService.GetDeploymentPods(rf.Namespace, GetSentinelName(rf))
+	rps, err := r.k8sService.GetDeploymentPods(rf.Namespace, getSentinelDeploymentName(rf))
 	if err != nil {
 		return nil, err
 	}
@@ -375,7 +393,7 @@ func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailo
 }
 
 // GetRedisesSlavesPods returns pods names of the Redis slave nodes
-func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+func (r *RedisFailoverChecker) GetRedisesSlavesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	redises := []string{}
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
@@ -387,10 +405,10 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 		return redises, err
 	}
 
-	rport := getRedisPort(rf.Spec.Redis.Port)
+	rport := getRedisPort(rf.RedisAnnouncePort())
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := r.redisClient.IsMaster(failoverContext(ctx, rf), getRedisAddress(rf, rp), rport, password)
 			if err != nil {
 				return []string{}, err
 			}
@@ -403,7 +421,7 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 }
 
 // GetRedisesMasterPod returns pods names of the Redis slave nodes
-func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error) {
+func (r *RedisFailoverChecker) GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error) {
 	rps, err := r.k8sService.GetStatefulSetPods(rFailover.Namespace, GetRedisName(rFailover))
 	if err != nil {
 		return "", err
@@ -414,10 +432,10 @@ func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.Re
 		return "", err
 	}
 
-	rport := getRedisPort(rFailover.Spec.Redis.Port)
+	rport := getRedisPort(rFailover.RedisAnnouncePort())
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := r.redisClient.IsMaster(failoverContext(ctx, rFailover), getRedisAddress(rFailover, rp), rport, password)
 			if err != nil {
 				return "", err
 			}
@@ -465,14 +483,31 @@ func (r *RedisFailoverChecker) GetRedisRevisionHash(podName string, rFailover *r
 }
 
 // CheckRedisSlavesReady returns true if the slave is ready (sync, connected, etc)
-func (r *RedisFailoverChecker) CheckRedisSlavesReady(ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+func (r *RedisFailoverChecker) CheckRedisSlavesReady(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
 	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
 	if err != nil {
 		return false, err
 	}
 
-	port := getRedisPort(rFailover.Spec.Redis.Port)
-	return r.redisClient.SlaveIsReady(ip, port, password)
+	port := getRedisPort(rFailover.RedisAnnouncePort())
+	return r.redisClient.SlaveIsReady(failoverContext(ctx, rFailover), ip, port, password)
+}
+
+// GetRedisConfigDrift returns the parameters of the custom configuration that have a different value on the redis
+func (r *RedisFailoverChecker) GetRedisConfigDrift(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	port := getRedisPort(rf.RedisAnnouncePort())
+	runtimeConfigs, _ := splitRedisCustomConfig(rf.Spec.Redis.CustomConfig)
+	return r.redisClient.GetRedisConfigDrift(failoverContext(ctx, rf), ip, port, runtimeConfigs, password)
+}
+
+// GetSentinelConfigDrift returns the parameters of the custom configuration that have a different value on the sentinel
+func (r *RedisFailoverChecker) GetSentinelConfigDrift(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	return r.redisClient.GetSentinelConfigDrift(failoverContext(ctx, rf), ip, rf.Spec.Sentinel.CustomConfig)
 }
 
 // IsRedisRunning returns true if all the pods are Running
@@ -483,8 +518,17 @@ func (r *RedisFailoverChecker) IsRedisRunning(rFailover *redisfailoverv1.RedisFa
 
 // IsSentinelRunning returns true if all the pods are Running
 func (r *RedisFailoverChecker) IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool {
-	dp, err := r.k8sService.GetDeploymentPods(rFailover.Namespace, GetSentinelName(rFailover))
-	return err == nil && len(dp.Items) > int(rFailover.Spec.Sentinel.Replicas-1) && AreAllRunning(dp, int(rFailover.Spec.Sentinel.Replicas))
+	// The shared sentinels are not deployed by the operator, they are checked when queried
+	if rFailover.SharedSentinels() && !rFailover.PooledSentinels() {
+		return true
+	}
+	replicas, err := getSentinelReplicas(r.k8sService, rFailover)
+	if err != nil {
+		r.logger.WithField("redisfailover", rFailover.ObjectMeta.Name).WithField("namespace", rFailover.ObjectMeta.Namespace).Warningf("Unable to get the sentinels: %s", err)
+		return false
+	}
+	dp, err := r.k8sService.GetDeploymentPods(rFailover.Namespace, getSentinelDeploymentName(rFailover))
+	return err == nil && len(dp.Items) > int(replicas-1) && AreAllRunning(dp, int(replicas))
 }
 
 // IsClusterRunning returns true if all the pods in the given redisfailover are Running
@@ -492,6 +536,37 @@ func (r *RedisFailoverChecker) IsClusterRunning(rFailover *redisfailoverv1.Redis
 	return r.IsSentinelRunning(rFailover) && r.IsRedisRunning(rFailover)
 }
 
+// getSentinelDeploymentName returns the name of the deployment of the sentinels monitoring the RedisFailover,
+// that is the one of the pool when its sentinels are pooled
+func getSentinelDeploymentName(rf *redisfailoverv1.RedisFailover) string {
+	if rf.PooledSentinels() {
+		return GetSentinelPoolName(rf)
+	}
+	return GetSentinelName(rf)
+}
+
+// failoverContext returns the context of the redis operations done on behalf of the RedisFailover, derived
+// from the given one so they are cancelled along with the handling of the RedisFailover
+func failoverContext(ctx context.Context, rf *redisfailoverv1.RedisFailover) context.Context {
+	ctx = redis.WithFailover(ctx, rf.Namespace, rf.Name)
+	return redis.WithMasterName(ctx, rf.Spec.Sentinel.MasterName)
+}
+
+// getRedisAddress returns the address used to reach a redis pod and announced to the rest of the failover
+func getRedisAddress(rf *redisfailoverv1.RedisFailover, pod corev1.Pod) string {
+	if rf.Spec.AnnounceHostnames {
+		return GetRedisPodHostname(rf, pod.Name)
+	}
+	switch rf.Spec.Redis.AnnounceIP {
+	case "", redisfailoverv1.AnnouncePodIP:
+		return pod.Status.PodIP
+	case redisfailoverv1.AnnounceHostIP:
+		return pod.Status.HostIP
+	default:
+		return rf.Spec.Redis.AnnounceIP
+	}
+}
+
 func getRedisPort(p int32) string {
 	return strconv.Itoa(int(p))
 }
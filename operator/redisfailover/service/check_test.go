@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -163,7 +164,7 @@ func TestCheckAllSlavesFromMasterGetStatefulSetError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "", rf)
 	assert.Error(err)
 }
 
@@ -187,11 +188,11 @@ func TestCheckAllSlavesFromMasterGetSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "", "0", "").Once().Return("", errors.New(""))
+	mr.On("GetSlaveOf", mock.Anything, "", "0", "").Once().Return("", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "", rf)
 	assert.Error(err)
 }
 
@@ -215,11 +216,11 @@ func TestCheckAllSlavesFromMasterDifferentMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("0.0.0.0", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -243,14 +244,121 @@ func TestCheckAllSlavesFromMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("1.1.1.1", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
+func TestCheckAllSlavesFromMasterIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::a",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::b",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "fd00:10:244::a", "0", "").Once().Return("", nil)
+	mr.On("GetSlaveOf", mock.Anything, "fd00:10:244::b", "0", "").Once().Return("fd00:10:244::c", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "fd00:10:244::a", rf)
+	assert.EqualError(err, "slave fd00:10:244::b don't have the master fd00:10:244::a, has fd00:10:244::c")
+}
+
+func TestCheckAllSlavesFromMasterWithHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	master := "rfr-test-1.rfr-test.testns.svc"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "rfr-test-0.rfr-test.testns.svc", "0", "").Once().Return(master, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), master, rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
+func TestCheckAllSlavesFromMasterWithAnnounce(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.AnnounceIP = redisfailoverv1.AnnounceHostIP
+	rf.Spec.Redis.AnnouncePort = 30379
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					HostIP: "192.0.2.10",
+					PodIP:  "0.0.0.0",
+					Phase:  corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	master := "192.0.2.11"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "192.0.2.10", "30379", "").Once().Return(master, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), master, rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
 func TestCheckSentinelNumberInMemoryGetDeploymentPodsError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -258,11 +366,11 @@ func TestCheckSentinelNumberInMemoryGetDeploymentPodsError(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New("expected error"))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(0), errors.New("expected error"))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -273,11 +381,11 @@ func TestCheckSentinelNumberInMemoryGetNumberSentinelInMemoryError(t *testing.T)
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -288,11 +396,11 @@ func TestCheckSentinelNumberInMemoryNumberMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -303,11 +411,11 @@ func TestCheckSentinelNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
@@ -318,11 +426,11 @@ func TestCheckSentinelSlavesNumberInMemoryGetNumberSentinelSlavesInMemoryError(t
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -333,11 +441,11 @@ func TestCheckSentinelSlavesNumberInMemoryReplicasMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -349,11 +457,11 @@ func TestCheckSentinelSlavesNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
@@ -362,11 +470,13 @@ func TestCheckSentinelMonitorGetSentinelMonitorError(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("", "", errors.New(""))
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("", "", errors.New(""))
+
+	rf := generateRF()
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
@@ -375,11 +485,13 @@ func TestCheckSentinelMonitorMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("2.2.2.2", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("2.2.2.2", "6379", nil)
+
+	rf := generateRF()
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
@@ -388,11 +500,13 @@ func TestCheckSentinelMonitor(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+
+	rf := generateRF()
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.NoError(err)
 }
 
@@ -401,11 +515,13 @@ func TestCheckSentinelMonitorWithPort(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+
+	rf := generateRF()
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1", "6379")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1", "6379")
 	assert.NoError(err)
 }
 
@@ -414,11 +530,13 @@ func TestCheckSentinelMonitorWithPortMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+
+	rf := generateRF()
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "0.0.0.0", "6379")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "0.0.0.0", "6379")
 	assert.Error(err)
 }
 
@@ -427,11 +545,13 @@ func TestCheckSentinelMonitorWithPortIPMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+
+	rf := generateRF()
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1", "6380")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1", "6380")
 	assert.Error(err)
 }
 
@@ -446,7 +566,7 @@ func TestGetMasterIPGetStatefulSetPodsError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -469,11 +589,11 @@ func TestGetMasterIPIsMasterError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -502,12 +622,12 @@ func TestGetMasterIPMultipleMastersError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -536,16 +656,51 @@ func TestGetMasterIP(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	master, err := checker.GetMasterIP(rf)
+	master, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal("0.0.0.0", master, "the master should be the expected")
 }
 
+func TestGetMasterIPIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::a",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::b",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, "fd00:10:244::a", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "fd00:10:244::b", "0", "").Once().Return(true, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	master, err := checker.GetMasterIP(context.TODO(), rf)
+	assert.NoError(err)
+	assert.Equal("fd00:10:244::b", master)
+}
+
 func TestGetNumberMastersGetStatefulSetPodsError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -557,7 +712,7 @@ func TestGetNumberMastersGetStatefulSetPodsError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -580,11 +735,11 @@ func TestGetNumberMastersIsMasterError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -613,12 +768,12 @@ func TestGetNumberMasters(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal(1, masterNumber, "the master number should be ok")
 }
@@ -648,16 +803,56 @@ func TestGetNumberMastersTwo(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal(2, masterNumber, "the master number should be ok")
 }
 
+func TestGetRedisesIPsWithHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-1",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodPending,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	redises, err := checker.GetRedisesIPs(rf)
+	assert.NoError(err)
+	assert.Equal([]string{"rfr-test-0.rfr-test.testns.svc"}, redises)
+}
+
 func TestGetMaxRedisPodTimeGetStatefulSetPodsError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -753,21 +948,21 @@ func TestGetRedisPodsNames(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
-	master, err := checker.GetRedisesMasterPod(rf)
+	master, err := checker.GetRedisesMasterPod(context.TODO(), rf)
 
 	assert.NoError(err)
 
 	assert.Equal(master, "master")
 
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
-	namePods, err := checker.GetRedisesSlavesPods(rf)
+	namePods, err := checker.GetRedisesSlavesPods(context.TODO(), rf)
 
 	assert.NoError(err)
 
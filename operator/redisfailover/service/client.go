@@ -1,8 +1,15 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/log"
@@ -25,6 +32,15 @@ type RedisFailoverClient interface {
 	EnsureRedisReadinessConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureRedisConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureNotPresentRedisService(rFailover *redisfailoverv1.RedisFailover) error
+	EnsureNotPresentSentinel(rFailover *redisfailoverv1.RedisFailover) error
+	EnsureFinalizer(rFailover *redisfailoverv1.RedisFailover) error
+	RemoveFinalizer(rFailover *redisfailoverv1.RedisFailover) error
+	RetainRedisPersistentVolumeClaims(rFailover *redisfailoverv1.RedisFailover) error
+	DeleteRedisPersistentVolumeClaims(rFailover *redisfailoverv1.RedisFailover) error
+	SnapshotRedisPersistentVolumeClaims(rFailover *redisfailoverv1.RedisFailover) error
+	EnsureRedisStorageExpansion(rFailover *redisfailoverv1.RedisFailover) error
+	EnsureMonitoring(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureNetworkPolicies(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) error
 }
 
 // RedisFailoverKubeClient implements the required methods to talk with kubernetes
@@ -105,12 +121,66 @@ func (r *RedisFailoverKubeClient) EnsureRedisStatefulset(rf *redisfailoverv1.Red
 		}
 	}
 	ss := generateRedisStatefulSet(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateStatefulSet(rf.Namespace, ss)
+	stored, err := r.K8SService.GetStatefulSet(rf.Namespace, ss.Name)
+	switch {
+	case errors.IsNotFound(err):
+		if err := r.resolveRedisFailoverSnapshotDataSource(ss); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		keepRedisPodTemplate(rf, stored, ss)
+	}
+	ss.Annotations = util.MergeAnnotations(ss.Annotations, map[string]string{
+		redisTemplateGenerationAnnotation: strconv.FormatInt(rf.Generation, 10),
+	})
+	err = r.K8SService.CreateOrUpdateStatefulSet(rf.Namespace, ss)
 
 	r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
 	return err
 }
 
+// keepRedisPodTemplate keeps the pod template of the stored redis statefulset while the spec of the
+// RedisFailover doesn't change, so a new version of the operator generating a different template doesn't
+// restart every redis pod. The statefulsets of previous versions don't know their generation, they take
+// the current one.
+func keepRedisPodTemplate(rf *redisfailoverv1.RedisFailover, stored *appsv1.StatefulSet, ss *appsv1.StatefulSet) {
+	generation, known := stored.Annotations[redisTemplateGenerationAnnotation]
+	if !known || generation == strconv.FormatInt(rf.Generation, 10) {
+		ss.Spec.Template = stored.Spec.Template
+	}
+}
+
+// resolveRedisFailoverSnapshotDataSource replaces a RedisFailoverSnapshot data source of the volume claim
+// template with the VolumeSnapshot it took, so new RedisFailovers can be restored from it. The volume
+// claim templates of an existing statefulset are not updated, so only its creation needs it.
+func (r *RedisFailoverKubeClient) resolveRedisFailoverSnapshotDataSource(ss *appsv1.StatefulSet) error {
+	if len(ss.Spec.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	dataSource := ss.Spec.VolumeClaimTemplates[0].Spec.DataSource
+	if dataSource == nil || dataSource.APIGroup == nil || *dataSource.APIGroup != redisfailoverv1.SchemeGroupVersion.Group || dataSource.Kind != redisfailoverv1.RFSnapshotKind {
+		return nil
+	}
+
+	rfs, err := r.K8SService.GetRedisFailoverSnapshot(context.TODO(), ss.Namespace, dataSource.Name)
+	if err != nil {
+		return err
+	}
+	if rfs.Status.Phase != redisfailoverv1.RedisFailoverSnapshotPhaseReady {
+		return fmt.Errorf("redisfailoversnapshot %s is not ready to be restored", rfs.Name)
+	}
+	snapshotGroup := k8s.VolumeSnapshotGroupVersionResource.Group
+	ss.Spec.VolumeClaimTemplates[0].Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &snapshotGroup,
+		Kind:     "VolumeSnapshot",
+		Name:     rfs.Status.VolumeSnapshotName,
+	}
+	ss.Spec.VolumeClaimTemplates[0].Spec.DataSourceRef = nil
+	return nil
+}
+
 // EnsureRedisConfigMap makes sure the Redis ConfigMap exists
 func (r *RedisFailoverKubeClient) EnsureRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 
@@ -169,6 +239,33 @@ func (r *RedisFailoverKubeClient) EnsureNotPresentRedisService(rf *redisfailover
 	return nil
 }
 
+// EnsureNotPresentSentinel makes sure the sentinel deployment and the resources around it are not present,
+// as the redis are monitored by a shared sentinel group
+func (r *RedisFailoverKubeClient) EnsureNotPresentSentinel(rf *redisfailoverv1.RedisFailover) error {
+	name := GetSentinelName(rf)
+	namespace := rf.Namespace
+	// If the resources exist (no get error), delete them
+	if _, err := r.K8SService.GetDeployment(namespace, name); err == nil {
+		if err := r.K8SService.DeleteDeployment(namespace, name); err != nil {
+			return err
+		}
+	}
+	if _, err := r.K8SService.GetPodDisruptionBudget(namespace, name); err == nil {
+		if err := r.K8SService.DeletePodDisruptionBudget(namespace, name); err != nil {
+			return err
+		}
+	}
+	if _, err := r.K8SService.GetService(namespace, name); err == nil {
+		if err := r.K8SService.DeleteService(namespace, name); err != nil {
+			return err
+		}
+	}
+	if _, err := r.K8SService.GetConfigMap(namespace, name); err == nil {
+		return r.K8SService.DeleteConfigMap(namespace, name)
+	}
+	return nil
+}
+
 // EnsureRedisMasterService makes sure the redis master service exists
 func (r *RedisFailoverKubeClient) EnsureRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	svc := generateRedisMasterService(rf, labels, ownerRefs)
@@ -189,18 +286,8 @@ func (r *RedisFailoverKubeClient) EnsureRedisSlaveService(rf *redisfailoverv1.Re
 
 // EnsureRedisStatefulset makes sure the pdb exists in the desired state
 func (r *RedisFailoverKubeClient) ensurePodDisruptionBudget(rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	name = generateName(name, rf.Name)
-	namespace := rf.Namespace
-
-	minAvailable := intstr.FromInt(2)
-	if rf.Spec.Redis.Replicas <= 2 {
-		minAvailable = intstr.FromInt(1)
-	}
-
-	labels = util.MergeLabels(labels, generateSelectorLabels(component, rf.Name))
-
-	pdb := generatePodDisruptionBudget(name, namespace, labels, ownerRefs, minAvailable)
-	err := r.K8SService.CreateOrUpdatePodDisruptionBudget(namespace, pdb)
+	pdb := generateComponentPodDisruptionBudget(rf, name, component, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdatePodDisruptionBudget(rf.Namespace, pdb)
 	r.setEnsureOperationMetrics(pdb.Namespace, pdb.Name, "PodDisruptionBudget" /* pdb.TypeMeta.Kind isnt working;  pdb.Kind isnt working either */, rf.Name, err)
 	return err
 }
@@ -1,5 +1,7 @@
 package service
 
+import "time"
+
 // variables refering to the redis exporter port
 const (
 	exporterPort                  = 9121
@@ -29,8 +31,63 @@ const (
 	hostnameTopologyKey    = "kubernetes.io/hostname"
 )
 
+// defaultSentinelMasterName is the name the sentinels know the master by when the RedisFailover doesn't set it
+const defaultSentinelMasterName = "mymaster"
+
 const (
 	redisRoleLabelKey    = "redisfailovers-role"
 	redisRoleLabelMaster = "master"
 	redisRoleLabelSlave  = "slave"
 )
+
+// fileSystemResizePendingTimeout is the time a persistent volume claim waits for its file system to be
+// resized online before its pod is restarted so it is resized offline
+const fileSystemResizePendingTimeout = 5 * time.Minute
+
+// configChecksumAnnotationPrefix prefixes the annotation set on the redis and sentinel pods with the checksum of
+// the custom configuration applied to them, named after the RedisFailover, as the pods of a sentinel pool are
+// configured by every RedisFailover they monitor
+const configChecksumAnnotationPrefix = "config-checksum.redisfailovers.databases.spotahome.com/"
+
+// redisRestartConfigChecksumAnnotation is set on the redis pods with the checksum of the custom configuration
+// that needs a restart to be applied, so the pods are restarted when it changes
+const redisRestartConfigChecksumAnnotation = "redisfailovers.databases.spotahome.com/restart-config-checksum"
+
+// redisTemplateGenerationAnnotation is set on the redis statefulset with the generation of the RedisFailover
+// its pod template was generated from
+const redisTemplateGenerationAnnotation = "redisfailovers.databases.spotahome.com/template-generation"
+
+// redisStartScript starts redis with the given port from its writable configuration file. CONFIG REWRITE stores
+// the replication state on it, so the one of the previous run is dropped on every start: a restarted master
+// must start as a slave, as the initial configuration does, until the sentinels or the operator choose it.
+const redisStartScript = `config=/redis/%[1]s
+{ echo "slaveof 127.0.0.1 %[2]d"; grep -v -e "^slaveof " -e "^replicaof " "$config"; } > "$config.tmp"
+mv "$config.tmp" "$config"
+exec redis-server "$config" "$@"`
+
+// redisRestartRequiredConfigs are the redis parameters that can't be changed with CONFIG SET, redis only
+// reads them when it starts
+var redisRestartRequiredConfigs = map[string]bool{
+	"aclfile":                  true,
+	"always-show-logo":         true,
+	"cluster-config-file":      true,
+	"cluster-enabled":          true,
+	"daemonize":                true,
+	"databases":                true,
+	"disable-thp":              true,
+	"enable-debug-command":     true,
+	"enable-module-command":    true,
+	"enable-protected-configs": true,
+	"io-threads":               true,
+	"io-threads-do-reads":      true,
+	"loadmodule":               true,
+	"logfile":                  true,
+	"pidfile":                  true,
+	"supervised":               true,
+	"syslog-enabled":           true,
+	"syslog-facility":          true,
+	"syslog-ident":             true,
+	"tcp-backlog":              true,
+	"unixsocket":               true,
+	"unixsocketperm":           true,
+}
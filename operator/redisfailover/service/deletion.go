@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// RedisFailoverFinalizer is the finalizer that lets the operator clean up the redis data of a
+// RedisFailover, following its deletion policy, before the RedisFailover is removed
+const RedisFailoverFinalizer = "redisfailovers.databases.spotahome.com/finalizer"
+
+// HasFinalizer returns true if the RedisFailover has the operator finalizer
+func HasFinalizer(rf *redisfailoverv1.RedisFailover) bool {
+	for _, f := range rf.Finalizers {
+		if f == RedisFailoverFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureFinalizer makes sure the RedisFailover has the operator finalizer. The stored object is
+// updated from a copy, so the defaults set on the given one are not persisted.
+func (r *RedisFailoverKubeClient) EnsureFinalizer(rf *redisfailoverv1.RedisFailover) error {
+	if HasFinalizer(rf) {
+		return nil
+	}
+	rfCopy := rf.DeepCopy()
+	rfCopy.Finalizers = append(rfCopy.Finalizers, RedisFailoverFinalizer)
+	updated, err := r.K8SService.UpdateRedisFailover(context.TODO(), rfCopy)
+	if err != nil {
+		return err
+	}
+	// Keep the resource version, so the later updates of this reconciliation don't conflict.
+	if updated != nil {
+		rf.ResourceVersion = updated.ResourceVersion
+	}
+	return nil
+}
+
+// RemoveFinalizer removes the operator finalizer from the RedisFailover, letting it be deleted
+func (r *RedisFailoverKubeClient) RemoveFinalizer(rf *redisfailoverv1.RedisFailover) error {
+	if !HasFinalizer(rf) {
+		return nil
+	}
+	rfCopy := rf.DeepCopy()
+	finalizers := []string{}
+	for _, f := range rfCopy.Finalizers {
+		if f != RedisFailoverFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	rfCopy.Finalizers = finalizers
+	_, err := r.K8SService.UpdateRedisFailover(context.TODO(), rfCopy)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// RetainRedisPersistentVolumeClaims removes the owner reference to the RedisFailover from the redis
+// persistent volume claims, so they are not garbage collected with it
+func (r *RedisFailoverKubeClient) RetainRedisPersistentVolumeClaims(rf *redisfailoverv1.RedisFailover) error {
+	pvcs, err := r.getRedisPersistentVolumeClaims(rf)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		ownerRefs := []metav1.OwnerReference{}
+		for _, ref := range pvc.OwnerReferences {
+			if ref.UID != rf.UID {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+		if len(ownerRefs) == len(pvc.OwnerReferences) {
+			continue
+		}
+		pvc.OwnerReferences = ownerRefs
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Retaining persistent volume claim %s", pvc.Name)
+		if err := r.K8SService.UpdatePersistentVolumeClaim(rf.Namespace, &pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRedisPersistentVolumeClaims deletes the redis persistent volume claims
+func (r *RedisFailoverKubeClient) DeleteRedisPersistentVolumeClaims(rf *redisfailoverv1.RedisFailover) error {
+	pvcs, err := r.getRedisPersistentVolumeClaims(rf)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Deleting persistent volume claim %s", pvc.Name)
+		if err := r.K8SService.DeletePersistentVolumeClaim(rf.Namespace, pvc.Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SnapshotRedisPersistentVolumeClaims takes a VolumeSnapshot of every redis persistent volume claim.
+// The snapshots are named after the claim and the deletion time of the RedisFailover, so retries
+// don't take them twice.
+func (r *RedisFailoverKubeClient) SnapshotRedisPersistentVolumeClaims(rf *redisfailoverv1.RedisFailover) error {
+	pvcs, err := r.getRedisPersistentVolumeClaims(rf)
+	if err != nil {
+		return err
+	}
+	suffix := metav1.Now().Unix()
+	if rf.DeletionTimestamp != nil {
+		suffix = rf.DeletionTimestamp.Unix()
+	}
+	for _, pvc := range pvcs {
+		name := fmt.Sprintf("%s-%d", pvc.Name, suffix)
+		volumeSnapshot := k8s.NewVolumeSnapshotFromPVC(rf.Namespace, name, pvc.Name, "", generateSelectorLabels(redisRoleName, rf.Name))
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Taking volume snapshot %s of persistent volume claim %s", name, pvc.Name)
+		if err := r.K8SService.CreateVolumeSnapshot(rf.Namespace, volumeSnapshot); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRedisPersistentVolumeClaims returns the persistent volume claims of the redis statefulset, which
+// get the statefulset selector labels
+func (r *RedisFailoverKubeClient) getRedisPersistentVolumeClaims(rf *redisfailoverv1.RedisFailover) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs, err := r.K8SService.ListPersistentVolumeClaims(rf.Namespace, generateSelectorLabels(redisRoleName, rf.Name))
+	if err != nil {
+		return nil, err
+	}
+	return pvcs.Items, nil
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestEnsureFinalizer(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("UpdateRedisFailover", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		return rfservice.HasFinalizer(updated)
+	})).Once().Return(&redisfailoverv1.RedisFailover{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "2"}}, nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureFinalizer(rf))
+	assert.False(rfservice.HasFinalizer(rf), "the given RedisFailover should not be modified")
+	assert.Equal("2", rf.ResourceVersion)
+
+	rf.Finalizers = []string{rfservice.RedisFailoverFinalizer}
+	assert.NoError(client.EnsureFinalizer(rf))
+	ms.AssertExpectations(t)
+}
+
+func TestRemoveFinalizer(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Finalizers = []string{"other", rfservice.RedisFailoverFinalizer}
+
+	ms := &mK8SService.Services{}
+	ms.On("UpdateRedisFailover", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		return len(updated.Finalizers) == 1 && updated.Finalizers[0] == "other"
+	})).Once().Return(nil, nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.RemoveFinalizer(rf))
+	ms.AssertExpectations(t)
+}
+
+func generateRedisPersistentVolumeClaims(rf *redisfailoverv1.RedisFailover) *corev1.PersistentVolumeClaimList {
+	ownerRefs := []metav1.OwnerReference{
+		{Name: rf.Name, UID: rf.UID},
+		{Name: "other", UID: "other-uid"},
+	}
+	return &corev1.PersistentVolumeClaimList{
+		Items: []corev1.PersistentVolumeClaim{
+			{ObjectMeta: metav1.ObjectMeta{Name: "redis-data-rfr-test-0", Namespace: namespace, OwnerReferences: ownerRefs}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "redis-data-rfr-test-1", Namespace: namespace, OwnerReferences: ownerRefs}},
+		},
+	}
+}
+
+func TestRetainRedisPersistentVolumeClaims(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.UID = "rf-uid"
+
+	ms := &mK8SService.Services{}
+	ms.On("ListPersistentVolumeClaims", namespace, mock.Anything).Once().Return(generateRedisPersistentVolumeClaims(rf), nil)
+	ms.On("UpdatePersistentVolumeClaim", namespace, mock.MatchedBy(func(pvc *corev1.PersistentVolumeClaim) bool {
+		return len(pvc.OwnerReferences) == 1 && pvc.OwnerReferences[0].UID == "other-uid"
+	})).Twice().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.RetainRedisPersistentVolumeClaims(rf))
+	ms.AssertExpectations(t)
+}
+
+func TestDeleteRedisPersistentVolumeClaims(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("ListPersistentVolumeClaims", namespace, mock.Anything).Once().Return(generateRedisPersistentVolumeClaims(rf), nil)
+	ms.On("DeletePersistentVolumeClaim", namespace, "redis-data-rfr-test-0").Once().Return(nil)
+	ms.On("DeletePersistentVolumeClaim", namespace, "redis-data-rfr-test-1").Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.DeleteRedisPersistentVolumeClaims(rf))
+	ms.AssertExpectations(t)
+}
+
+func TestSnapshotRedisPersistentVolumeClaims(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	deletion := metav1.Unix(1700000000, 0)
+	rf.DeletionTimestamp = &deletion
+
+	snapshots := map[string]string{}
+	ms := &mK8SService.Services{}
+	ms.On("ListPersistentVolumeClaims", namespace, mock.Anything).Once().Return(generateRedisPersistentVolumeClaims(rf), nil)
+	ms.On("CreateVolumeSnapshot", namespace, mock.Anything).Twice().Run(func(args mock.Arguments) {
+		volumeSnapshot := args.Get(1).(*unstructured.Unstructured)
+		source, _, _ := unstructured.NestedString(volumeSnapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		snapshots[volumeSnapshot.GetName()] = source
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.SnapshotRedisPersistentVolumeClaims(rf))
+	assert.Equal(map[string]string{
+		"redis-data-rfr-test-0-1700000000": "redis-data-rfr-test-0",
+		"redis-data-rfr-test-1-1700000000": "redis-data-rfr-test-1",
+	}, snapshots)
+	ms.AssertExpectations(t)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"net"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// Kinds of the objects of the redis and sentinel commands planned in dry run mode
+const (
+	dryRunRedisKind    = "Redis"
+	dryRunSentinelKind = "Sentinel"
+)
+
+// RedisFailoverDryRunHealer is the RedisFailoverHeal of the dry run mode, it logs and records the
+// redis and sentinel commands that would be run without running them
+type RedisFailoverDryRunHealer struct {
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+var _ RedisFailoverHeal = &RedisFailoverDryRunHealer{}
+
+// NewRedisFailoverDryRunHealer creates an object of the RedisFailoverDryRunHealer struct
+func NewRedisFailoverDryRunHealer(logger log.Logger, metricsRecorder metrics.Recorder) *RedisFailoverDryRunHealer {
+	return &RedisFailoverDryRunHealer{
+		logger:          logger.With("service", "redis.healer.dryrun"),
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+func (r *RedisFailoverDryRunHealer) plan(rf *redisfailoverv1.RedisFailover, kind, object, command string) error {
+	r.logger.WithFields(map[string]interface{}{
+		"namespace":     rf.Namespace,
+		"redisfailover": rf.Name,
+		"kind":          kind,
+		"name":          object,
+		"action":        command,
+	}).Info("Dry run, command not run")
+	r.metricsRecorder.RecordDryRunChange(rf.Namespace, rf.Name, kind, object, command)
+	return nil
+}
+
+// MakeMaster plans the promotion of the given redis to master
+func (r *RedisFailoverDryRunHealer) MakeMaster(_ context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunRedisKind, ip, "SLAVEOF NO ONE")
+}
+
+// SetOldestAsMaster plans the promotion of the oldest redis, it is only known once the command is run
+func (r *RedisFailoverDryRunHealer) SetOldestAsMaster(_ context.Context, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunRedisKind, GetRedisName(rf), "SLAVEOF NO ONE")
+}
+
+// SetMasterOnAll plans the replication of all the redis from the given master
+func (r *RedisFailoverDryRunHealer) SetMasterOnAll(_ context.Context, masterIP string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunRedisKind, GetRedisName(rf), "SLAVEOF "+net.JoinHostPort(masterIP, getRedisPort(rf.RedisAnnouncePort())))
+}
+
+// SetExternalMasterOnAll plans the replication of all the redis from the given external master
+func (r *RedisFailoverDryRunHealer) SetExternalMasterOnAll(_ context.Context, masterIP string, masterPort string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunRedisKind, GetRedisName(rf), "SLAVEOF "+net.JoinHostPort(masterIP, masterPort))
+}
+
+// NewSentinelMonitor plans the monitoring of the given master by the sentinel
+func (r *RedisFailoverDryRunHealer) NewSentinelMonitor(_ context.Context, ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunSentinelKind, ip, "SENTINEL MONITOR "+net.JoinHostPort(monitor, getRedisPort(rf.RedisAnnouncePort())))
+}
+
+// NewSentinelMonitorWithPort plans the monitoring of the given master by the sentinel
+func (r *RedisFailoverDryRunHealer) NewSentinelMonitorWithPort(_ context.Context, ip string, monitor string, port string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunSentinelKind, ip, "SENTINEL MONITOR "+net.JoinHostPort(monitor, port))
+}
+
+// RestoreSentinel plans the reset of the sentinel
+func (r *RedisFailoverDryRunHealer) RestoreSentinel(_ context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunSentinelKind, ip, "SENTINEL RESET")
+}
+
+// RemoveSentinelMonitor plans the removal of the monitor of the sentinel
+func (r *RedisFailoverDryRunHealer) RemoveSentinelMonitor(_ context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunSentinelKind, ip, "SENTINEL REMOVE")
+}
+
+// SetSentinelCustomConfig plans the configuration of the sentinel
+func (r *RedisFailoverDryRunHealer) SetSentinelCustomConfig(_ context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunSentinelKind, ip, "SENTINEL SET")
+}
+
+// SetRedisCustomConfig plans the configuration of the redis
+func (r *RedisFailoverDryRunHealer) SetRedisCustomConfig(_ context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunRedisKind, ip, "CONFIG SET")
+}
+
+// DeletePod plans the deletion of the pod
+func (r *RedisFailoverDryRunHealer) DeletePod(podName string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, "Pod", podName, k8s.DryRunDelete)
+}
+
+// BackgroundSave plans the save of the redis
+func (r *RedisFailoverDryRunHealer) BackgroundSave(_ context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	return r.plan(rf, dryRunRedisKind, ip, "BGSAVE")
+}
@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// dryRunRecorder keeps the dry run changes recorded on the metrics
+type dryRunRecorder struct {
+	metrics.Recorder
+	changes []string
+}
+
+func (d *dryRunRecorder) RecordDryRunChange(namespace, resource, kind, object, action string) {
+	d.changes = append(d.changes, namespace+"/"+resource+" "+kind+" "+object+": "+action)
+}
+
+func TestDryRunHealer(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Port = 6379
+	recorder := &dryRunRecorder{Recorder: metrics.Dummy}
+	healer := rfservice.NewRedisFailoverDryRunHealer(log.Dummy, recorder)
+
+	assert.NoError(healer.MakeMaster(context.TODO(), "0.0.0.0", rf))
+	assert.NoError(healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf))
+	assert.NoError(healer.NewSentinelMonitor(context.TODO(), "0.0.0.1", "0.0.0.0", rf))
+	assert.NoError(healer.RestoreSentinel(context.TODO(), "0.0.0.1", rf))
+	assert.NoError(healer.DeletePod("rfr-test-1", rf))
+
+	assert.Equal([]string{
+		namespace + "/" + name + " Redis 0.0.0.0: SLAVEOF NO ONE",
+		namespace + "/" + name + " Redis rfr-" + name + ": SLAVEOF 0.0.0.0:6379",
+		namespace + "/" + name + " Sentinel 0.0.0.1: SENTINEL MONITOR 0.0.0.0:6379",
+		namespace + "/" + name + " Sentinel 0.0.0.1: SENTINEL RESET",
+		namespace + "/" + name + " Pod rfr-test-1: delete",
+	}, recorder.changes)
+}
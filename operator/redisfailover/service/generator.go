@@ -2,6 +2,8 @@ package service
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"text/template"
@@ -18,23 +20,38 @@ import (
 )
 
 const (
-	redisConfigurationVolumeName = "redis-config"
+	redisConfigurationVolumeName         = "redis-config"
+	redisWritableConfigurationVolumeName = "redis-config-writable"
 	// Template used to build the Redis configuration
 	redisConfigTemplate = `slaveof 127.0.0.1 {{.Spec.Redis.Port}}
 port {{.Spec.Redis.Port}}
 tcp-keepalive 60
-save 900 1
-save 300 10
+{{- range .Spec.Redis.Persistence.GetRDBSchedule}}
+save {{.Seconds}} {{.Changes}}
+{{- else}}
+save ""
+{{- end}}
+{{- if .Spec.Redis.Persistence.AOFEnabled}}
+appendonly yes
+appendfsync {{.Spec.Redis.Persistence.GetAppendFsync}}
+aof-use-rdb-preamble {{if .Spec.Redis.Persistence.GetAOFUseRDBPreamble}}yes{{else}}no{{end}}
+{{- end}}
 user pinger -@all +ping on >pingpass
 {{- range .Spec.Redis.CustomCommandRenames}}
 rename-command "{{.From}}" "{{.To}}"
 {{- end}}
 `
 
-	sentinelConfigTemplate = `sentinel monitor mymaster 127.0.0.1 {{.Spec.Redis.Port}} 2
-sentinel down-after-milliseconds mymaster 1000
-sentinel failover-timeout mymaster 3000
-sentinel parallel-syncs mymaster 2`
+	sentinelConfigTemplate = `
+{{- if .Spec.AnnounceHostnames -}}
+sentinel resolve-hostnames yes
+sentinel announce-hostnames yes
+{{end -}}
+{{- $masterName := getSentinelMasterName . -}}
+sentinel monitor {{$masterName}} 127.0.0.1 {{.Spec.Redis.Port}} 2
+sentinel down-after-milliseconds {{$masterName}} 1000
+sentinel failover-timeout {{$masterName}} 3000
+sentinel parallel-syncs {{$masterName}} 2`
 
 	redisShutdownConfigurationVolumeName   = "redis-shutdown-config"
 	redisStartupConfigurationVolumeName    = "redis-startup-config"
@@ -53,7 +70,7 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 	selectorLabels := generateSelectorLabels(sentinelRoleName, rf.Name)
 	labels = util.MergeLabels(labels, selectorLabels)
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
@@ -73,6 +90,17 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 			},
 		},
 	}
+	// The ServiceMonitor scrapes the exporter through the service
+	if sentinelServiceMonitored(rf) {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:     exporterPortName,
+			Port:     sentinelExporterPort,
+			Protocol: corev1.ProtocolTCP,
+		})
+	}
+	applyServiceSettings(svc, rf.Spec.Sentinel.Service)
+
+	return svc
 }
 
 func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
@@ -81,10 +109,28 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 
 	selectorLabels := generateSelectorLabels(redisRoleName, rf.Name)
 	labels = util.MergeLabels(labels, selectorLabels)
-	defaultAnnotations := map[string]string{
-		"prometheus.io/scrape": "true",
-		"prometheus.io/port":   "http",
-		"prometheus.io/path":   "/metrics",
+	defaultAnnotations := map[string]string{}
+	ports := []corev1.ServicePort{
+		{
+			Port:       rf.Spec.Redis.Port,
+			TargetPort: intstr.FromInt(int(rf.Spec.Redis.Port)),
+			Protocol:   corev1.ProtocolTCP,
+			Name:       "redis",
+		},
+	}
+	if rf.Spec.Redis.Exporter.Enabled {
+		defaultAnnotations = map[string]string{
+			"prometheus.io/scrape": "true",
+			"prometheus.io/port":   "http",
+			"prometheus.io/path":   "/metrics",
+		}
+		ports = []corev1.ServicePort{
+			{
+				Port:     exporterPort,
+				Protocol: corev1.ProtocolTCP,
+				Name:     exporterPortName,
+			},
+		}
 	}
 	annotations := util.MergeLabels(defaultAnnotations, rf.Spec.Redis.ServiceAnnotations)
 
@@ -99,14 +145,10 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 		Spec: corev1.ServiceSpec{
 			Type:      corev1.ServiceTypeClusterIP,
 			ClusterIP: corev1.ClusterIPNone,
-			Ports: []corev1.ServicePort{
-				{
-					Port:     exporterPort,
-					Protocol: corev1.ProtocolTCP,
-					Name:     exporterPortName,
-				},
-			},
-			Selector: selectorLabels,
+			Ports:     ports,
+			Selector:  selectorLabels,
+			// Replicas must be able to resolve the master hostname before they are ready
+			PublishNotReadyAddresses: rf.Spec.AnnounceHostnames,
 		},
 	}
 }
@@ -121,7 +163,7 @@ func generateRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[st
 	})
 	labels = util.MergeLabels(labels, selectorLabels)
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
@@ -142,6 +184,24 @@ func generateRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[st
 			Selector: selectorLabels,
 		},
 	}
+	applyServiceSettings(svc, rf.Spec.Redis.MasterService)
+
+	return svc
+}
+
+// applyServiceSettings sets the exposure settings requested on the spec over a generated service.
+func applyServiceSettings(svc *corev1.Service, settings redisfailoverv1.ServiceSettings) {
+	if settings.Type != "" {
+		svc.Spec.Type = settings.Type
+	}
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerIP = settings.LoadBalancerIP
+		svc.Spec.LoadBalancerSourceRanges = settings.LoadBalancerSourceRanges
+	}
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer || svc.Spec.Type == corev1.ServiceTypeNodePort {
+		svc.Spec.ExternalTrafficPolicy = settings.ExternalTrafficPolicy
+	}
+	svc.Spec.Ports = append(svc.Spec.Ports, settings.ExtraPorts...)
 }
 
 func generateRedisSlaveService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
@@ -183,7 +243,9 @@ func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[str
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(sentinelRoleName, rf.Name))
 
-	tmpl, err := template.New("sentinel").Parse(sentinelConfigTemplate)
+	tmpl, err := template.New("sentinel").Funcs(template.FuncMap{
+		"getSentinelMasterName": GetSentinelMasterName,
+	}).Parse(sentinelConfigTemplate)
 	if err != nil {
 		panic(err)
 	}
@@ -223,6 +285,10 @@ func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string
 	}
 
 	redisConfigFileContent := tplOutput.String()
+	_, restartConfigs := splitRedisCustomConfig(rf.Spec.Redis.CustomConfig)
+	for _, config := range restartConfigs {
+		redisConfigFileContent = fmt.Sprintf("%s%s\n", redisConfigFileContent, config)
+	}
 
 	if password != "" {
 		redisConfigFileContent = fmt.Sprintf("%s\nmasterauth %s\nrequirepass %s", redisConfigFileContent, password, password)
@@ -246,19 +312,41 @@ func generateRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels ma
 	port := rf.Spec.Redis.Port
 	namespace := rf.Namespace
 	rfName := strings.Replace(strings.ToUpper(rf.Name), "-", "_", -1)
+	// hostname -i returns every pod IP on dual-stack clusters
+	selfAddress := "$(hostname -i)"
+	if rf.Spec.AnnounceHostnames {
+		selfAddress = "${REDIS_ANNOUNCE_HOSTNAME}"
+	} else if rf.Spec.Redis.AnnounceIP != "" {
+		selfAddress = "${REDIS_ANNOUNCE_IP}"
+	}
+
+	// The failover is asked to the sentinel service, to the service of the pool or to the first sentinel
+	// of a shared group
+	sentinelHost := fmt.Sprintf("${RFS_%s_SERVICE_HOST}", rfName)
+	sentinelPort := fmt.Sprintf("${RFS_%s_SERVICE_PORT_SENTINEL}", rfName)
+	if rf.PooledSentinels() {
+		sentinelHost = GetSentinelPoolName(rf)
+		sentinelPort = "26379"
+	} else if rf.SharedSentinels() {
+		sentinelHost = rf.Spec.Sentinel.SharedGroup.Addresses[0]
+		sentinelPort = "26379"
+	}
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
-	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
-if [ "$master" = "$(hostname -i)" ]; then
-  redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} SENTINEL failover mymaster
-  sleep 31
-fi
-cmd="redis-cli -p %[2]v"
+	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h %[1]v -p %[2]v --csv SENTINEL get-master-addr-by-name %[5]v | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
+for address in %[4]v; do
+  if [ "$master" = "$address" ]; then
+    redis-cli -h %[1]v -p %[2]v SENTINEL failover %[5]v
+    sleep 31
+    break
+  fi
+done
+cmd="redis-cli -p %[3]v"
 if [ ! -z "${REDIS_PASSWORD}" ]; then
 	export REDISCLI_AUTH=${REDIS_PASSWORD}
 fi
 save_command="${cmd} save"
-eval $save_command`, rfName, port)
+eval $save_command`, sentinelHost, sentinelPort, port, selfAddress, GetSentinelMasterName(rf))
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -367,7 +455,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: rf.Spec.Redis.PodAnnotations,
+					Annotations: getRedisPodAnnotations(rf),
 				},
 				Spec: corev1.PodSpec{
 					Affinity:                      getAffinity(rf.Spec.Redis.Affinity, labels),
@@ -381,6 +469,41 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 					PriorityClassName:             rf.Spec.Redis.PriorityClassName,
 					ServiceAccountName:            rf.Spec.Redis.ServiceAccountName,
 					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
+					// The configuration is copied to a writable volume, so redis can rewrite it with the
+					// configuration applied at runtime
+					InitContainers: []corev1.Container{
+						{
+							Name:            "redis-config-copy",
+							Image:           rf.Spec.Redis.Image,
+							ImagePullPolicy: pullPolicy(rf.Spec.Redis.ImagePullPolicy),
+							SecurityContext: getContainerSecurityContext(rf.Spec.Redis.ContainerSecurityContext),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      redisConfigurationVolumeName,
+									MountPath: "/redis",
+								},
+								{
+									Name:      redisWritableConfigurationVolumeName,
+									MountPath: "/redis-writable",
+								},
+							},
+							Command: []string{
+								"cp",
+								fmt.Sprintf("/redis/%s", redisConfigFileName),
+								fmt.Sprintf("/redis-writable/%s", redisConfigFileName),
+							},
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("10m"),
+									corev1.ResourceMemory: resource.MustParse("32Mi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("10m"),
+									corev1.ResourceMemory: resource.MustParse("32Mi"),
+								},
+							},
+						},
+					},
 					Containers: []corev1.Container{
 						{
 							Name:            "redis",
@@ -604,6 +727,10 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 		},
 	}
 
+	if rf.Spec.Sentinel.AnnounceIP != "" || rf.Spec.Sentinel.AnnouncePort > 0 {
+		setSentinelAnnounce(rf, &sd.Spec.Template.Spec.InitContainers[0])
+	}
+
 	if rf.Spec.Sentinel.CustomLivenessProbe != nil {
 		sd.Spec.Template.Spec.Containers[0].LivenessProbe = rf.Spec.Sentinel.CustomLivenessProbe
 	} else {
@@ -633,7 +760,7 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 					Command: []string{
 						"sh",
 						"-c",
-						"redis-cli -h $(hostname) -p 26379 sentinel get-master-addr-by-name mymaster | head -n 1 | grep -vq '127.0.0.1'",
+						fmt.Sprintf("redis-cli -h $(hostname) -p 26379 sentinel get-master-addr-by-name %s | head -n 1 | grep -vq '127.0.0.1'", GetSentinelMasterName(rf)),
 					},
 				},
 			},
@@ -671,6 +798,53 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 	return sd
 }
 
+// setSentinelAnnounce makes the config copy init container append the announce directives to the
+// sentinel configuration. The values are resolved per pod, so they can reference $(HOST_IP) or $(POD_IP).
+func setSentinelAnnounce(rf *redisfailoverv1.RedisFailover, c *corev1.Container) {
+	c.Env = []corev1.EnvVar{
+		{
+			Name: "HOST_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.hostIP",
+				},
+			},
+		},
+		{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
+				},
+			},
+		},
+	}
+
+	writableConfig := fmt.Sprintf("/redis-writable/%s", sentinelConfigFileName)
+	script := fmt.Sprintf("cp /redis/%s %s", sentinelConfigFileName, writableConfig)
+	if rf.Spec.Sentinel.AnnounceIP != "" {
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  "SENTINEL_ANNOUNCE_IP",
+			Value: rf.Spec.Sentinel.AnnounceIP,
+		})
+		script = fmt.Sprintf("%s && echo \"sentinel announce-ip ${SENTINEL_ANNOUNCE_IP}\" >> %s", script, writableConfig)
+	}
+	if rf.Spec.Sentinel.AnnouncePort > 0 {
+		script = fmt.Sprintf("%s && echo \"sentinel announce-port %d\" >> %s", script, rf.Spec.Sentinel.AnnouncePort, writableConfig)
+	}
+	c.Command = []string{"sh", "-c", script}
+}
+
+// generateComponentPodDisruptionBudget returns the pdb of the redis or sentinel pods of the RedisFailover
+func generateComponentPodDisruptionBudget(rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(2)
+	if rf.Spec.Redis.Replicas <= 2 {
+		minAvailable = intstr.FromInt(1)
+	}
+	labels = util.MergeLabels(labels, generateSelectorLabels(component, rf.Name))
+	return generatePodDisruptionBudget(generateName(name, rf.Name), rf.Namespace, labels, ownerRefs, minAvailable)
+}
+
 func generatePodDisruptionBudget(name string, namespace string, labels map[string]string, ownerRefs []metav1.OwnerReference, minAvailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
 	return &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
@@ -849,13 +1023,16 @@ func getDnsPolicy(dnspolicy corev1.DNSPolicy) corev1.DNSPolicy {
 }
 
 func getQuorum(rf *redisfailoverv1.RedisFailover) int32 {
+	if rf.Spec.Sentinel.SharedGroup != nil {
+		return rf.Spec.Sentinel.SharedGroup.Quorum
+	}
 	return rf.Spec.Sentinel.Replicas/2 + 1
 }
 
 func getRedisVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMount {
 	volumeMounts := []corev1.VolumeMount{
 		{
-			Name:      redisConfigurationVolumeName,
+			Name:      redisWritableConfigurationVolumeName,
 			MountPath: "/redis",
 		},
 		{
@@ -927,6 +1104,12 @@ func getRedisVolumes(rf *redisfailoverv1.RedisFailover) []corev1.Volume {
 				},
 			},
 		},
+		{
+			Name: redisWritableConfigurationVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
 		{
 			Name: redisShutdownConfigurationVolumeName,
 			VolumeSource: corev1.VolumeSource{
@@ -1058,14 +1241,57 @@ func getRedisDataVolumeName(rf *redisfailoverv1.RedisFailover) string {
 	}
 }
 
+// splitRedisCustomConfig separates the custom configuration that can be applied to a running redis from the
+// one that needs a restart
+func splitRedisCustomConfig(configs []string) (runtimeConfigs []string, restartConfigs []string) {
+	for _, config := range configs {
+		fields := strings.Fields(config)
+		if len(fields) > 0 && redisRestartRequiredConfigs[strings.ToLower(fields[0])] {
+			restartConfigs = append(restartConfigs, config)
+		} else {
+			runtimeConfigs = append(runtimeConfigs, config)
+		}
+	}
+	return runtimeConfigs, restartConfigs
+}
+
+// getRedisPodAnnotations returns the annotations of the redis pods. When there is custom configuration
+// that needs a restart, its checksum is added so changing it rolls the pods.
+func getRedisPodAnnotations(rf *redisfailoverv1.RedisFailover) map[string]string {
+	_, restartConfigs := splitRedisCustomConfig(rf.Spec.Redis.CustomConfig)
+	if len(restartConfigs) == 0 {
+		return rf.Spec.Redis.PodAnnotations
+	}
+
+	annotations := map[string]string{}
+	for k, v := range rf.Spec.Redis.PodAnnotations {
+		annotations[k] = v
+	}
+	checksum := sha256.Sum256([]byte(strings.Join(restartConfigs, "\n")))
+	annotations[redisRestartConfigChecksumAnnotation] = hex.EncodeToString(checksum[:])
+	return annotations
+}
+
 func getRedisCommand(rf *redisfailoverv1.RedisFailover) []string {
 	if len(rf.Spec.Redis.Command) > 0 {
 		return rf.Spec.Redis.Command
 	}
-	return []string{
-		"redis-server",
-		fmt.Sprintf("/redis/%s", redisConfigFileName),
+	cmd := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf(redisStartScript, redisConfigFileName, rf.Spec.Redis.Port),
+		"--",
+	}
+	if rf.Spec.AnnounceHostnames {
+		cmd = append(cmd, "--replica-announce-ip", "$(REDIS_ANNOUNCE_HOSTNAME)")
 	}
+	if rf.Spec.Redis.AnnounceIP != "" {
+		cmd = append(cmd, "--replica-announce-ip", "$(REDIS_ANNOUNCE_IP)")
+	}
+	if rf.Spec.Redis.AnnouncePort > 0 {
+		cmd = append(cmd, "--replica-announce-port", fmt.Sprintf("%d", rf.Spec.Redis.AnnouncePort))
+	}
+	return cmd
 }
 
 func getSentinelCommand(rf *redisfailoverv1.RedisFailover) []string {
@@ -1135,6 +1361,48 @@ func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 		Value: "default",
 	})
 
+	if rf.Spec.AnnounceHostnames {
+		env = append(env, corev1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
+
+		env = append(env, corev1.EnvVar{
+			Name:  "REDIS_ANNOUNCE_HOSTNAME",
+			Value: GetRedisPodHostname(rf, "$(POD_NAME)"),
+		})
+	}
+
+	if rf.Spec.Redis.AnnounceIP != "" {
+		// The announced address is resolved per pod, so it can reference the IPs of the pod
+		env = append(env, corev1.EnvVar{
+			Name: "HOST_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.hostIP",
+				},
+			},
+		})
+
+		env = append(env, corev1.EnvVar{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
+				},
+			},
+		})
+
+		env = append(env, corev1.EnvVar{
+			Name:  "REDIS_ANNOUNCE_IP",
+			Value: rf.Spec.Redis.AnnounceIP,
+		})
+	}
+
 	if rf.Spec.Auth.SecretPath != "" {
 		env = append(env, corev1.EnvVar{
 			Name: "REDIS_PASSWORD",
@@ -2,14 +2,17 @@ package service_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
@@ -40,7 +43,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 								{
 									VolumeMounts: []corev1.VolumeMount{
 										{
-											Name:      "redis-config",
+											Name:      "redis-config-writable",
 											MountPath: "/redis",
 										},
 										{
@@ -69,6 +72,12 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-config-writable",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
 								{
 									Name: "redis-shutdown-config",
 									VolumeSource: corev1.VolumeSource{
@@ -114,7 +123,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 								{
 									VolumeMounts: []corev1.VolumeMount{
 										{
-											Name:      "redis-config",
+											Name:      "redis-config-writable",
 											MountPath: "/redis",
 										},
 										{
@@ -143,6 +152,12 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-config-writable",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
 								{
 									Name: "redis-shutdown-config",
 									VolumeSource: corev1.VolumeSource{
@@ -194,7 +209,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 								{
 									VolumeMounts: []corev1.VolumeMount{
 										{
-											Name:      "redis-config",
+											Name:      "redis-config-writable",
 											MountPath: "/redis",
 										},
 										{
@@ -223,6 +238,12 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-config-writable",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
 								{
 									Name: "redis-shutdown-config",
 									VolumeSource: corev1.VolumeSource{
@@ -304,7 +325,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 								{
 									VolumeMounts: []corev1.VolumeMount{
 										{
-											Name:      "redis-config",
+											Name:      "redis-config-writable",
 											MountPath: "/redis",
 										},
 										{
@@ -333,6 +354,12 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-config-writable",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
 								{
 									Name: "redis-shutdown-config",
 									VolumeSource: corev1.VolumeSource{
@@ -419,7 +446,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 								{
 									VolumeMounts: []corev1.VolumeMount{
 										{
-											Name:      "redis-config",
+											Name:      "redis-config-writable",
 											MountPath: "/redis",
 										},
 										{
@@ -448,6 +475,12 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-config-writable",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
 								{
 									Name: "redis-shutdown-config",
 									VolumeSource: corev1.VolumeSource{
@@ -528,6 +561,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			ss := args.Get(1).(*appsv1.StatefulSet)
 			generatedStatefulSet = *ss
@@ -544,6 +578,13 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 	}
 }
 
+// redisStartScript is the script starting redis with the default command, dropping the replication state
+// stored on its configuration file by the previous run
+const redisStartScript = `config=/redis/redis.conf
+{ echo "slaveof 127.0.0.1 0"; grep -v -e "^slaveof " -e "^replicaof " "$config"; } > "$config.tmp"
+mv "$config.tmp" "$config"
+exec redis-server "$config" "$@"`
+
 func TestRedisStatefulSetCommands(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -554,8 +595,10 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 			name:          "Default values",
 			givenCommands: []string{},
 			expectedCommands: []string{
-				"redis-server",
-				"/redis/redis.conf",
+				"/bin/sh",
+				"-c",
+				redisStartScript,
+				"--",
 			},
 		},
 		{
@@ -582,6 +625,7 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			ss := args.Get(1).(*appsv1.StatefulSet)
 			gotCommands = ss.Spec.Template.Spec.Containers[0].Command
@@ -647,6 +691,67 @@ func TestSentinelDeploymentCommands(t *testing.T) {
 	}
 }
 
+func TestSentinelDeploymentAnnounce(t *testing.T) {
+	tests := []struct {
+		name                 string
+		announceIP           string
+		announcePort         int32
+		expectedInitCommands []string
+		expectedAnnounceEnv  string
+	}{
+		{
+			name: "Announce not defined",
+			expectedInitCommands: []string{
+				"cp",
+				"/redis/sentinel.conf",
+				"/redis-writable/sentinel.conf",
+			},
+		},
+		{
+			name:         "Announce IP and port defined",
+			announceIP:   "$(HOST_IP)",
+			announcePort: 26379,
+			expectedInitCommands: []string{
+				"sh",
+				"-c",
+				`cp /redis/sentinel.conf /redis-writable/sentinel.conf && echo "sentinel announce-ip ${SENTINEL_ANNOUNCE_IP}" >> /redis-writable/sentinel.conf && echo "sentinel announce-port 26379" >> /redis-writable/sentinel.conf`,
+			},
+			expectedAnnounceEnv: "$(HOST_IP)",
+		},
+	}
+
+	for _, test := range tests {
+		assert := assert.New(t)
+
+		rf := generateRF()
+		rf.Spec.Sentinel.AnnounceIP = test.announceIP
+		rf.Spec.Sentinel.AnnouncePort = test.announcePort
+
+		var gotInitContainer corev1.Container
+
+		ms := &mK8SService.Services{}
+		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateDeployment", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(1).(*appsv1.Deployment)
+			gotInitContainer = d.Spec.Template.Spec.InitContainers[0]
+		}).Return(nil)
+
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
+
+		assert.NoError(err)
+		assert.Equal(test.expectedInitCommands, gotInitContainer.Command, test.name)
+
+		gotAnnounceEnv := ""
+		for _, env := range gotInitContainer.Env {
+			if env.Name == "SENTINEL_ANNOUNCE_IP" {
+				gotAnnounceEnv = env.Value
+			}
+		}
+		assert.Equal(test.expectedAnnounceEnv, gotAnnounceEnv, test.name)
+	}
+}
+
 func TestRedisStatefulSetPodAnnotations(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -682,6 +787,7 @@ func TestRedisStatefulSetPodAnnotations(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			ss := args.Get(1).(*appsv1.StatefulSet)
 			gotPodAnnotations = ss.Spec.Template.ObjectMeta.Annotations
@@ -772,6 +878,7 @@ func TestRedisStatefulSetServiceAccountName(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			ss := args.Get(1).(*appsv1.StatefulSet)
 			gotServiceAccountName = ss.Spec.Template.Spec.ServiceAccountName
@@ -834,6 +941,8 @@ func TestSentinelService(t *testing.T) {
 		rfNamespace     string
 		rfLabels        map[string]string
 		rfAnnotations   map[string]string
+		rfService       redisfailoverv1.ServiceSettings
+		rfMonitoring    bool
 		expectedService corev1.Service
 	}{
 		{
@@ -1012,6 +1121,87 @@ func TestSentinelService(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with NodePort settings",
+			rfService: redisfailoverv1.ServiceSettings{
+				Type:                     corev1.ServiceTypeNodePort,
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+				ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+			},
+			expectedService: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      sentinelName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/component": "sentinel",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Name: "testing",
+						},
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:                  corev1.ServiceTypeNodePort,
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					Selector: map[string]string{
+						"app.kubernetes.io/component": "sentinel",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "sentinel",
+							Port:       26379,
+							TargetPort: intstr.FromInt(26379),
+							Protocol:   "TCP",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:         "with the sentinel exporter monitored by a ServiceMonitor",
+			rfMonitoring: true,
+			expectedService: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      sentinelName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/component": "sentinel",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Name: "testing",
+						},
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{
+						"app.kubernetes.io/component": "sentinel",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "sentinel",
+							Port:       26379,
+							TargetPort: intstr.FromInt(26379),
+							Protocol:   "TCP",
+						},
+						{
+							Name:     "http-metrics",
+							Port:     9355,
+							Protocol: "TCP",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1027,6 +1217,11 @@ func TestSentinelService(t *testing.T) {
 				rf.Namespace = test.rfNamespace
 			}
 			rf.Spec.Sentinel.ServiceAnnotations = test.rfAnnotations
+			rf.Spec.Sentinel.Service = test.rfService
+			if test.rfMonitoring {
+				rf.Spec.Sentinel.Exporter.Enabled = true
+				rf.Spec.Monitoring = redisfailoverv1.MonitoringSettings{Enabled: true, MonitorKind: redisfailoverv1.MonitorKindServiceMonitor}
+			}
 
 			generatedService := corev1.Service{}
 
@@ -1052,6 +1247,7 @@ func TestRedisService(t *testing.T) {
 		rfNamespace     string
 		rfLabels        map[string]string
 		rfAnnotations   map[string]string
+		withoutExporter bool
 		expectedService corev1.Service
 	}{
 		{
@@ -1260,6 +1456,45 @@ func TestRedisService(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "with announced hostnames and without exporter",
+			withoutExporter: true,
+			expectedService: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      redisName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/component": "redis",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+					Annotations: map[string]string{},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Name: "testing",
+						},
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:      corev1.ServiceTypeClusterIP,
+					ClusterIP: corev1.ClusterIPNone,
+					Selector: map[string]string{
+						"app.kubernetes.io/component": "redis",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "redis",
+							Port:       6379,
+							TargetPort: intstr.FromInt(6379),
+							Protocol:   corev1.ProtocolTCP,
+						},
+					},
+					PublishNotReadyAddresses: true,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1275,6 +1510,9 @@ func TestRedisService(t *testing.T) {
 				rf.Namespace = test.rfNamespace
 			}
 			rf.Spec.Redis.ServiceAnnotations = test.rfAnnotations
+			rf.Spec.Redis.Exporter.Enabled = !test.withoutExporter
+			rf.Spec.AnnounceHostnames = test.withoutExporter
+			rf.Spec.Redis.Port = 6379
 
 			generatedService := corev1.Service{}
 
@@ -1300,6 +1538,7 @@ func TestRedisMasterService(t *testing.T) {
 		rfNamespace     string
 		rfLabels        map[string]string
 		rfAnnotations   map[string]string
+		rfMasterService redisfailoverv1.ServiceSettings
 		expectedService corev1.Service
 	}{
 		{
@@ -1499,6 +1738,67 @@ func TestRedisMasterService(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with LoadBalancer settings",
+			rfMasterService: redisfailoverv1.ServiceSettings{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				LoadBalancerIP:           "10.0.0.10",
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+				ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+				ExtraPorts: []corev1.ServicePort{
+					{
+						Name:       "redis-tls",
+						Port:       6380,
+						Protocol:   corev1.ProtocolTCP,
+						TargetPort: intstr.FromInt(6380),
+					},
+				},
+			},
+			expectedService: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      masterName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/component": "redis",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+						"redisfailovers-role":         "master",
+					},
+					Annotations: nil,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Name: "testing",
+						},
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:                     corev1.ServiceTypeLoadBalancer,
+					LoadBalancerIP:           "10.0.0.10",
+					LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+					ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+					Selector: map[string]string{
+						"app.kubernetes.io/component": "redis",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+						"redisfailovers-role":         "master",
+					},
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "redis",
+							Port:       6379,
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: intstr.FromString("redis"),
+						},
+						{
+							Name:       "redis-tls",
+							Port:       6380,
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: intstr.FromInt(6380),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1515,6 +1815,7 @@ func TestRedisMasterService(t *testing.T) {
 			}
 			rf.Spec.Redis.Port = 6379
 			rf.Spec.Redis.ServiceAnnotations = test.rfAnnotations
+			rf.Spec.Redis.MasterService = test.rfMasterService
 
 			generatedMasterService := corev1.Service{}
 
@@ -1807,6 +2108,7 @@ func TestRedisHostNetworkAndDnsPolicy(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			ss := args.Get(1).(*appsv1.StatefulSet)
 			actualHostNetwork = ss.Spec.Template.Spec.HostNetwork
@@ -1906,6 +2208,7 @@ func TestRedisImagePullPolicy(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			ss := args.Get(1).(*appsv1.StatefulSet)
 			policy = ss.Spec.Template.Spec.Containers[0].ImagePullPolicy
@@ -2027,9 +2330,10 @@ func TestRedisExtraVolumeMounts(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
-			extraVolume = s.Spec.Template.Spec.Volumes[3]
+			extraVolume = s.Spec.Template.Spec.Volumes[4]
 			extraVolumeMount = s.Spec.Template.Spec.Containers[0].VolumeMounts[4]
 		}).Return(nil)
 
@@ -2159,6 +2463,7 @@ func TestCustomPort(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
 			port = s.Spec.Template.Spec.Containers[0].Ports[0]
@@ -2241,6 +2546,7 @@ func TestRedisEnv(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
 			env = s.Spec.Template.Spec.Containers[0].Env
@@ -2254,6 +2560,271 @@ func TestRedisEnv(t *testing.T) {
 	}
 }
 
+func TestAnnounceHostnames(t *testing.T) {
+	tests := []struct {
+		name                     string
+		announceHostnames        bool
+		expectedRedisCommand     []string
+		expectedSentinelConfig   string
+		expectedPublishNotReady  bool
+		expectedShutdownSelfAddr string
+	}{
+		{
+			name:              "Hostnames not announced",
+			announceHostnames: false,
+			expectedRedisCommand: []string{
+				"/bin/sh",
+				"-c",
+				redisStartScript,
+				"--",
+			},
+			expectedSentinelConfig: `sentinel monitor mymaster 127.0.0.1 0 2
+sentinel down-after-milliseconds mymaster 1000
+sentinel failover-timeout mymaster 3000
+sentinel parallel-syncs mymaster 2`,
+			expectedPublishNotReady:  false,
+			expectedShutdownSelfAddr: `for address in $(hostname -i); do`,
+		},
+		{
+			name:              "Hostnames announced",
+			announceHostnames: true,
+			expectedRedisCommand: []string{
+				"/bin/sh",
+				"-c",
+				redisStartScript,
+				"--",
+				"--replica-announce-ip",
+				"$(REDIS_ANNOUNCE_HOSTNAME)",
+			},
+			expectedSentinelConfig: `sentinel resolve-hostnames yes
+sentinel announce-hostnames yes
+sentinel monitor mymaster 127.0.0.1 0 2
+sentinel down-after-milliseconds mymaster 1000
+sentinel failover-timeout mymaster 3000
+sentinel parallel-syncs mymaster 2`,
+			expectedPublishNotReady:  true,
+			expectedShutdownSelfAddr: `for address in ${REDIS_ANNOUNCE_HOSTNAME}; do`,
+		},
+	}
+
+	for _, test := range tests {
+		assert := assert.New(t)
+
+		rf := generateRF()
+		rf.Spec.AnnounceHostnames = test.announceHostnames
+
+		var gotRedisCommand []string
+		var gotRedisEnv []corev1.EnvVar
+		var gotSentinelConfig string
+		var gotShutdownScript string
+		var gotPublishNotReady bool
+
+		ms := &mK8SService.Services{}
+		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
+		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(1).(*appsv1.StatefulSet)
+			gotRedisCommand = ss.Spec.Template.Spec.Containers[0].Command
+			gotRedisEnv = ss.Spec.Template.Spec.Containers[0].Env
+		}).Return(nil)
+		ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Twice().Run(func(args mock.Arguments) {
+			cm := args.Get(1).(*corev1.ConfigMap)
+			if config, ok := cm.Data["sentinel.conf"]; ok {
+				gotSentinelConfig = config
+			}
+			if script, ok := cm.Data["shutdown.sh"]; ok {
+				gotShutdownScript = script
+			}
+		}).Return(nil)
+		ms.On("CreateOrUpdateService", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			s := args.Get(1).(*corev1.Service)
+			gotPublishNotReady = s.Spec.PublishNotReadyAddresses
+		}).Return(nil)
+
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+		assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+		assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+		assert.NoError(client.EnsureRedisService(rf, nil, []metav1.OwnerReference{}))
+
+		assert.Equal(test.expectedRedisCommand, gotRedisCommand, test.name)
+		assert.Equal(test.expectedSentinelConfig, gotSentinelConfig, test.name)
+		assert.Equal(test.expectedPublishNotReady, gotPublishNotReady, test.name)
+		assert.Contains(gotShutdownScript, test.expectedShutdownSelfAddr, test.name)
+
+		if test.announceHostnames {
+			assert.Contains(gotRedisEnv, corev1.EnvVar{
+				Name:  "REDIS_ANNOUNCE_HOSTNAME",
+				Value: "$(POD_NAME).rfr-test.testns.svc",
+			}, test.name)
+		}
+	}
+}
+
+func TestRedisAnnounce(t *testing.T) {
+	tests := []struct {
+		name                     string
+		announceIP               string
+		announcePort             int32
+		expectedRedisCommand     []string
+		expectedAnnounceEnv      string
+		expectedShutdownSelfAddr string
+	}{
+		{
+			name: "Announce not defined",
+			expectedRedisCommand: []string{
+				"/bin/sh",
+				"-c",
+				redisStartScript,
+				"--",
+			},
+			expectedShutdownSelfAddr: `for address in $(hostname -i); do`,
+		},
+		{
+			name:         "Announce IP and port defined",
+			announceIP:   "$(HOST_IP)",
+			announcePort: 30379,
+			expectedRedisCommand: []string{
+				"/bin/sh",
+				"-c",
+				redisStartScript,
+				"--",
+				"--replica-announce-ip",
+				"$(REDIS_ANNOUNCE_IP)",
+				"--replica-announce-port",
+				"30379",
+			},
+			expectedAnnounceEnv:      "$(HOST_IP)",
+			expectedShutdownSelfAddr: `for address in ${REDIS_ANNOUNCE_IP}; do`,
+		},
+		{
+			name:         "Announce port defined",
+			announcePort: 30379,
+			expectedRedisCommand: []string{
+				"/bin/sh",
+				"-c",
+				redisStartScript,
+				"--",
+				"--replica-announce-port",
+				"30379",
+			},
+			expectedShutdownSelfAddr: `for address in $(hostname -i); do`,
+		},
+	}
+
+	for _, test := range tests {
+		assert := assert.New(t)
+
+		rf := generateRF()
+		rf.Spec.Redis.AnnounceIP = test.announceIP
+		rf.Spec.Redis.AnnouncePort = test.announcePort
+
+		var gotRedisCommand []string
+		var gotRedisEnv []corev1.EnvVar
+		var gotShutdownScript string
+
+		ms := &mK8SService.Services{}
+		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
+		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(1).(*appsv1.StatefulSet)
+			gotRedisCommand = ss.Spec.Template.Spec.Containers[0].Command
+			gotRedisEnv = ss.Spec.Template.Spec.Containers[0].Env
+		}).Return(nil)
+		ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			gotShutdownScript = args.Get(1).(*corev1.ConfigMap).Data["shutdown.sh"]
+		}).Return(nil)
+
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+		assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+
+		assert.Equal(test.expectedRedisCommand, gotRedisCommand, test.name)
+		assert.Contains(gotShutdownScript, test.expectedShutdownSelfAddr, test.name)
+
+		gotAnnounceEnv := ""
+		for _, env := range gotRedisEnv {
+			if env.Name == "REDIS_ANNOUNCE_IP" {
+				gotAnnounceEnv = env.Value
+			}
+		}
+		assert.Equal(test.expectedAnnounceEnv, gotAnnounceEnv, test.name)
+	}
+}
+
+func TestSentinelMasterName(t *testing.T) {
+	tests := []struct {
+		name                   string
+		sharedGroup            *redisfailoverv1.SharedSentinelGroup
+		poolRef                *redisfailoverv1.SentinelPoolReference
+		expectedSentinelConfig string
+		expectedShutdownScript []string
+	}{
+		{
+			name: "Dedicated sentinels",
+			expectedSentinelConfig: `sentinel monitor cache 127.0.0.1 0 2
+sentinel down-after-milliseconds cache 1000
+sentinel failover-timeout cache 3000
+sentinel parallel-syncs cache 2`,
+			expectedShutdownScript: []string{
+				`master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name cache |`,
+				`redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} SENTINEL failover cache`,
+			},
+		},
+		{
+			name:        "Shared sentinels",
+			sharedGroup: &redisfailoverv1.SharedSentinelGroup{Addresses: []string{"sentinel-0.shared", "sentinel-1.shared"}, Quorum: 2},
+			expectedShutdownScript: []string{
+				`master=$(redis-cli -h sentinel-0.shared -p 26379 --csv SENTINEL get-master-addr-by-name cache |`,
+				`redis-cli -h sentinel-0.shared -p 26379 SENTINEL failover cache`,
+			},
+		},
+		{
+			name:    "Pooled sentinels",
+			poolRef: &redisfailoverv1.SentinelPoolReference{Name: "pool"},
+			expectedShutdownScript: []string{
+				`master=$(redis-cli -h rfs-pool -p 26379 --csv SENTINEL get-master-addr-by-name cache |`,
+				`redis-cli -h rfs-pool -p 26379 SENTINEL failover cache`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Sentinel.MasterName = "cache"
+			rf.Spec.Sentinel.SharedGroup = test.sharedGroup
+			rf.Spec.Sentinel.PoolRef = test.poolRef
+
+			var gotSentinelConfig string
+			var gotShutdownScript string
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Run(func(args mock.Arguments) {
+				cm := args.Get(1).(*corev1.ConfigMap)
+				if config, ok := cm.Data["sentinel.conf"]; ok {
+					gotSentinelConfig = config
+				}
+				if script, ok := cm.Data["shutdown.sh"]; ok {
+					gotShutdownScript = script
+				}
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			if rf.DeploySentinels() {
+				assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+			}
+			assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+
+			assert.Equal(test.expectedSentinelConfig, gotSentinelConfig)
+			for _, line := range test.expectedShutdownScript {
+				assert.Contains(gotShutdownScript, line)
+			}
+		})
+	}
+}
+
 func TestRedisStartupProbe(t *testing.T) {
 	mode := int32(0744)
 	tests := []struct {
@@ -2292,6 +2863,7 @@ func TestRedisStartupProbe(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
 			startupVolumes = s.Spec.Template.Spec.Volumes
@@ -2429,6 +3001,7 @@ func TestRedisCustomLivenessProbe(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
 			livenessProbe = s.Spec.Template.Spec.Containers[0].LivenessProbe
@@ -2575,6 +3148,7 @@ func TestRedisCustomReadinessProbe(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
 			readinessProbe = s.Spec.Template.Spec.Containers[0].ReadinessProbe
@@ -2713,6 +3287,7 @@ func TestRedisCustomStartupProbe(t *testing.T) {
 
 		ms := &mK8SService.Services{}
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
 			startupProbe = s.Spec.Template.Spec.Containers[0].StartupProbe
@@ -2792,3 +3367,304 @@ func TestSentinelCustomStartupProbe(t *testing.T) {
 		assert.Equal(test.expectedStartupProbe, startupProbe)
 	}
 }
+
+func TestRedisStatefulSetRestoreFromRedisFailoverSnapshot(t *testing.T) {
+	rfGroup := redisfailoverv1.SchemeGroupVersion.Group
+	snapshotGroup := "snapshot.storage.k8s.io"
+
+	tests := []struct {
+		name              string
+		statefulSetExists bool
+		snapshotPhase     redisfailoverv1.RedisFailoverSnapshotPhase
+		expDataSource     *corev1.TypedLocalObjectReference
+		expErr            bool
+	}{
+		{
+			name:          "A new statefulset is restored from the volume snapshot",
+			snapshotPhase: redisfailoverv1.RedisFailoverSnapshotPhaseReady,
+			expDataSource: &corev1.TypedLocalObjectReference{APIGroup: &snapshotGroup, Kind: "VolumeSnapshot", Name: "backup-volume"},
+		},
+		{
+			name:          "A snapshot not ready can't be restored",
+			snapshotPhase: redisfailoverv1.RedisFailoverSnapshotPhaseSnapshotting,
+			expErr:        true,
+		},
+		{
+			name:              "An existing statefulset doesn't need the snapshot",
+			statefulSetExists: true,
+			expDataSource:     &corev1.TypedLocalObjectReference{APIGroup: &rfGroup, Kind: "RedisFailoverSnapshot", Name: "backup"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					DataSource: &corev1.TypedLocalObjectReference{APIGroup: &rfGroup, Kind: "RedisFailoverSnapshot", Name: "backup"},
+				},
+			}
+
+			var generatedStatefulSet *appsv1.StatefulSet
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil)
+			if test.statefulSetExists {
+				ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(&appsv1.StatefulSet{}, nil)
+			} else {
+				ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
+				ms.On("GetRedisFailoverSnapshot", mock.Anything, namespace, "backup").Once().Return(&redisfailoverv1.RedisFailoverSnapshot{
+					ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace},
+					Status: redisfailoverv1.RedisFailoverSnapshotStatus{
+						Phase:              test.snapshotPhase,
+						VolumeSnapshotName: "backup-volume",
+					},
+				}, nil)
+			}
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Run(func(args mock.Arguments) {
+				generatedStatefulSet = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil).Maybe()
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+
+			if test.expErr {
+				assert.Error(err)
+				assert.Nil(generatedStatefulSet)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expDataSource, generatedStatefulSet.Spec.VolumeClaimTemplates[0].Spec.DataSource)
+			}
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisStatefulSetKeepsPodTemplate(t *testing.T) {
+	storedTemplate := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "redis", Command: []string{"redis-server", "/redis/redis.conf"}},
+			},
+		},
+	}
+	tests := []struct {
+		name                 string
+		storedAnnotations    map[string]string
+		expKeptTemplate      bool
+		expGenerationOnStore string
+	}{
+		{
+			name:                 "statefulset of a previous version keeps its template",
+			expKeptTemplate:      true,
+			expGenerationOnStore: "2",
+		},
+		{
+			name:                 "same generation keeps the template",
+			storedAnnotations:    map[string]string{"redisfailovers.databases.spotahome.com/template-generation": "2"},
+			expKeptTemplate:      true,
+			expGenerationOnStore: "2",
+		},
+		{
+			name:                 "changed spec generates the template",
+			storedAnnotations:    map[string]string{"redisfailovers.databases.spotahome.com/template-generation": "1"},
+			expKeptTemplate:      false,
+			expGenerationOnStore: "2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Generation = 2
+			stored := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.storedAnnotations},
+				Spec:       appsv1.StatefulSetSpec{Template: storedTemplate},
+			}
+
+			var generatedStatefulSet *appsv1.StatefulSet
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil)
+			ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(stored, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				generatedStatefulSet = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+
+			if test.expKeptTemplate {
+				assert.Equal(storedTemplate, generatedStatefulSet.Spec.Template)
+			} else {
+				assert.NotEqual(storedTemplate, generatedStatefulSet.Spec.Template)
+				assert.Equal([]string{"/bin/sh", "-c", redisStartScript, "--"}, generatedStatefulSet.Spec.Template.Spec.Containers[0].Command)
+			}
+			assert.Equal(test.expGenerationOnStore, generatedStatefulSet.Annotations["redisfailovers.databases.spotahome.com/template-generation"])
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisConfigMapPersistence(t *testing.T) {
+	tests := []struct {
+		name           string
+		persistence    *redisfailoverv1.RedisPersistence
+		expectedConfig string
+	}{
+		{
+			name: "default",
+			expectedConfig: `save 900 1
+save 300 10
+user pinger`,
+		},
+		{
+			name:        "RDB with a custom schedule",
+			persistence: &redisfailoverv1.RedisPersistence{Mode: redisfailoverv1.PersistenceModeRDB, RDBSchedule: []redisfailoverv1.RDBSaveRule{{Seconds: 60, Changes: 1000}}},
+			expectedConfig: `save 60 1000
+user pinger`,
+		},
+		{
+			name:        "AOF",
+			persistence: &redisfailoverv1.RedisPersistence{Mode: redisfailoverv1.PersistenceModeAOF, AppendFsync: "always"},
+			expectedConfig: `save ""
+appendonly yes
+appendfsync always
+aof-use-rdb-preamble no
+user pinger`,
+		},
+		{
+			name:        "Hybrid",
+			persistence: &redisfailoverv1.RedisPersistence{Mode: redisfailoverv1.PersistenceModeHybrid},
+			expectedConfig: `save 900 1
+save 300 10
+appendonly yes
+appendfsync everysec
+aof-use-rdb-preamble yes
+user pinger`,
+		},
+		{
+			name:        "None",
+			persistence: &redisfailoverv1.RedisPersistence{Mode: redisfailoverv1.PersistenceModeNone},
+			expectedConfig: `save ""
+user pinger`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config string
+
+			rf := generateRF()
+			rf.Spec.Redis.Persistence = test.persistence
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				cm := args.Get(1).(*corev1.ConfigMap)
+				config = cm.Data["redis.conf"]
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{})
+
+			assert.NoError(err)
+			assert.Contains(config, "tcp-keepalive 60\n"+test.expectedConfig)
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisStatefulSetConfigCopy(t *testing.T) {
+	assert := assert.New(t)
+
+	var ss *appsv1.StatefulSet
+
+	rf := generateRF()
+	rf.Spec.Redis.Image = "redis:7"
+
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+	ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
+	ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		ss = args.Get(1).(*appsv1.StatefulSet)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+
+	assert.NoError(err)
+	if assert.Len(ss.Spec.Template.Spec.InitContainers, 1) {
+		initContainer := ss.Spec.Template.Spec.InitContainers[0]
+		assert.Equal("redis:7", initContainer.Image)
+		assert.Equal([]string{"cp", "/redis/redis.conf", "/redis-writable/redis.conf"}, initContainer.Command)
+		assert.Equal([]corev1.VolumeMount{
+			{Name: "redis-config", MountPath: "/redis"},
+			{Name: "redis-config-writable", MountPath: "/redis-writable"},
+		}, initContainer.VolumeMounts)
+	}
+	assert.Contains(ss.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "redis-config-writable", MountPath: "/redis"})
+}
+
+func TestRedisRestartRequiredConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		customConfig     []string
+		podAnnotations   map[string]string
+		expectedConfig   string
+		expectedChecksum bool
+	}{
+		{
+			name:         "runtime configuration",
+			customConfig: []string{"maxmemory 1gb"},
+		},
+		{
+			name:             "configuration that needs a restart",
+			customConfig:     []string{"maxmemory 1gb", "databases 32", "io-threads 4"},
+			podAnnotations:   map[string]string{"foo": "bar"},
+			expectedConfig:   "databases 32\nio-threads 4\n",
+			expectedChecksum: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config string
+			var annotations map[string]string
+
+			rf := generateRF()
+			rf.Spec.Redis.CustomConfig = test.customConfig
+			rf.Spec.Redis.PodAnnotations = test.podAnnotations
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				config = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+			}).Return(nil)
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, kerrors.NewNotFound(schema.GroupResource{}, ""))
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				annotations = args.Get(1).(*appsv1.StatefulSet).Spec.Template.Annotations
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+
+			assert.NotContains(config, "maxmemory")
+			assert.True(strings.HasSuffix(config, "\n"+test.expectedConfig))
+			checksum, ok := annotations["redisfailovers.databases.spotahome.com/restart-config-checksum"]
+			assert.Equal(test.expectedChecksum, ok)
+			if test.expectedChecksum {
+				assert.Len(checksum, 64)
+				assert.Equal("bar", annotations["foo"])
+				assert.NotContains(rf.Spec.Redis.PodAnnotations, "redisfailovers.databases.spotahome.com/restart-config-checksum")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"sort"
 	"strconv"
@@ -14,16 +15,18 @@ import (
 
 // RedisFailoverHeal defines the interface able to fix the problems on the redis failovers
 type RedisFailoverHeal interface {
-	MakeMaster(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetOldestAsMaster(rFailover *redisfailoverv1.RedisFailover) error
-	SetMasterOnAll(masterIP string, rFailover *redisfailoverv1.RedisFailover) error
-	SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitor(ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
-	RestoreSentinel(ip string) error
-	SetSentinelCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetRedisCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
+	MakeMaster(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetOldestAsMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	SetMasterOnAll(ctx context.Context, masterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
+	RestoreSentinel(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	RemoveSentinelMonitor(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
 	DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error
+	BackgroundSave(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
 }
 
 // RedisFailoverHealer is our implementation of RedisFailoverCheck interface
@@ -61,14 +64,14 @@ func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(namespace string, pod v1.
 	return r.k8sService.UpdatePodLabels(namespace, pod.ObjectMeta.Name, generateRedisSlaveRoleLabel())
 }
 
-func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) MakeMaster(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	password, err := k8s.GetRedisPassword(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
-	err = r.redisClient.MakeMaster(ip, port, password)
+	port := getRedisPort(rf.RedisAnnouncePort())
+	err = r.redisClient.MakeMaster(failoverContext(ctx, rf), ip, port, password)
 	if err != nil {
 		return err
 	}
@@ -78,7 +81,7 @@ func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFai
 		return err
 	}
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == ip {
+		if getRedisAddress(rf, rp) == ip {
 			return r.setMasterLabelIfNecessary(rf.Namespace, rp)
 		}
 	}
@@ -86,7 +89,7 @@ func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFai
 }
 
 // SetOldestAsMaster puts all redis to the same master, choosen by order of appearance
-func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetOldestAsMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
@@ -105,15 +108,15 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 		return err
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
+	port := getRedisPort(rf.RedisAnnouncePort())
 	newMasterIP := ""
 	for _, pod := range ssp.Items {
 		if newMasterIP == "" {
-			newMasterIP = pod.Status.PodIP
+			newMasterIP = getRedisAddress(rf, pod)
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("New master is %s with ip %s", pod.Name, newMasterIP)
-			if err := r.redisClient.MakeMaster(newMasterIP, port, password); err != nil {
+			if err := r.redisClient.MakeMaster(failoverContext(ctx, rf), newMasterIP, port, password); err != nil {
 				newMasterIP = ""
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make new master failed, master ip: %s, error: %v", pod.Status.PodIP, err)
+				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make new master failed, master ip: %s, error: %v", getRedisAddress(rf, pod), err)
 				continue
 			}
 
@@ -122,11 +125,11 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 				return err
 			}
 
-			newMasterIP = pod.Status.PodIP
+			newMasterIP = getRedisAddress(rf, pod)
 		} else {
 			r.logger.Infof("Making pod %s slave of %s", pod.Name, newMasterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, newMasterIP, port, password); err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", pod.Status.PodIP, newMasterIP, err)
+			if err := r.redisClient.MakeSlaveOfWithPort(failoverContext(ctx, rf), getRedisAddress(rf, pod), newMasterIP, port, password); err != nil {
+				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", getRedisAddress(rf, pod), newMasterIP, err)
 			}
 
 			err = r.setSlaveLabelIfNecessary(rf.Namespace, pod)
@@ -143,7 +146,7 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 }
 
 // SetMasterOnAll puts all redis nodes as a slave of a given master
-func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetMasterOnAll(ctx context.Context, masterIP string, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
@@ -154,20 +157,21 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 		return err
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
+	port := getRedisPort(rf.RedisAnnouncePort())
 	for _, pod := range ssp.Items {
 		//During this configuration process if there is a new master selected , bailout
-		isMaster, err := r.redisClient.IsMaster(masterIP, port, password)
+		isMaster, err := r.redisClient.IsMaster(failoverContext(ctx, rf), masterIP, port, password)
 		if err != nil || !isMaster {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("check master failed maybe this node is not ready(ip changed), or sentinel made a switch: %s", masterIP)
 			return err
 		} else {
-			if pod.Status.PodIP == masterIP {
+			podAddress := getRedisAddress(rf, pod)
+			if podAddress == masterIP {
 				continue
 			}
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Making pod %s slave of %s", pod.Name, masterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, port, password); err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", pod.Status.PodIP, masterIP, err)
+			if err := r.redisClient.MakeSlaveOfWithPort(failoverContext(ctx, rf), podAddress, masterIP, port, password); err != nil {
+				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", podAddress, masterIP, err)
 				return err
 			}
 
@@ -182,7 +186,7 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 
 // SetExternalMasterOnAll puts all redis nodes as a slave of a given master outside of
 // the current RedisFailover instance
-func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetExternalMasterOnAll(ctx context.Context, masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
@@ -195,7 +199,7 @@ func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string
 
 	for _, pod := range ssp.Items {
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Making pod %s slave of %s:%s", pod.Name, masterIP, masterPort)
-		if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, masterPort, password); err != nil {
+		if err := r.redisClient.MakeSlaveOfWithPort(failoverContext(ctx, rf), getRedisAddress(rf, pod), masterIP, masterPort, password); err != nil {
 			return err
 		}
 
@@ -204,44 +208,57 @@ func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string
 }
 
 // NewSentinelMonitor changes the master that Sentinel has to monitor
-func (r *RedisFailoverHealer) NewSentinelMonitor(ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
-	quorum := strconv.Itoa(int(getQuorum(rf)))
+func (r *RedisFailoverHealer) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
+	quorum, err := getSentinelQuorum(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
 
 	password, err := k8s.GetRedisPassword(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, port, quorum, password)
+	port := getRedisPort(rf.RedisAnnouncePort())
+	return r.redisClient.MonitorRedisWithPort(failoverContext(ctx, rf), ip, monitor, port, strconv.Itoa(int(quorum)), password)
 }
 
 // NewSentinelMonitorWithPort changes the master that Sentinel has to monitor by the provided IP and Port
-func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
-	quorum := strconv.Itoa(int(getQuorum(rf)))
+func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
+	quorum, err := getSentinelQuorum(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
 
 	password, err := k8s.GetRedisPassword(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, monitorPort, quorum, password)
+	return r.redisClient.MonitorRedisWithPort(failoverContext(ctx, rf), ip, monitor, monitorPort, strconv.Itoa(int(quorum)), password)
 }
 
-// RestoreSentinel clear the number of sentinels on memory
-func (r *RedisFailoverHealer) RestoreSentinel(ip string) error {
+// RestoreSentinel clear the number of sentinels on memory for the master of the RedisFailover
+func (r *RedisFailoverHealer) RestoreSentinel(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.Debugf("Restoring sentinel %s", ip)
-	return r.redisClient.ResetSentinel(ip)
+	return r.redisClient.ResetSentinel(failoverContext(ctx, rf), ip)
+}
+
+// RemoveSentinelMonitor makes the sentinel stop monitoring the master of the RedisFailover
+func (r *RedisFailoverHealer) RemoveSentinelMonitor(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Removing the master from sentinel %s...", ip)
+	return r.redisClient.RemoveSentinelMonitor(failoverContext(ctx, rf), ip)
 }
 
 // SetSentinelCustomConfig will call sentinel to set the configuration given in config
-func (r *RedisFailoverHealer) SetSentinelCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetSentinelCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the custom config on sentinel %s...", ip)
-	return r.redisClient.SetCustomSentinelConfig(ip, rf.Spec.Sentinel.CustomConfig)
+	return r.redisClient.SetCustomSentinelConfig(failoverContext(ctx, rf), ip, rf.Spec.Sentinel.CustomConfig)
 }
 
-// SetRedisCustomConfig will call redis to set the configuration given in config
-func (r *RedisFailoverHealer) SetRedisCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
+// SetRedisCustomConfig will call redis to set the configuration given in config. The configuration that needs
+// a restart is left to the redis configuration file, the pods are restarted when it changes.
+func (r *RedisFailoverHealer) SetRedisCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the custom config on redis %s...", ip)
 
 	password, err := k8s.GetRedisPassword(r.k8sService, rf)
@@ -249,8 +266,9 @@ func (r *RedisFailoverHealer) SetRedisCustomConfig(ip string, rf *redisfailoverv
 		return err
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.SetCustomRedisConfig(ip, port, rf.Spec.Redis.CustomConfig, password)
+	port := getRedisPort(rf.RedisAnnouncePort())
+	runtimeConfigs, _ := splitRedisCustomConfig(rf.Spec.Redis.CustomConfig)
+	return r.redisClient.SetCustomRedisConfig(failoverContext(ctx, rf), ip, port, runtimeConfigs, password)
 }
 
 // DeletePod delete a failing pod so kubernetes relaunch it again
@@ -258,3 +276,16 @@ func (r *RedisFailoverHealer) DeletePod(podName string, rFailover *redisfailover
 	r.logger.WithField("redisfailover", rFailover.ObjectMeta.Name).WithField("namespace", rFailover.ObjectMeta.Namespace).Infof("Deleting pods %s...", podName)
 	return r.k8sService.DeletePod(rFailover.Namespace, podName)
 }
+
+// BackgroundSave saves the dataset of the redis to disk, waiting until the save is done
+func (r *RedisFailoverHealer) BackgroundSave(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Saving the dataset of redis %s...", ip)
+
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.RedisAnnouncePort())
+	return r.redisClient.BackgroundSave(failoverContext(ctx, rf), ip, port, password)
+}
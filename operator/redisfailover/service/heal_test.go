@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -36,11 +37,11 @@ func TestSetOldestAsMasterNewMasterError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -63,11 +64,11 @@ func TestSetOldestAsMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -95,12 +96,12 @@ func TestSetOldestAsMasterMultiplePodsMakeSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -128,15 +129,49 @@ func TestSetOldestAsMasterMultiplePods(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
+func TestSetOldestAsMasterMultiplePodsIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::a",
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::b",
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("MakeMaster", mock.Anything, "fd00:10:244::a", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "fd00:10:244::b", "fd00:10:244::a", "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
 func TestSetOldestAsMasterOrdering(t *testing.T) {
 	assert := assert.New(t)
 
@@ -171,12 +206,12 @@ func TestSetOldestAsMasterOrdering(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "1.1.1.1", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -204,10 +239,10 @@ func TestSetMasterOnAllMakeMasterError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(false, errors.New(""))
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -235,12 +270,12 @@ func TestSetMasterOnAllMakeSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -268,13 +303,90 @@ func TestSetMasterOnAll(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+}
+
+func TestSetMasterOnAllWithHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-1",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+				},
+			},
+		},
+	}
+
+	master := "rfr-test-0.rfr-test.testns.svc"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, master, "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "rfr-test-1.rfr-test.testns.svc", master, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SetMasterOnAll(context.TODO(), master, rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
+func TestSetMasterOnAllIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::a",
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "fd00:10:244::b",
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, "fd00:10:244::a", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "fd00:10:244::b", "fd00:10:244::a", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "fd00:10:244::a", rf)
 	assert.NoError(err)
+	mr.AssertExpectations(t)
 }
 
 func TestSetExternalMasterOnAll(t *testing.T) {
@@ -327,18 +439,18 @@ func TestSetExternalMasterOnAll(t *testing.T) {
 
 			mr := &mRedisService.Client{}
 			if !expectError {
-				mr.On("MakeSlaveOfWithPort", "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
+				mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
 				if test.errorOnMakeSlaveOf {
 					expectError = true
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
 				} else {
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
 				}
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.SetExternalMasterOnAll("5.5.5.5", "6379", rf)
+			err := healer.SetExternalMasterOnAll(context.TODO(), "5.5.5.5", "6379", rf)
 
 			if expectError {
 				assert.Error(err)
@@ -375,14 +487,14 @@ func TestNewSentinelMonitor(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(nil)
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitor("0.0.0.0", "1.1.1.1", rf)
+			err := healer.NewSentinelMonitor(context.TODO(), "0.0.0.0", "1.1.1.1", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -419,14 +531,14 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(nil)
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitorWithPort("0.0.0.0", "1.1.1.1", "6379", rf)
+			err := healer.NewSentinelMonitorWithPort(context.TODO(), "0.0.0.0", "1.1.1.1", "6379", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -438,3 +550,38 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRedisCustomConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.CustomConfig = []string{"maxmemory 1gb", "databases 32", "IO-THREADS 4", "appendonly yes"}
+
+	ms := &mK8SService.Services{}
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", []string{"maxmemory 1gb", "appendonly yes"}, "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SetRedisCustomConfig(context.TODO(), "0.0.0.0", rf)
+
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
+func TestBackgroundSaveIsCancelledWithTheHandling(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ms := &mK8SService.Services{}
+	mr := &mRedisService.Client{}
+	mr.On("BackgroundSave", mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() != nil }), "0.0.0.1", "0", "").Once().Return(context.Canceled)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.ErrorIs(healer.BackgroundSave(ctx, "0.0.0.1", rf), context.Canceled)
+	mr.AssertExpectations(t)
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	// exporterContainerPortName is the name of the port of the exporter containers
+	exporterContainerPortName = "metrics"
+	// masterChangedWindow is how long the change of master is alerted
+	masterChangedWindow = "5m"
+)
+
+// MonitoringNotInstalledError is returned by EnsureMonitoring when the prometheus-operator CRDs of some objects
+// are not installed. The rest of the objects are ensured anyway.
+type MonitoringNotInstalledError struct {
+	// Objects are the kind and name of the objects that couldn't be created
+	Objects []string
+}
+
+func (e *MonitoringNotInstalledError) Error() string {
+	return fmt.Sprintf("unable to create %s: the prometheus-operator CRDs are not installed", strings.Join(e.Objects, ", "))
+}
+
+// monitoringObject is an object of the prometheus-operator the operator manages for a RedisFailover
+type monitoringObject struct {
+	resource schema.GroupVersionResource
+	name     string
+}
+
+// EnsureMonitoring makes sure the prometheus-operator objects of the RedisFailover match its monitoring
+// settings: a monitor for every enabled exporter and the PrometheusRule with the default alerts. The
+// objects no longer needed, like the monitors of another kind, are deleted. A MonitoringNotInstalledError is
+// returned when the prometheus-operator CRDs are not installed.
+func (r *RedisFailoverKubeClient) EnsureMonitoring(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	desired := map[monitoringObject]*unstructured.Unstructured{}
+	for _, object := range generateMonitoringObjects(rf, labels, ownerRefs) {
+		desired[monitoringObject{resource: monitoringResource(object), name: object.GetName()}] = object
+	}
+
+	missing := []string{}
+	for _, managed := range managedMonitoringObjects(rf) {
+		object, ok := desired[managed]
+		if !ok {
+			if err := r.ensureNotPresentMonitoringObject(rf.Namespace, managed); err != nil {
+				return err
+			}
+			continue
+		}
+		err := r.K8SService.CreateOrUpdateMonitoringObject(managed.resource, rf.Namespace, object)
+		r.setEnsureOperationMetrics(rf.Namespace, object.GetName(), object.GetKind(), rf.Name, err)
+		if k8s.IsMonitoringNotInstalled(err) {
+			missing = append(missing, object.GetKind()+" "+object.GetName())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MonitoringNotInstalledError{Objects: missing}
+	}
+	return nil
+}
+
+// ensureNotPresentMonitoringObject deletes the object when it exists. An error getting it, like the one
+// returned when the prometheus-operator CRDs are not installed, is taken as not present.
+func (r *RedisFailoverKubeClient) ensureNotPresentMonitoringObject(namespace string, managed monitoringObject) error {
+	if _, err := r.K8SService.GetMonitoringObject(managed.resource, namespace, managed.name); err != nil {
+		return nil
+	}
+	err := r.K8SService.DeleteMonitoringObject(managed.resource, namespace, managed.name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// managedMonitoringObjects returns every object the operator could manage for the RedisFailover
+func managedMonitoringObjects(rf *redisfailoverv1.RedisFailover) []monitoringObject {
+	return []monitoringObject{
+		{resource: k8s.ServiceMonitorGroupVersionResource, name: GetRedisName(rf)},
+		{resource: k8s.ServiceMonitorGroupVersionResource, name: GetSentinelName(rf)},
+		{resource: k8s.PodMonitorGroupVersionResource, name: GetRedisName(rf)},
+		{resource: k8s.PodMonitorGroupVersionResource, name: GetSentinelName(rf)},
+		{resource: k8s.PrometheusRuleGroupVersionResource, name: GetPrometheusRuleName(rf)},
+	}
+}
+
+func monitoringResource(object *unstructured.Unstructured) schema.GroupVersionResource {
+	switch object.GetKind() {
+	case string(redisfailoverv1.MonitorKindPodMonitor):
+		return k8s.PodMonitorGroupVersionResource
+	case "PrometheusRule":
+		return k8s.PrometheusRuleGroupVersionResource
+	default:
+		return k8s.ServiceMonitorGroupVersionResource
+	}
+}
+
+// generateMonitoringObjects returns the prometheus-operator objects of the RedisFailover
+func generateMonitoringObjects(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) []*unstructured.Unstructured {
+	monitoring := rf.Spec.Monitoring
+	if !monitoring.Enabled {
+		return nil
+	}
+	labels = util.MergeLabels(labels, monitoring.Labels)
+
+	objects := []*unstructured.Unstructured{}
+	if rf.Spec.Redis.Exporter.Enabled {
+		objects = append(objects, generateMonitor(rf, GetRedisName(rf), redisRoleName, labels, ownerRefs))
+	}
+	if rf.DeploySentinels() && rf.Spec.Sentinel.Exporter.Enabled {
+		objects = append(objects, generateMonitor(rf, GetSentinelName(rf), sentinelRoleName, labels, ownerRefs))
+	}
+	if !monitoring.Rules.Disabled {
+		objects = append(objects, generatePrometheusRule(rf, labels, ownerRefs))
+	}
+	return objects
+}
+
+// sentinelServiceMonitored returns true when the sentinel exporter is scraped through the sentinel service
+func sentinelServiceMonitored(rf *redisfailoverv1.RedisFailover) bool {
+	return rf.Spec.Monitoring.Enabled && rf.Spec.Monitoring.MonitorKind == redisfailoverv1.MonitorKindServiceMonitor && rf.Spec.Sentinel.Exporter.Enabled
+}
+
+// generateMonitor returns the ServiceMonitor or the PodMonitor scraping the exporter of the component
+func generateMonitor(rf *redisfailoverv1.RedisFailover, name, component string, labels map[string]string, ownerRefs []metav1.OwnerReference) *unstructured.Unstructured {
+	selectorLabels := generateSelectorLabels(component, rf.Name)
+	kind := rf.Spec.Monitoring.MonitorKind
+	endpoint := map[string]interface{}{
+		"path": "/metrics",
+	}
+	if rf.Spec.Monitoring.Interval != "" {
+		endpoint["interval"] = rf.Spec.Monitoring.Interval
+	}
+
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": toUnstructuredMap(selectorLabels),
+		},
+	}
+	if kind == redisfailoverv1.MonitorKindPodMonitor {
+		endpoint["port"] = exporterContainerPortName
+		spec["podMetricsEndpoints"] = []interface{}{endpoint}
+	} else {
+		endpoint["port"] = exporterPortName
+		spec["endpoints"] = []interface{}{endpoint}
+	}
+
+	monitor := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	monitor.SetAPIVersion(k8s.ServiceMonitorGroupVersionResource.GroupVersion().String())
+	monitor.SetKind(string(kind))
+	monitor.SetNamespace(rf.Namespace)
+	monitor.SetName(name)
+	monitor.SetLabels(util.MergeLabels(labels, selectorLabels))
+	monitor.SetOwnerReferences(ownerRefs)
+	return monitor
+}
+
+// generatePrometheusRule returns the PrometheusRule with the default alerts of the RedisFailover, on the
+// metrics of the redis exporter
+func generatePrometheusRule(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *unstructured.Unstructured {
+	selector := fmt.Sprintf(`namespace=%q,pod=~%q`, rf.Namespace, GetRedisName(rf)+"-[0-9]+")
+	masterSelector := selector + `,role="master"`
+	rules := rf.Spec.Monitoring.Rules
+
+	alert := func(name, expr, duration, severity, summary string) interface{} {
+		alert := map[string]interface{}{
+			"alert": name,
+			"expr":  expr,
+			"labels": map[string]interface{}{
+				"severity":      severity,
+				"namespace":     rf.Namespace,
+				"redisfailover": rf.Name,
+			},
+			"annotations": map[string]interface{}{
+				"summary": summary,
+			},
+		}
+		if duration != "" {
+			alert["for"] = duration
+		}
+		return alert
+	}
+
+	alerts := []interface{}{}
+	// The redis of a bootstrapping RedisFailover are slaves of the bootstrap node
+	if !rf.Bootstrapping() {
+		alerts = append(alerts, alert("RedisFailoverNoMaster",
+			fmt.Sprintf(`count(redis_instance_info{%[1]s}) unless count(redis_instance_info{%[2]s})`, selector, masterSelector),
+			"1m", "critical",
+			fmt.Sprintf("RedisFailover %s/%s has no redis working as master", rf.Namespace, rf.Name)))
+	}
+	alerts = append(alerts,
+		alert("RedisFailoverReplicationLag",
+			fmt.Sprintf(`redis_master_repl_offset{%[1]s} - on(namespace, pod) group_right redis_connected_slave_offset_bytes{%[1]s} > %[2]d`, selector, rules.ReplicationLagBytes),
+			"5m", "warning",
+			fmt.Sprintf("A slave of RedisFailover %s/%s is more than %d bytes behind its master", rf.Namespace, rf.Name, rules.ReplicationLagBytes)),
+		alert("RedisFailoverMemoryNearMaxmemory",
+			fmt.Sprintf(`100 * redis_memory_used_bytes{%[1]s} / (redis_memory_max_bytes{%[1]s} > 0) > %[2]d`, selector, rules.MemoryUsagePercent),
+			"5m", "warning",
+			fmt.Sprintf("A redis of RedisFailover %s/%s uses more than %d%% of its maxmemory", rf.Namespace, rf.Name, rules.MemoryUsagePercent)),
+		alert("RedisFailoverMasterChanged",
+			fmt.Sprintf(`count(max by (pod) (redis_instance_info{%[1]s}) unless max by (pod) (redis_instance_info{%[1]s} offset %[2]s)) and on() count(redis_instance_info{%[1]s} offset %[2]s)`, masterSelector, masterChangedWindow),
+			"", "info",
+			fmt.Sprintf("The master of RedisFailover %s/%s has changed in the last %s", rf.Namespace, rf.Name, masterChangedWindow)),
+	)
+
+	rule := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name":  GetPrometheusRuleName(rf),
+						"rules": alerts,
+					},
+				},
+			},
+		},
+	}
+	rule.SetAPIVersion(k8s.PrometheusRuleGroupVersionResource.GroupVersion().String())
+	rule.SetKind("PrometheusRule")
+	rule.SetNamespace(rf.Namespace)
+	rule.SetName(GetPrometheusRuleName(rf))
+	rule.SetLabels(labels)
+	rule.SetOwnerReferences(ownerRefs)
+	return rule
+}
+
+// toUnstructuredMap converts the map to the types the unstructured objects are made of
+func toUnstructuredMap(m map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		converted[key] = value
+	}
+	return converted
+}
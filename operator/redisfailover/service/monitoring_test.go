@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestEnsureMonitoring(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dyncli := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		k8s.ServiceMonitorGroupVersionResource: "ServiceMonitorList",
+		k8s.PodMonitorGroupVersionResource:     "PodMonitorList",
+		k8s.PrometheusRuleGroupVersionResource: "PrometheusRuleList",
+	})
+	k8sService := k8s.New(kubernetes.NewSimpleClientset(), redisfailoverfake.NewSimpleClientset(), nil, dyncli, log.Dummy, metrics.Dummy)
+	client := rfservice.NewRedisFailoverKubeClient(k8sService, log.Dummy, metrics.Dummy)
+
+	rf := generateRF()
+	rf.Spec.Redis.Exporter.Enabled = true
+	rf.Spec.Sentinel.Exporter.Enabled = true
+	rf.Spec.Monitoring = redisfailoverv1.MonitoringSettings{Enabled: true, Interval: "15s", Labels: map[string]string{"release": "prometheus"}}
+	require.NoError(rf.Validate())
+
+	exists := func(resource schema.GroupVersionResource, name string) bool {
+		_, err := k8sService.GetMonitoringObject(resource, namespace, name)
+		return err == nil
+	}
+
+	// A ServiceMonitor for every exporter and the rule
+	require.NoError(client.EnsureMonitoring(rf, map[string]string{"app": "test"}, nil))
+	monitor, err := k8sService.GetMonitoringObject(k8s.ServiceMonitorGroupVersionResource, namespace, rfservice.GetRedisName(rf))
+	require.NoError(err)
+	assert.Equal("prometheus", monitor.GetLabels()["release"])
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	assert.Equal([]interface{}{map[string]interface{}{"port": "http-metrics", "path": "/metrics", "interval": "15s"}}, endpoints)
+	component, _, _ := unstructured.NestedString(monitor.Object, "spec", "selector", "matchLabels", "app.kubernetes.io/component")
+	assert.Equal("redis", component)
+	assert.True(exists(k8s.ServiceMonitorGroupVersionResource, rfservice.GetSentinelName(rf)))
+	rule, err := k8sService.GetMonitoringObject(k8s.PrometheusRuleGroupVersionResource, namespace, rfservice.GetPrometheusRuleName(rf))
+	require.NoError(err)
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	require.Len(groups, 1)
+	alerts := []string{}
+	for _, alert := range groups[0].(map[string]interface{})["rules"].([]interface{}) {
+		alerts = append(alerts, alert.(map[string]interface{})["alert"].(string))
+	}
+	assert.Equal([]string{"RedisFailoverNoMaster", "RedisFailoverReplicationLag", "RedisFailoverMemoryNearMaxmemory", "RedisFailoverMasterChanged"}, alerts)
+
+	// The ServiceMonitors are replaced by PodMonitors, and the rule removed
+	rf.Spec.Monitoring.MonitorKind = redisfailoverv1.MonitorKindPodMonitor
+	rf.Spec.Monitoring.Rules.Disabled = true
+	require.NoError(client.EnsureMonitoring(rf, nil, nil))
+	assert.False(exists(k8s.ServiceMonitorGroupVersionResource, rfservice.GetRedisName(rf)))
+	assert.False(exists(k8s.ServiceMonitorGroupVersionResource, rfservice.GetSentinelName(rf)))
+	assert.False(exists(k8s.PrometheusRuleGroupVersionResource, rfservice.GetPrometheusRuleName(rf)))
+	monitor, err = k8sService.GetMonitoringObject(k8s.PodMonitorGroupVersionResource, namespace, rfservice.GetRedisName(rf))
+	require.NoError(err)
+	port, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "podMetricsEndpoints")
+	assert.Equal("metrics", port[0].(map[string]interface{})["port"])
+
+	// Everything is removed once monitoring is disabled
+	rf.Spec.Monitoring.Enabled = false
+	require.NoError(client.EnsureMonitoring(rf, nil, nil))
+	assert.False(exists(k8s.PodMonitorGroupVersionResource, rfservice.GetRedisName(rf)))
+	assert.False(exists(k8s.PodMonitorGroupVersionResource, rfservice.GetSentinelName(rf)))
+}
+
+func TestEnsureMonitoringNotInstalled(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Exporter.Enabled = true
+	rf.Spec.Monitoring = redisfailoverv1.MonitoringSettings{Enabled: true, MonitorKind: redisfailoverv1.MonitorKindServiceMonitor, Rules: redisfailoverv1.MonitoringRules{Disabled: true}}
+
+	// The API server answers not found to every request of a resource it doesn't serve
+	notFound := errors.NewNotFound(schema.GroupResource{Group: "monitoring.coreos.com", Resource: "servicemonitors"}, "")
+	ms := &mK8SService.Services{}
+	ms.On("GetMonitoringObject", mock.Anything, namespace, mock.Anything).Return(nil, notFound)
+	ms.On("CreateOrUpdateMonitoringObject", k8s.ServiceMonitorGroupVersionResource, namespace, mock.Anything).Once().Return(notFound)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	err := client.EnsureMonitoring(rf, nil, nil)
+	var notInstalled *rfservice.MonitoringNotInstalledError
+	if assert.ErrorAs(err, &notInstalled) {
+		assert.Equal([]string{"ServiceMonitor rfr-test"}, notInstalled.Objects)
+	}
+	ms.AssertExpectations(t)
+
+	// Any other error still fails
+	ms = &mK8SService.Services{}
+	ms.On("GetMonitoringObject", mock.Anything, namespace, mock.Anything).Return(nil, notFound)
+	ms.On("CreateOrUpdateMonitoringObject", k8s.ServiceMonitorGroupVersionResource, namespace, mock.Anything).Once().Return(errors.NewForbidden(schema.GroupResource{}, "", nil))
+
+	client = rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	err = client.EnsureMonitoring(rf, nil, nil)
+	assert.True(errors.IsForbidden(err))
+}
@@ -29,11 +29,35 @@ func GetRedisReadinessName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisReadinessName, rf.Name)
 }
 
+// GetSentinelMasterName returns the name the sentinels know the redis master by
+func GetSentinelMasterName(rf *redisfailoverv1.RedisFailover) string {
+	if rf.Spec.Sentinel.MasterName != "" {
+		return rf.Spec.Sentinel.MasterName
+	}
+	return defaultSentinelMasterName
+}
+
 // GetSentinelName returns the name for sentinel resources
 func GetSentinelName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(sentinelName, rf.Name)
 }
 
+// GetSentinelPoolName returns the name of the sentinel resources of the pool monitoring the RedisFailover
+func GetSentinelPoolName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(sentinelName, rf.Spec.Sentinel.PoolRef.Name)
+}
+
+// GetConfigChecksumAnnotation returns the annotation of the pods with the checksum of the custom configuration
+// the RedisFailover applied to them
+func GetConfigChecksumAnnotation(rf *redisfailoverv1.RedisFailover) string {
+	return configChecksumAnnotationPrefix + rf.Name
+}
+
+// GetPrometheusRuleName returns the name of the PrometheusRule with the alerts of the RedisFailover
+func GetPrometheusRuleName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName("", rf.Name)
+}
+
 func GetRedisMasterName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisMasterName, rf.Name)
 }
@@ -42,6 +66,11 @@ func GetRedisSlaveName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisSlaveName, rf.Name)
 }
 
+// GetRedisPodHostname returns the DNS name of a redis pod, resolved through the redis headless service
+func GetRedisPodHostname(rf *redisfailoverv1.RedisFailover, podName string) string {
+	return fmt.Sprintf("%s.%s.%s.svc", podName, GetRedisName(rf), rf.Namespace)
+}
+
 func generateName(typeName, metaName string) string {
 	return fmt.Sprintf("%s%s-%s", baseName, typeName, metaName)
 }
@@ -0,0 +1,186 @@
+package service
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+)
+
+// sentinelPort is the port the sentinels listen on
+const sentinelPort = 26379
+
+// EnsureNetworkPolicies makes sure the NetworkPolicies of the redis and the sentinels allow only the
+// connections the RedisFailover needs, from the operator peer and from the clients of its settings. The
+// policies no longer needed are deleted.
+func (r *RedisFailoverKubeClient) EnsureNetworkPolicies(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) error {
+	var members []string
+	if rf.Spec.NetworkPolicy.Enabled && rf.DeploySentinels() {
+		var err error
+		if members, err = getSentinelPoolMembers(r.K8SService, rf); err != nil {
+			return err
+		}
+	}
+
+	desired := map[string]bool{}
+	for _, np := range generateNetworkPolicies(rf, members, labels, ownerRefs, operator) {
+		desired[np.Name] = true
+		err := r.K8SService.CreateOrUpdateNetworkPolicy(rf.Namespace, np)
+		r.setEnsureOperationMetrics(np.Namespace, np.Name, "NetworkPolicy", rf.Name, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range []string{GetRedisName(rf), GetSentinelName(rf)} {
+		if desired[name] {
+			continue
+		}
+		// If the policy exists (no get error), delete it
+		if _, err := r.K8SService.GetNetworkPolicy(rf.Namespace, name); err != nil {
+			continue
+		}
+		if err := r.K8SService.DeleteNetworkPolicy(rf.Namespace, name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateNetworkPolicies returns the NetworkPolicies of the redis and the deployed sentinels of the RedisFailover,
+// being members the RedisFailovers monitored by its sentinels as a pool
+func generateNetworkPolicies(rf *redisfailoverv1.RedisFailover, members []string, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) []*networkingv1.NetworkPolicy {
+	if !rf.Spec.NetworkPolicy.Enabled {
+		return nil
+	}
+	policies := []*networkingv1.NetworkPolicy{generateRedisNetworkPolicy(rf, labels, ownerRefs, operator)}
+	if rf.DeploySentinels() {
+		policies = append(policies, generateSentinelNetworkPolicy(rf, members, labels, ownerRefs, operator))
+	}
+	return policies
+}
+
+// generateRedisNetworkPolicy returns the NetworkPolicy of the redis pods. Their port is reached by the
+// other redis to replicate, by the sentinels monitoring them, by the operator and by the clients.
+func generateRedisNetworkPolicy(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) *networkingv1.NetworkPolicy {
+	peers := []networkingv1.NetworkPolicyPeer{podsPeer(generateSelectorLabels(redisRoleName, rf.Name))}
+	if rf.DeploySentinels() {
+		peers = append(peers, podsPeer(generateSelectorLabels(sentinelRoleName, rf.Name)))
+	}
+	if rf.PooledSentinels() {
+		peers = append(peers, podsPeer(generateSelectorLabels(sentinelRoleName, rf.Spec.Sentinel.PoolRef.Name)))
+	}
+
+	var metricsPort int32
+	if rf.Spec.Redis.Exporter.Enabled {
+		metricsPort = exporterPort
+	}
+	return generateComponentNetworkPolicy(rf, GetRedisName(rf), redisRoleName, rf.Spec.Redis.Port, metricsPort, peers, labels, ownerRefs, operator)
+}
+
+// generateSentinelNetworkPolicy returns the NetworkPolicy of the sentinel pods. Their port is reached by
+// the other sentinels to agree on the failovers, by the redis they monitor, whose shutdown asks them to fail
+// over, by the operator and by the clients.
+func generateSentinelNetworkPolicy(rf *redisfailoverv1.RedisFailover, members []string, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) *networkingv1.NetworkPolicy {
+	peers := []networkingv1.NetworkPolicyPeer{
+		podsPeer(generateSelectorLabels(sentinelRoleName, rf.Name)),
+		podsPeer(generateSelectorLabels(redisRoleName, rf.Name)),
+	}
+	for _, member := range members {
+		peers = append(peers, podsPeer(generateSelectorLabels(redisRoleName, member)))
+	}
+
+	var metricsPort int32
+	if rf.Spec.Sentinel.Exporter.Enabled {
+		metricsPort = sentinelExporterPort
+	}
+	return generateComponentNetworkPolicy(rf, GetSentinelName(rf), sentinelRoleName, sentinelPort, metricsPort, peers, labels, ownerRefs, operator)
+}
+
+// generateComponentNetworkPolicy returns the NetworkPolicy allowing the connections to the port of the
+// component from the given peers, the operator and the clients, and to its metrics port, when set, from
+// the scrapers. Any other ingress traffic to the pods of the component is denied.
+func generateComponentNetworkPolicy(rf *redisfailoverv1.RedisFailover, name, component string, port, metricsPort int32, peers []networkingv1.NetworkPolicyPeer, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) *networkingv1.NetworkPolicy {
+	selectorLabels := generateSelectorLabels(component, rf.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	peers = append(peers, operator)
+	peers = append(peers, toNetworkPolicyPeers(rf.Spec.NetworkPolicy.Clients)...)
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(port)},
+			From:  peers,
+		},
+	}
+	if metricsPort != 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(metricsPort)},
+			// Without peers, the exporter is reached from anywhere
+			From: toNetworkPolicyPeers(rf.Spec.NetworkPolicy.Scrapers),
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+}
+
+// OperatorNetworkPolicyPeer returns the peer selecting the operator pods: the ones with the given labels, every
+// pod when empty, in the given namespace, the one of the RedisFailover when empty.
+func OperatorNetworkPolicyPeer(namespace string, podLabels map[string]string) networkingv1.NetworkPolicyPeer {
+	peer := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: podLabels},
+	}
+	if namespace != "" {
+		peer.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": namespace},
+		}
+	}
+	return peer
+}
+
+// podsPeer returns the peer selecting the pods of the namespace of the RedisFailover with the given labels
+func podsPeer(podLabels map[string]string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: podLabels},
+	}
+}
+
+func toNetworkPolicyPeers(peers []redisfailoverv1.NetworkPolicyPeer) []networkingv1.NetworkPolicyPeer {
+	converted := make([]networkingv1.NetworkPolicyPeer, 0, len(peers))
+	for _, peer := range peers {
+		converted = append(converted, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: peer.NamespaceSelector.DeepCopy(),
+			PodSelector:       peer.PodSelector.DeepCopy(),
+		})
+	}
+	if len(converted) == 0 {
+		return nil
+	}
+	return converted
+}
+
+func networkPolicyPort(port int32) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	target := intstr.FromInt(int(port))
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &target,
+	}
+}
@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestEnsureNetworkPolicies(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rfcli := redisfailoverfake.NewSimpleClientset()
+	k8sService := k8s.New(kubernetes.NewSimpleClientset(), rfcli, nil, nil, log.Dummy, metrics.Dummy)
+	client := rfservice.NewRedisFailoverKubeClient(k8sService, log.Dummy, metrics.Dummy)
+
+	clients := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}
+	scrapers := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}}
+	rf := generateRF()
+	rf.Spec.Redis.Exporter.Enabled = true
+	rf.Spec.NetworkPolicy = redisfailoverv1.NetworkPolicySettings{
+		Enabled:  true,
+		Clients:  []redisfailoverv1.NetworkPolicyPeer{{PodSelector: clients}},
+		Scrapers: []redisfailoverv1.NetworkPolicyPeer{{NamespaceSelector: scrapers}},
+	}
+	require.NoError(rf.Validate())
+	operator := rfservice.OperatorNetworkPolicyPeer("operators", map[string]string{"app": "redisoperator"})
+
+	exists := func(name string) bool {
+		_, err := k8sService.GetNetworkPolicy(namespace, name)
+		return err == nil
+	}
+	peer := func(component, name string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+			"app.kubernetes.io/component": component,
+			"app.kubernetes.io/name":      name,
+			"app.kubernetes.io/part-of":   "redis-failover",
+		}}}
+	}
+	port := func(port int) []networkingv1.NetworkPolicyPort {
+		protocol := corev1.ProtocolTCP
+		target := intstr.FromInt(port)
+		return []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &target}}
+	}
+
+	// A policy for the redis and another one for the sentinels
+	require.NoError(client.EnsureNetworkPolicies(rf, map[string]string{"app": "test"}, nil, operator))
+	redisPolicy, err := k8sService.GetNetworkPolicy(namespace, rfservice.GetRedisName(rf))
+	require.NoError(err)
+	assert.Equal("test", redisPolicy.Labels["app"])
+	assert.Equal(peer("redis", name).PodSelector, &redisPolicy.Spec.PodSelector)
+	assert.Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, redisPolicy.Spec.PolicyTypes)
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: port(6379),
+			From: []networkingv1.NetworkPolicyPeer{
+				peer("redis", name),
+				peer("sentinel", name),
+				{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "operators"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redisoperator"}},
+				},
+				{PodSelector: clients},
+			},
+		},
+		{
+			Ports: port(9121),
+			From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: scrapers}},
+		},
+	}, redisPolicy.Spec.Ingress)
+
+	sentinelPolicy, err := k8sService.GetNetworkPolicy(namespace, rfservice.GetSentinelName(rf))
+	require.NoError(err)
+	require.Len(sentinelPolicy.Spec.Ingress, 1)
+	assert.Equal(port(26379), sentinelPolicy.Spec.Ingress[0].Ports)
+	// The redis reach the sentinels to ask for a failover on shutdown
+	assert.Equal([]networkingv1.NetworkPolicyPeer{peer("sentinel", name), peer("redis", name)}, sentinelPolicy.Spec.Ingress[0].From[:2])
+
+	// The sentinels of a pool are also reached by the redis of its members
+	for _, member := range []string{"member-b", "member-a"} {
+		pooled := generateRF()
+		pooled.Name = member
+		pooled.Spec.Sentinel.PoolRef = &redisfailoverv1.SentinelPoolReference{Name: name}
+		_, err := rfcli.DatabasesV1().RedisFailovers(namespace).Create(context.Background(), pooled, metav1.CreateOptions{})
+		require.NoError(err)
+	}
+	require.NoError(client.EnsureNetworkPolicies(rf, nil, nil, operator))
+	sentinelPolicy, err = k8sService.GetNetworkPolicy(namespace, rfservice.GetSentinelName(rf))
+	require.NoError(err)
+	assert.Equal(port(26379), sentinelPolicy.Spec.Ingress[0].Ports)
+	assert.Equal([]networkingv1.NetworkPolicyPeer{peer("sentinel", name), peer("redis", name), peer("redis", "member-a"), peer("redis", "member-b")}, sentinelPolicy.Spec.Ingress[0].From[:4])
+
+	// The redis monitored by a sentinel pool are reached by the sentinels of the pool, and its own are not deployed
+	rf.Spec.Sentinel.PoolRef = &redisfailoverv1.SentinelPoolReference{Name: "pool"}
+	rf.Spec.Sentinel.MasterName = "pooled"
+	require.NoError(rf.Validate())
+	require.NoError(client.EnsureNetworkPolicies(rf, nil, nil, operator))
+	redisPolicy, err = k8sService.GetNetworkPolicy(namespace, rfservice.GetRedisName(rf))
+	require.NoError(err)
+	assert.Equal([]networkingv1.NetworkPolicyPeer{peer("redis", name), peer("sentinel", "pool")}, redisPolicy.Spec.Ingress[0].From[:2])
+	assert.False(exists(rfservice.GetSentinelName(rf)))
+
+	// Everything is removed once the network policy is disabled
+	rf.Spec.NetworkPolicy.Enabled = false
+	require.NoError(client.EnsureNetworkPolicies(rf, nil, nil, operator))
+	assert.False(exists(rfservice.GetRedisName(rf)))
+}
+
+func TestOperatorNetworkPolicyPeer(t *testing.T) {
+	assert := assert.New(t)
+
+	// Every pod of the namespace of the RedisFailover
+	assert.Equal(networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}, rfservice.OperatorNetworkPolicyPeer("", nil))
+
+	assert.Equal(networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "operators"}},
+		PodSelector:       &metav1.LabelSelector{},
+	}, rfservice.OperatorNetworkPolicyPeer("operators", nil))
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// getSentinelPool returns the RedisFailover whose sentinels monitor the redis of the given one, with its
+// defaults set
+func getSentinelPool(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	name := rf.Spec.Sentinel.PoolRef.Name
+	pool, err := k8sService.GetRedisFailover(context.Background(), rf.Namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get sentinel pool %s: %w", name, err)
+	}
+	pool = pool.DeepCopy()
+	if err := pool.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sentinel pool %s: %w", name, err)
+	}
+	if !pool.DeploySentinels() {
+		return nil, fmt.Errorf("sentinel pool %s doesn't deploy sentinels", name)
+	}
+	if pool.Spec.Sentinel.MasterName == rf.Spec.Sentinel.MasterName {
+		return nil, fmt.Errorf("sentinel pool %s already monitors a master named %s", name, rf.Spec.Sentinel.MasterName)
+	}
+	return pool, nil
+}
+
+// checkSentinelPoolMasterName controls that no other RedisFailover of the pool uses the same master name,
+// as the sentinels would mix up both masters
+func checkSentinelPoolMasterName(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) error {
+	rfs, err := k8sService.ListRedisFailovers(context.Background(), rf.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, other := range rfs.Items {
+		if other.Name == rf.Name || other.Spec.Sentinel.PoolRef == nil {
+			continue
+		}
+		if other.Spec.Sentinel.PoolRef.Name == rf.Spec.Sentinel.PoolRef.Name && other.Spec.Sentinel.MasterName == rf.Spec.Sentinel.MasterName {
+			return fmt.Errorf("master name %s is already used by %s in sentinel pool %s", rf.Spec.Sentinel.MasterName, other.Name, rf.Spec.Sentinel.PoolRef.Name)
+		}
+	}
+	return nil
+}
+
+// getSentinelPoolMembers returns the names of the RedisFailovers monitored by the sentinels of the given one
+func getSentinelPoolMembers(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	rfs, err := k8sService.ListRedisFailovers(context.Background(), rf.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	members := []string{}
+	for _, other := range rfs.Items {
+		if other.Name != rf.Name && other.Spec.Sentinel.PoolRef != nil && other.Spec.Sentinel.PoolRef.Name == rf.Name {
+			members = append(members, other.Name)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// getSentinelReplicas returns the number of sentinels monitoring the redis of the RedisFailover
+func getSentinelReplicas(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) (int32, error) {
+	switch {
+	case rf.PooledSentinels():
+		pool, err := getSentinelPool(k8sService, rf)
+		if err != nil {
+			return 0, err
+		}
+		return pool.Spec.Sentinel.Replicas, nil
+	case rf.SharedSentinels():
+		return int32(len(rf.Spec.Sentinel.SharedGroup.Addresses)), nil
+	default:
+		return rf.Spec.Sentinel.Replicas, nil
+	}
+}
+
+// getSentinelQuorum returns the quorum of the sentinels monitoring the redis of the RedisFailover, that
+// is the one of the pool when its sentinels are pooled
+func getSentinelQuorum(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) (int32, error) {
+	if rf.PooledSentinels() {
+		pool, err := getSentinelPool(k8sService, rf)
+		if err != nil {
+			return 0, err
+		}
+		return getQuorum(pool), nil
+	}
+	return getQuorum(rf), nil
+}
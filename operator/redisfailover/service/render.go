@@ -0,0 +1,59 @@
+package service
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+)
+
+// Render returns the objects generated for the RedisFailover, in the order they are ensured, without a
+// cluster. What is read from the cluster when they are ensured is not resolved: the redis configuration
+// is rendered without the password of the auth secret, a custom shutdown configmap is not rendered and
+// the RedisFailoverSnapshot data sources are kept as they are in the spec.
+func Render(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, operator networkingv1.NetworkPolicyPeer) []runtime.Object {
+	objects := []runtime.Object{}
+	if rf.DeployRedisService() {
+		objects = append(objects, generateRedisService(rf, labels, ownerRefs))
+	}
+
+	deploySentinels := rf.DeploySentinels()
+	if deploySentinels {
+		objects = append(objects,
+			generateSentinelService(rf, labels, ownerRefs),
+			generateSentinelConfigMap(rf, labels, ownerRefs),
+		)
+	}
+
+	objects = append(objects,
+		generateRedisMasterService(rf, labels, ownerRefs),
+		generateRedisSlaveService(rf, labels, ownerRefs),
+	)
+	if rf.Spec.Redis.ShutdownConfigMap == "" {
+		objects = append(objects, generateRedisShutdownConfigMap(rf, labels, ownerRefs))
+	}
+	objects = append(objects,
+		generateRedisReadinessConfigMap(rf, labels, ownerRefs),
+		generateRedisConfigMap(rf, labels, ownerRefs, ""),
+	)
+	for _, np := range generateNetworkPolicies(rf, nil, labels, ownerRefs, operator) {
+		objects = append(objects, np)
+	}
+	if !rf.Spec.Redis.DisablePodDisruptionBudget {
+		objects = append(objects, generateComponentPodDisruptionBudget(rf, redisName, redisRoleName, labels, ownerRefs))
+	}
+	objects = append(objects, generateRedisStatefulSet(rf, labels, ownerRefs))
+
+	if deploySentinels {
+		if !rf.Spec.Sentinel.DisablePodDisruptionBudget {
+			objects = append(objects, generateComponentPodDisruptionBudget(rf, sentinelName, sentinelRoleName, labels, ownerRefs))
+		}
+		objects = append(objects, generateSentinelDeployment(rf, labels, ownerRefs))
+	}
+
+	for _, object := range generateMonitoringObjects(rf, labels, ownerRefs) {
+		objects = append(objects, object)
+	}
+	return objects
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// maxConcurrentNodeChecks is the maximum number of nodes queried at the same time while taking a snapshot
+const maxConcurrentNodeChecks = 10
+
+// RedisNodeState is the state of a redis node when the snapshot was taken
+type RedisNodeState struct {
+	PodName      string
+	PodUID       string
+	Address      string
+	RevisionHash string
+	RoleLabel    string
+	IsMaster     bool
+	SlaveOf      string
+	SlaveReady   bool
+	// ReplOffset is the offset of the replication stream the node has got to
+	ReplOffset int64
+	// Uptime is only known for the nodes working as master
+	Uptime time.Duration
+	Keys   int64
+	// ConfigChecksum is the checksum of the custom configuration last applied to the node
+	ConfigChecksum string
+	// Err is set when the node could not be queried, the rest of the state is unknown then
+	Err error
+}
+
+// SentinelNodeState is the state of a sentinel node when the snapshot was taken
+type SentinelNodeState struct {
+	PodName              string
+	PodUID               string
+	Address              string
+	ConfigChecksum       string
+	MonitorIP            string
+	MonitorPort          string
+	MonitorErr           error
+	SentinelsInMemory    int32
+	SentinelsInMemoryErr error
+	SlavesInMemory       int32
+	SlavesInMemoryErr    error
+}
+
+// RedisFailoverSnapshot is the state of every running redis and sentinel of a RedisFailover,
+// collected at once so the checks can be done against it
+type RedisFailoverSnapshot struct {
+	Redises   []RedisNodeState
+	Sentinels []SentinelNodeState
+	// SentinelReplicas is the number of sentinels expected to monitor the master
+	SentinelReplicas int32
+}
+
+// GetSnapshot queries concurrently every running redis and sentinel of the RedisFailover
+func (r *RedisFailoverChecker) GetSnapshot(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*RedisFailoverSnapshot, error) {
+	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &RedisFailoverSnapshot{}
+	if rf.SentinelsAllowed() {
+		if rf.PooledSentinels() {
+			if err := checkSentinelPoolMasterName(r.k8sService, rf); err != nil {
+				return nil, err
+			}
+		}
+		snapshot.SentinelReplicas, err = getSentinelReplicas(r.k8sService, rf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, rp := range rps.Items {
+		if isPodActive(rp) {
+			snapshot.Redises = append(snapshot.Redises, RedisNodeState{
+				PodName:      rp.Name,
+				PodUID:       string(rp.UID),
+				Address:      getRedisAddress(rf, rp),
+				RevisionHash: rp.Labels[appsv1.ControllerRevisionHashLabelKey],
+				RoleLabel:    rp.Labels[redisRoleLabelKey],
+				// The checksum is kept on the pod, so it is still known after the operator restarts
+				ConfigChecksum: rp.Annotations[GetConfigChecksumAnnotation(rf)],
+			})
+		}
+	}
+
+	if rf.SharedSentinels() && !rf.PooledSentinels() {
+		for _, address := range rf.Spec.Sentinel.SharedGroup.Addresses {
+			snapshot.Sentinels = append(snapshot.Sentinels, SentinelNodeState{
+				Address: address,
+			})
+		}
+	} else if rf.SentinelsAllowed() {
+		sps, err := r.k8sService.GetDeploymentPods(rf.Namespace, getSentinelDeploymentName(rf))
+		if err != nil {
+			return nil, err
+		}
+		for _, sp := range sps.Items {
+			if isPodActive(sp) {
+				snapshot.Sentinels = append(snapshot.Sentinels, SentinelNodeState{
+					PodName:        sp.Name,
+					PodUID:         string(sp.UID),
+					Address:        sp.Status.PodIP,
+					ConfigChecksum: sp.Annotations[GetConfigChecksumAnnotation(rf)],
+				})
+			}
+		}
+	}
+
+	ctx = failoverContext(ctx, rf)
+	port := getRedisPort(rf.RedisAnnouncePort())
+	nRedises := len(snapshot.Redises)
+	util.RunConcurrently(nRedises+len(snapshot.Sentinels), maxConcurrentNodeChecks, func(i int) {
+		if i < nRedises {
+			node := &snapshot.Redises[i]
+			redisInfo, err := r.redisClient.GetInfo(ctx, node.Address, port, password)
+			if err != nil {
+				node.Err = err
+				return
+			}
+			node.IsMaster = redisInfo.Replication.IsMaster()
+			if node.IsMaster {
+				node.Uptime = redisInfo.Server.Uptime()
+				node.ReplOffset = redisInfo.Replication.MasterReplOffset
+			} else {
+				node.SlaveOf = redisInfo.Replication.SlaveOf()
+				node.SlaveReady = redisInfo.Replication.SlaveReady()
+				node.ReplOffset = redisInfo.Replication.SlaveReplOffset
+			}
+			node.Keys = redisInfo.Keyspace.TotalKeys()
+			return
+		}
+		node := &snapshot.Sentinels[i-nRedises]
+		node.MonitorIP, node.MonitorPort, node.MonitorErr = r.redisClient.GetSentinelMonitor(ctx, node.Address)
+		// A single INFO of the sentinel gives both the sentinels and the slaves it knows about
+		master, err := r.redisClient.GetSentinelMaster(ctx, node.Address)
+		node.SentinelsInMemory, node.SentinelsInMemoryErr = master.Sentinels, err
+		node.SlavesInMemory, node.SlavesInMemoryErr = master.Slaves, err
+	})
+
+	for _, node := range snapshot.Redises {
+		if node.Err != nil {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Get redis info failed, maybe this node is not ready, pod %s: %v", node.PodName, node.Err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// UpdateRoleLabels sets the role label of the redis pods of the snapshot, being master the one with the given address
+func (r *RedisFailoverChecker) UpdateRoleLabels(rf *redisfailoverv1.RedisFailover, snapshot *RedisFailoverSnapshot, master string) error {
+	for _, node := range snapshot.Redises {
+		role, labels := redisRoleLabelSlave, generateRedisSlaveRoleLabel()
+		if node.Address == master {
+			role, labels = redisRoleLabelMaster, generateRedisMasterRoleLabel()
+		}
+		if node.RoleLabel == role {
+			continue
+		}
+		if err := r.k8sService.UpdatePodLabels(rf.Namespace, node.PodName, labels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NumberMasters returns the number of redis nodes that are working as a master
+func (s *RedisFailoverSnapshot) NumberMasters() int {
+	nMasters := 0
+	for _, node := range s.Redises {
+		if node.Err == nil && node.IsMaster {
+			nMasters++
+		}
+	}
+	return nMasters
+}
+
+// Master returns the redis node working as master
+func (s *RedisFailoverSnapshot) Master() (RedisNodeState, error) {
+	masters := []RedisNodeState{}
+	for _, node := range s.Redises {
+		if node.Err == nil && node.IsMaster {
+			masters = append(masters, node)
+		}
+	}
+	if len(masters) != 1 {
+		return RedisNodeState{}, errors.New("number of redis nodes known as master is different than 1")
+	}
+	return masters[0], nil
+}
+
+// MasterAddress returns the address of the redis node working as master
+func (s *RedisFailoverSnapshot) MasterAddress() (string, error) {
+	master, err := s.Master()
+	if err != nil {
+		return "", err
+	}
+	return master.Address, nil
+}
+
+// ReplicationLags returns the bytes of the replication stream every known slave of the master is behind it,
+// by the name of their pods
+func (s *RedisFailoverSnapshot) ReplicationLags() map[string]int64 {
+	master, err := s.Master()
+	if err != nil {
+		return nil
+	}
+	lags := map[string]int64{}
+	for _, node := range s.Redises {
+		if node.Err != nil || node.IsMaster || node.SlaveOf != master.Address {
+			continue
+		}
+		// The offsets are read at different moments, a slave can get ahead of the master read before it
+		lag := master.ReplOffset - node.ReplOffset
+		if lag < 0 {
+			lag = 0
+		}
+		lags[node.PodName] = lag
+	}
+	return lags
+}
+
+// EmptyRestartedMaster returns the master when it has restarted recently without any data while some slave
+// still keeps it, along with the slave with most keys. Those slaves would lose their data once they sync
+// with the master.
+func (s *RedisFailoverSnapshot) EmptyRestartedMaster(maxUptime time.Duration) (master RedisNodeState, candidate RedisNodeState, found bool) {
+	master, err := s.Master()
+	if err != nil || master.Uptime >= maxUptime || master.Keys > 0 {
+		return RedisNodeState{}, RedisNodeState{}, false
+	}
+	for _, node := range s.Redises {
+		if node.Err != nil || node.IsMaster || node.Keys <= candidate.Keys {
+			continue
+		}
+		candidate = node
+	}
+	if candidate.Keys == 0 {
+		return RedisNodeState{}, RedisNodeState{}, false
+	}
+	return master, candidate, true
+}
+
+// CheckAllSlavesFromMaster controls that all slaves have the given master
+func (s *RedisFailoverSnapshot) CheckAllSlavesFromMaster(master string) error {
+	for _, node := range s.Redises {
+		if node.Err != nil {
+			return fmt.Errorf("state of redis %s unknown: %w", node.Address, node.Err)
+		}
+		if node.SlaveOf != "" && node.SlaveOf != master {
+			return fmt.Errorf("slave %s don't have the master %s, has %s", node.Address, master, node.SlaveOf)
+		}
+	}
+	return nil
+}
+
+// CheckIfMasterLocalhost returns true if all the redis nodes have localhost as master, which
+// happens on the fresh boot of all the redis pods
+func (s *RedisFailoverSnapshot) CheckIfMasterLocalhost() (bool, error) {
+	if len(s.Redises) == 0 {
+		return false, errors.New("unable to fetch any redis Ips Currently")
+	}
+	for _, node := range s.Redises {
+		if node.Err != nil {
+			return false, node.Err
+		}
+		if node.SlaveOf == "" {
+			return false, errors.New("unexpected master state, fix manually")
+		}
+		if node.SlaveOf != "127.0.0.1" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// CheckMonitor controls if the sentinel is monitoring the expected master
+func (s SentinelNodeState) CheckMonitor(monitorIP, monitorPort string) error {
+	if s.MonitorErr != nil {
+		return s.MonitorErr
+	}
+	if s.MonitorIP != monitorIP || (monitorPort != "" && monitorPort != s.MonitorPort) {
+		return fmt.Errorf("sentinel monitoring %s:%s instead %s:%s", s.MonitorIP, s.MonitorPort, monitorIP, monitorPort)
+	}
+	return nil
+}
+
+// CheckSentinelsInMemory controls that the sentinel has only the expected living sentinels on its memory
+func (s SentinelNodeState) CheckSentinelsInMemory(replicas int32) error {
+	if s.SentinelsInMemoryErr != nil {
+		return s.SentinelsInMemoryErr
+	}
+	if s.SentinelsInMemory != replicas {
+		return errors.New("sentinels in memory mismatch")
+	}
+	return nil
+}
+
+// CheckSlavesInMemory controls that the sentinel has only the expected slaves number
+func (s SentinelNodeState) CheckSlavesInMemory(rf *redisfailoverv1.RedisFailover) error {
+	if s.SlavesInMemoryErr != nil {
+		return s.SlavesInMemoryErr
+	}
+	expected := rf.Spec.Redis.Replicas - 1
+	if rf.Bootstrapping() {
+		expected = rf.Spec.Redis.Replicas
+	}
+	if s.SlavesInMemory != expected {
+		return errors.New("redis slaves in sentinel memory mismatch")
+	}
+	return nil
+}
+
+func isPodActive(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil
+}
@@ -0,0 +1,456 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/redis/info"
+)
+
+func TestGetSnapshot(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	redisPods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-0",
+					Labels: map[string]string{
+						appsv1.ControllerRevisionHashLabelKey: "1",
+						"redisfailovers-role":                 "master",
+					},
+					Annotations: map[string]string{
+						"config-checksum.redisfailovers.databases.spotahome.com/test": "abc",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-1",
+					Labels: map[string]string{
+						appsv1.ControllerRevisionHashLabelKey: "2",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-2",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "2.2.2.2",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-3",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "3.3.3.3",
+					Phase: corev1.PodPending,
+				},
+			},
+		},
+	}
+	sentinelPods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfs-0",
+					Annotations: map[string]string{
+						"config-checksum.redisfailovers.databases.spotahome.com/test":  "def",
+						"config-checksum.redisfailovers.databases.spotahome.com/other": "ghi",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: "4.4.4.4",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(redisPods, nil)
+	ms.On("GetDeploymentPods", namespace, rfservice.GetSentinelName(rf)).Once().Return(sentinelPods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetInfo", mock.Anything, "0.0.0.0", "0", "").Once().Return(&info.Info{
+		Server:      info.Server{UptimeInSeconds: 3600},
+		Replication: info.Replication{Role: "master", MasterReplOffset: 1500},
+		Keyspace:    info.Keyspace{"db0": {Keys: 10}},
+	}, nil)
+	mr.On("GetInfo", mock.Anything, "1.1.1.1", "0", "").Once().Return(&info.Info{
+		Server:      info.Server{UptimeInSeconds: 60},
+		Replication: info.Replication{Role: "slave", MasterHost: "0.0.0.0", MasterLinkStatus: "up", SlaveReplOffset: 1400},
+		Keyspace:    info.Keyspace{"db0": {Keys: 9}},
+	}, nil)
+	mr.On("GetInfo", mock.Anything, "2.2.2.2", "0", "").Once().Return(nil, errors.New(""))
+	mr.On("GetSentinelMonitor", mock.Anything, "4.4.4.4").Once().Return("0.0.0.0", "0", nil)
+	mr.On("GetSentinelMaster", mock.Anything, "4.4.4.4").Once().Return(info.SentinelMaster{Sentinels: 3, Slaves: 2}, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	snapshot, err := checker.GetSnapshot(context.TODO(), rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+
+	expected := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "rfr-0", Address: "0.0.0.0", RevisionHash: "1", RoleLabel: "master", IsMaster: true, ReplOffset: 1500, Uptime: time.Hour, Keys: 10, ConfigChecksum: "abc"},
+			{PodName: "rfr-1", Address: "1.1.1.1", RevisionHash: "2", SlaveOf: "0.0.0.0", SlaveReady: true, ReplOffset: 1400, Keys: 9},
+			{PodName: "rfr-2", Address: "2.2.2.2", Err: errors.New("")},
+		},
+		Sentinels: []rfservice.SentinelNodeState{
+			{PodName: "rfs-0", Address: "4.4.4.4", ConfigChecksum: "def", MonitorIP: "0.0.0.0", MonitorPort: "0", SentinelsInMemory: 3, SlavesInMemory: 2},
+		},
+		SentinelReplicas: rf.Spec.Sentinel.Replicas,
+	}
+	assert.Equal(expected, snapshot)
+}
+
+func TestGetSnapshotSharedSentinels(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.MasterName = "cache"
+	rf.Spec.Sentinel.SharedGroup = &redisfailoverv1.SharedSentinelGroup{Addresses: []string{"sentinel-0.shared"}, Quorum: 1}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(&corev1.PodList{}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSentinelMonitor", mock.Anything, "sentinel-0.shared").Once().Return("0.0.0.0", "0", nil)
+	mr.On("GetSentinelMaster", mock.Anything, "sentinel-0.shared").Once().Return(info.SentinelMaster{Sentinels: 5, Slaves: 2}, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	snapshot, err := checker.GetSnapshot(context.TODO(), rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+
+	expected := &rfservice.RedisFailoverSnapshot{
+		Sentinels: []rfservice.SentinelNodeState{
+			{Address: "sentinel-0.shared", MonitorIP: "0.0.0.0", MonitorPort: "0", SentinelsInMemory: 5, SlavesInMemory: 2},
+		},
+		SentinelReplicas: 1,
+	}
+	assert.Equal(expected, snapshot)
+}
+
+func TestGetSnapshotPooledSentinels(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.MasterName = "cache"
+	rf.Spec.Sentinel.PoolRef = &redisfailoverv1.SentinelPoolReference{Name: "pool"}
+	pool := generateRF()
+	pool.Name = "pool"
+	pool.Spec.Sentinel.Replicas = 5
+	other := generateRF()
+	other.Name = "other"
+	other.Spec.Sentinel.MasterName = "sessions"
+	other.Spec.Sentinel.PoolRef = &redisfailoverv1.SentinelPoolReference{Name: "pool"}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(&corev1.PodList{}, nil)
+	ms.On("ListRedisFailovers", mock.Anything, namespace, mock.Anything).Once().Return(&redisfailoverv1.RedisFailoverList{
+		Items: []redisfailoverv1.RedisFailover{*pool, *rf, *other},
+	}, nil)
+	ms.On("GetRedisFailover", mock.Anything, namespace, "pool").Once().Return(pool, nil)
+	ms.On("GetDeploymentPods", namespace, "rfs-pool").Once().Return(&corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{UID: "1"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "4.4.4.4"},
+			},
+		},
+	}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSentinelMonitor", mock.Anything, "4.4.4.4").Once().Return("0.0.0.0", "0", nil)
+	mr.On("GetSentinelMaster", mock.Anything, "4.4.4.4").Once().Return(info.SentinelMaster{Sentinels: 5, Slaves: 2}, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	snapshot, err := checker.GetSnapshot(context.TODO(), rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+
+	expected := &rfservice.RedisFailoverSnapshot{
+		Sentinels: []rfservice.SentinelNodeState{
+			{PodUID: "1", Address: "4.4.4.4", MonitorIP: "0.0.0.0", MonitorPort: "0", SentinelsInMemory: 5, SlavesInMemory: 2},
+		},
+		SentinelReplicas: 5,
+	}
+	assert.Equal(expected, snapshot)
+}
+
+func TestGetSnapshotPooledSentinelsMasterNameTaken(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.MasterName = "cache"
+	rf.Spec.Sentinel.PoolRef = &redisfailoverv1.SentinelPoolReference{Name: "pool"}
+	other := rf.DeepCopy()
+	other.Name = "other"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(&corev1.PodList{}, nil)
+	ms.On("ListRedisFailovers", mock.Anything, namespace, mock.Anything).Once().Return(&redisfailoverv1.RedisFailoverList{
+		Items: []redisfailoverv1.RedisFailover{*rf, *other},
+	}, nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	_, err := checker.GetSnapshot(context.TODO(), rf)
+	assert.EqualError(err, "master name cache is already used by other in sentinel pool pool")
+}
+
+func TestSnapshotMaster(t *testing.T) {
+	tests := []struct {
+		name      string
+		redises   []rfservice.RedisNodeState
+		expMaster string
+		expErr    bool
+	}{
+		{
+			name: "one master",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true},
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0"},
+			},
+			expMaster: "0.0.0.0",
+		},
+		{
+			name: "no master",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", SlaveOf: "1.1.1.1"},
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0"},
+			},
+			expErr: true,
+		},
+		{
+			name: "multiple masters",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true},
+				{Address: "1.1.1.1", IsMaster: true},
+			},
+			expErr: true,
+		},
+		{
+			name: "unknown nodes are ignored",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true},
+				{Address: "1.1.1.1", Err: errors.New("")},
+			},
+			expMaster: "0.0.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			snapshot := &rfservice.RedisFailoverSnapshot{Redises: test.redises}
+			master, err := snapshot.MasterAddress()
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expMaster, master)
+				assert.Equal(1, snapshot.NumberMasters())
+			}
+		})
+	}
+}
+
+func TestSnapshotEmptyRestartedMaster(t *testing.T) {
+	tests := []struct {
+		name         string
+		redises      []rfservice.RedisNodeState
+		expFound     bool
+		expCandidate string
+	}{
+		{
+			name: "master with data",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true, Uptime: time.Second, Keys: 10},
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0", Keys: 10},
+			},
+		},
+		{
+			name: "empty master running for long",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true, Uptime: time.Hour},
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0", Keys: 10},
+			},
+		},
+		{
+			name: "empty master and slaves",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true, Uptime: time.Second},
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0"},
+			},
+		},
+		{
+			name: "restarted empty master with slaves keeping the data",
+			redises: []rfservice.RedisNodeState{
+				{Address: "0.0.0.0", IsMaster: true, Uptime: time.Second},
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0", Keys: 5},
+				{Address: "2.2.2.2", SlaveOf: "0.0.0.0", Keys: 10},
+				{Address: "3.3.3.3", Err: errors.New("")},
+			},
+			expFound:     true,
+			expCandidate: "2.2.2.2",
+		},
+		{
+			name: "no master",
+			redises: []rfservice.RedisNodeState{
+				{Address: "1.1.1.1", SlaveOf: "0.0.0.0", Keys: 10},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			snapshot := &rfservice.RedisFailoverSnapshot{Redises: test.redises}
+			master, candidate, found := snapshot.EmptyRestartedMaster(time.Minute)
+			assert.Equal(test.expFound, found)
+			if test.expFound {
+				assert.Equal("0.0.0.0", master.Address)
+				assert.Equal(test.expCandidate, candidate.Address)
+			}
+		})
+	}
+}
+
+func TestSnapshotReplicationLags(t *testing.T) {
+	assert := assert.New(t)
+
+	snapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "rfr-0", Address: "0.0.0.0", IsMaster: true, ReplOffset: 1500},
+			{PodName: "rfr-1", Address: "1.1.1.1", SlaveOf: "0.0.0.0", ReplOffset: 1400},
+			{PodName: "rfr-2", Address: "2.2.2.2", SlaveOf: "0.0.0.0", ReplOffset: 1600},
+			{PodName: "rfr-3", Address: "3.3.3.3", SlaveOf: "127.0.0.1"},
+			{PodName: "rfr-4", Address: "4.4.4.4", Err: errors.New("")},
+		},
+	}
+	assert.Equal(map[string]int64{"rfr-1": 100, "rfr-2": 0}, snapshot.ReplicationLags())
+
+	snapshot.Redises[0].IsMaster = false
+	assert.Nil(snapshot.ReplicationLags())
+}
+
+func TestSnapshotCheckAllSlavesFromMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	snapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{Address: "0.0.0.0", IsMaster: true},
+			{Address: "1.1.1.1", SlaveOf: "0.0.0.0"},
+		},
+	}
+	assert.NoError(snapshot.CheckAllSlavesFromMaster("0.0.0.0"))
+
+	snapshot.Redises = append(snapshot.Redises, rfservice.RedisNodeState{Address: "2.2.2.2", SlaveOf: "3.3.3.3"})
+	assert.Error(snapshot.CheckAllSlavesFromMaster("0.0.0.0"))
+
+	snapshot.Redises[2] = rfservice.RedisNodeState{Address: "2.2.2.2", Err: errors.New("")}
+	assert.Error(snapshot.CheckAllSlavesFromMaster("0.0.0.0"))
+}
+
+func TestSnapshotCheckIfMasterLocalhost(t *testing.T) {
+	assert := assert.New(t)
+
+	snapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{Address: "0.0.0.0", SlaveOf: "127.0.0.1"},
+			{Address: "1.1.1.1", SlaveOf: "127.0.0.1"},
+		},
+	}
+	localhost, err := snapshot.CheckIfMasterLocalhost()
+	assert.NoError(err)
+	assert.True(localhost)
+
+	snapshot.Redises[1].SlaveOf = "0.0.0.0"
+	localhost, err = snapshot.CheckIfMasterLocalhost()
+	assert.NoError(err)
+	assert.False(localhost)
+
+	snapshot.Redises[1] = rfservice.RedisNodeState{Address: "1.1.1.1", IsMaster: true}
+	_, err = snapshot.CheckIfMasterLocalhost()
+	assert.Error(err)
+}
+
+func TestSentinelNodeStateChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	sentinel := rfservice.SentinelNodeState{
+		Address:           "0.0.0.0",
+		MonitorIP:         "1.1.1.1",
+		MonitorPort:       "0",
+		SentinelsInMemory: rf.Spec.Sentinel.Replicas,
+		SlavesInMemory:    rf.Spec.Redis.Replicas - 1,
+	}
+	assert.NoError(sentinel.CheckMonitor("1.1.1.1", "0"))
+	assert.Error(sentinel.CheckMonitor("2.2.2.2", "0"))
+	assert.Error(sentinel.CheckMonitor("1.1.1.1", "26379"))
+	assert.NoError(sentinel.CheckSentinelsInMemory(rf.Spec.Sentinel.Replicas))
+	assert.NoError(sentinel.CheckSlavesInMemory(rf))
+
+	sentinel.SentinelsInMemory = 4
+	assert.Error(sentinel.CheckSentinelsInMemory(rf.Spec.Sentinel.Replicas))
+	sentinel.SlavesInMemoryErr = errors.New("")
+	assert.Error(sentinel.CheckSlavesInMemory(rf))
+}
+
+func TestUpdateRoleLabels(t *testing.T) {
+	rf := generateRF()
+
+	snapshot := &rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "rfr-0", Address: "0.0.0.0", RoleLabel: "slave", IsMaster: true},
+			{PodName: "rfr-1", Address: "1.1.1.1", RoleLabel: "slave"},
+			{PodName: "rfr-2", Address: "2.2.2.2", RoleLabel: "master"},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("UpdatePodLabels", namespace, "rfr-0", map[string]string{"redisfailovers-role": "master"}).Once().Return(nil)
+	ms.On("UpdatePodLabels", namespace, "rfr-2", map[string]string{"redisfailovers-role": "slave"}).Once().Return(nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	assert.NoError(t, checker.UpdateRoleLabels(rf, snapshot, "0.0.0.0"))
+	ms.AssertExpectations(t)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+)
+
+// EnsureRedisStorageExpansion expands the redis persistent volume claims to the capacity requested on
+// the RedisFailover, and reports the progress of the expansion on its status. The volume claim
+// templates of the statefulset can't be updated, so the claims are expanded one by one. Out of the
+// maintenance windows, the expansion is left pending.
+func (r *RedisFailoverKubeClient) EnsureRedisStorageExpansion(rf *redisfailoverv1.RedisFailover) error {
+	claim := rf.Spec.Redis.Storage.PersistentVolumeClaim
+	if claim == nil {
+		return r.updateStorageStatus(rf, nil, "")
+	}
+	requested, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return r.updateStorageStatus(rf, nil, "")
+	}
+	inMaintenanceWindow := rf.InMaintenanceWindow(time.Now())
+
+	ss, err := r.K8SService.GetStatefulSet(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+	pvcs, err := r.getStatefulSetPersistentVolumeClaims(ss, claim.Name)
+	if err != nil {
+		return err
+	}
+
+	status := &redisfailoverv1.StorageStatus{
+		RequestedCapacity: requested.String(),
+	}
+	resizePending := []corev1.PersistentVolumeClaim{}
+	for i := range pvcs {
+		pvcStatus, err := r.expandPersistentVolumeClaim(rf, &pvcs[i], requested, inMaintenanceWindow)
+		if err != nil {
+			return err
+		}
+		status.PersistentVolumeClaims = append(status.PersistentVolumeClaims, pvcStatus)
+		if pvcStatus.Phase == redisfailoverv1.StorageExpansionPhaseFileSystemResizePending && fileSystemResizePendingFor(&pvcs[i]) > fileSystemResizePendingTimeout {
+			resizePending = append(resizePending, pvcs[i])
+		}
+	}
+	status.Phase, status.Message = aggregateStorageExpansionPhase(status.PersistentVolumeClaims)
+
+	pending := ""
+	switch {
+	case status.Phase == redisfailoverv1.StorageExpansionPhasePending:
+		pending = fmt.Sprintf("expansion of the persistent volume claims to %s", requested.String())
+	case !inMaintenanceWindow && len(resizePending) > 0:
+		pending = fmt.Sprintf("restart of %d pods to resize the file system of their volumes", len(resizePending))
+	default:
+		if err := r.restartForFileSystemResize(rf, ss, claim.Name, resizePending); err != nil {
+			return err
+		}
+	}
+	return r.updateStorageStatus(rf, status, pending)
+}
+
+// expandPersistentVolumeClaim requests the expansion of the claim when it is smaller than requested and a
+// maintenance window is open, and returns the progress of its expansion
+func (r *RedisFailoverKubeClient) expandPersistentVolumeClaim(rf *redisfailoverv1.RedisFailover, pvc *corev1.PersistentVolumeClaim, requested resource.Quantity, inMaintenanceWindow bool) (redisfailoverv1.PersistentVolumeClaimStorageStatus, error) {
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	status := redisfailoverv1.PersistentVolumeClaimStorageStatus{
+		Name:     pvc.Name,
+		Capacity: capacity.String(),
+	}
+
+	switch {
+	case requested.Cmp(current) < 0:
+		status.Phase = redisfailoverv1.StorageExpansionPhaseFailed
+		status.Message = fmt.Sprintf("persistent volume claims can't be shrunk from %s to %s", current.String(), requested.String())
+	case requested.Cmp(current) > 0:
+		if err := r.checkVolumeExpansionAllowed(pvc); err != nil {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Persistent volume claim %s can't be expanded: %s", pvc.Name, err)
+			status.Phase = redisfailoverv1.StorageExpansionPhaseFailed
+			status.Message = err.Error()
+			return status, nil
+		}
+		if !inMaintenanceWindow {
+			status.Phase = redisfailoverv1.StorageExpansionPhasePending
+			status.Message = "waiting for a maintenance window"
+			return status, nil
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Expanding persistent volume claim %s from %s to %s", pvc.Name, current.String(), requested.String())
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+		if err := r.K8SService.UpdatePersistentVolumeClaim(rf.Namespace, pvc); err != nil {
+			return status, err
+		}
+		status.Phase = redisfailoverv1.StorageExpansionPhaseResizing
+	case pvc.Status.Phase != corev1.ClaimBound || capacity.Cmp(requested) >= 0:
+		// A claim not bound yet gets the requested capacity once it is provisioned.
+		status.Phase = redisfailoverv1.StorageExpansionPhaseResized
+	case getPersistentVolumeClaimCondition(pvc, corev1.PersistentVolumeClaimFileSystemResizePending) != nil:
+		status.Phase = redisfailoverv1.StorageExpansionPhaseFileSystemResizePending
+	default:
+		status.Phase = redisfailoverv1.StorageExpansionPhaseResizing
+	}
+	return status, nil
+}
+
+// checkVolumeExpansionAllowed returns an error when the storage class of the claim doesn't allow to
+// expand its volumes
+func (r *RedisFailoverKubeClient) checkVolumeExpansionAllowed(pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return fmt.Errorf("persistent volume claim %s has no storage class", pvc.Name)
+	}
+	storageClass, err := r.K8SService.GetStorageClass(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return fmt.Errorf("unable to get storage class %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return fmt.Errorf("storage class %s doesn't allow volume expansion", storageClass.Name)
+	}
+	return nil
+}
+
+// restartForFileSystemResize restarts a pod whose file system is still waiting to be resized, for the
+// volumes that can only be expanded offline. Only one pod is restarted at a time, when all of them are
+// ready, and the master is the last one.
+func (r *RedisFailoverKubeClient) restartForFileSystemResize(rf *redisfailoverv1.RedisFailover, ss *appsv1.StatefulSet, claimName string, pvcs []corev1.PersistentVolumeClaim) error {
+	if len(pvcs) == 0 {
+		return nil
+	}
+	if ss.Spec.Replicas == nil || ss.Status.ReadyReplicas != *ss.Spec.Replicas {
+		return nil
+	}
+
+	var restart *corev1.Pod
+	for _, pvc := range pvcs {
+		pod, err := r.K8SService.GetPod(rf.Namespace, strings.TrimPrefix(pvc.Name, claimName+"-"))
+		if err != nil {
+			return err
+		}
+		if pod.DeletionTimestamp != nil {
+			return nil
+		}
+		if restart == nil || restart.Labels[redisRoleLabelKey] == redisRoleLabelMaster {
+			restart = pod
+		}
+	}
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Restarting pod %s to resize the file system of its volume", restart.Name)
+	return r.K8SService.DeletePod(rf.Namespace, restart.Name)
+}
+
+// updateStorageStatus updates the storage status of the RedisFailover, and the expansion pending for a
+// maintenance window, when they changed. The status is updated from a copy, so the defaults set on the
+// given one are not persisted.
+func (r *RedisFailoverKubeClient) updateStorageStatus(rf *redisfailoverv1.RedisFailover, status *redisfailoverv1.StorageStatus, pending string) error {
+	r.metricsClient.SetPendingOperation(rf.Namespace, rf.Name, string(redisfailoverv1.PendingOperationStorageExpansion), pending != "")
+	rfCopy := rf.DeepCopy()
+	pendingChanged := rfCopy.Status.SetPendingOperation(redisfailoverv1.PendingOperationStorageExpansion, pending)
+	if reflect.DeepEqual(rf.Status.Storage, status) && !pendingChanged {
+		return nil
+	}
+	rfCopy.Status.Storage = status
+	updated, err := r.K8SService.UpdateRedisFailoverStatus(context.TODO(), rfCopy)
+	if err != nil {
+		return err
+	}
+	if updated != nil {
+		rf.ResourceVersion = updated.ResourceVersion
+	}
+	rf.Status.Storage = status
+	rf.Status.PendingOperations = rfCopy.Status.PendingOperations
+	return nil
+}
+
+// getStatefulSetPersistentVolumeClaims returns the persistent volume claims created by the statefulset from
+// the given volume claim template, sorted by name. The statefulset controller sets its selector labels on them.
+func (r *RedisFailoverKubeClient) getStatefulSetPersistentVolumeClaims(ss *appsv1.StatefulSet, claimName string) ([]corev1.PersistentVolumeClaim, error) {
+	if ss.Spec.Selector == nil {
+		return nil, nil
+	}
+	pvcs, err := r.K8SService.ListPersistentVolumeClaims(ss.Namespace, ss.Spec.Selector.MatchLabels)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%s-%s-", claimName, ss.Name)
+	claims := []corev1.PersistentVolumeClaim{}
+	for _, pvc := range pvcs.Items {
+		if strings.HasPrefix(pvc.Name, prefix) {
+			claims = append(claims, pvc)
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Name < claims[j].Name
+	})
+	return claims, nil
+}
+
+func getPersistentVolumeClaimCondition(pvc *corev1.PersistentVolumeClaim, conditionType corev1.PersistentVolumeClaimConditionType) *corev1.PersistentVolumeClaimCondition {
+	for i, condition := range pvc.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return &pvc.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// fileSystemResizePendingFor returns for how long the file system of the claim is waiting to be resized
+func fileSystemResizePendingFor(pvc *corev1.PersistentVolumeClaim) time.Duration {
+	condition := getPersistentVolumeClaimCondition(pvc, corev1.PersistentVolumeClaimFileSystemResizePending)
+	if condition == nil || condition.LastTransitionTime.IsZero() {
+		return 0
+	}
+	return time.Since(condition.LastTransitionTime.Time)
+}
+
+// aggregateStorageExpansionPhase returns the phase of the least advanced claim, with the message of the
+// first failing one
+func aggregateStorageExpansionPhase(pvcs []redisfailoverv1.PersistentVolumeClaimStorageStatus) (redisfailoverv1.StorageExpansionPhase, string) {
+	phase := redisfailoverv1.StorageExpansionPhaseResized
+	for _, pvc := range pvcs {
+		switch pvc.Phase {
+		case redisfailoverv1.StorageExpansionPhaseFailed:
+			return redisfailoverv1.StorageExpansionPhaseFailed, fmt.Sprintf("%s: %s", pvc.Name, pvc.Message)
+		case redisfailoverv1.StorageExpansionPhasePending:
+			phase = redisfailoverv1.StorageExpansionPhasePending
+		case redisfailoverv1.StorageExpansionPhaseResizing:
+			if phase != redisfailoverv1.StorageExpansionPhasePending {
+				phase = redisfailoverv1.StorageExpansionPhaseResizing
+			}
+		case redisfailoverv1.StorageExpansionPhaseFileSystemResizePending:
+			if phase == redisfailoverv1.StorageExpansionPhaseResized {
+				phase = redisfailoverv1.StorageExpansionPhaseFileSystemResizePending
+			}
+		}
+	}
+	return phase, ""
+}
@@ -0,0 +1,275 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func generateStoragePVC(name, requested, capacity string, conditions ...corev1.PersistentVolumeClaimCondition) corev1.PersistentVolumeClaim {
+	storageClassName := "standard"
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(requested),
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(capacity),
+			},
+			Conditions: conditions,
+		},
+	}
+}
+
+// generateClosedMaintenanceWindow returns a maintenance window that only opens tomorrow
+func generateClosedMaintenanceWindow() redisfailoverv1.MaintenanceWindow {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Weekday().String()[:3]
+	return redisfailoverv1.MaintenanceWindow{
+		Days:     []string{tomorrow},
+		Start:    "00:00",
+		Duration: metav1.Duration{Duration: time.Minute},
+	}
+}
+
+func TestEnsureRedisStorageExpansion(t *testing.T) {
+	resizePendingSince := func(d time.Duration) corev1.PersistentVolumeClaimCondition {
+		return corev1.PersistentVolumeClaimCondition{
+			Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-d)),
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		requested            string
+		pvcs                 []corev1.PersistentVolumeClaim
+		allowVolumeExpansion bool
+		maintenanceWindows   []redisfailoverv1.MaintenanceWindow
+		expUpdatedPVCs       []string
+		expDeletedPod        string
+		expStatus            *redisfailoverv1.StorageStatus
+		expPending           []redisfailoverv1.PendingOperation
+	}{
+		{
+			name:                 "Smaller claims are expanded when the storage class allows it",
+			requested:            "2Gi",
+			pvcs:                 []corev1.PersistentVolumeClaim{generateStoragePVC("redis-data-rfr-test-0", "1Gi", "1Gi"), generateStoragePVC("redis-data-rfr-test-1", "2Gi", "2Gi")},
+			allowVolumeExpansion: true,
+			expUpdatedPVCs:       []string{"redis-data-rfr-test-0"},
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseResizing,
+				RequestedCapacity: "2Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhaseResizing},
+					{Name: "redis-data-rfr-test-1", Capacity: "2Gi", Phase: redisfailoverv1.StorageExpansionPhaseResized},
+				},
+			},
+		},
+		{
+			name:                 "Claims wait for a maintenance window to be expanded",
+			requested:            "2Gi",
+			pvcs:                 []corev1.PersistentVolumeClaim{generateStoragePVC("redis-data-rfr-test-0", "1Gi", "1Gi"), generateStoragePVC("redis-data-rfr-test-1", "2Gi", "2Gi")},
+			allowVolumeExpansion: true,
+			maintenanceWindows:   []redisfailoverv1.MaintenanceWindow{generateClosedMaintenanceWindow()},
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhasePending,
+				RequestedCapacity: "2Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhasePending, Message: "waiting for a maintenance window"},
+					{Name: "redis-data-rfr-test-1", Capacity: "2Gi", Phase: redisfailoverv1.StorageExpansionPhaseResized},
+				},
+			},
+			expPending: []redisfailoverv1.PendingOperation{
+				{Type: redisfailoverv1.PendingOperationStorageExpansion, Message: "expansion of the persistent volume claims to 2Gi"},
+			},
+		},
+		{
+			name:                 "Claims are not expanded when the storage class doesn't allow it",
+			requested:            "2Gi",
+			pvcs:                 []corev1.PersistentVolumeClaim{generateStoragePVC("redis-data-rfr-test-0", "1Gi", "1Gi")},
+			allowVolumeExpansion: false,
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseFailed,
+				RequestedCapacity: "2Gi",
+				Message:           "redis-data-rfr-test-0: storage class standard doesn't allow volume expansion",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhaseFailed, Message: "storage class standard doesn't allow volume expansion"},
+				},
+			},
+		},
+		{
+			name:      "Claims are not shrunk",
+			requested: "1Gi",
+			pvcs:      []corev1.PersistentVolumeClaim{generateStoragePVC("redis-data-rfr-test-0", "2Gi", "2Gi")},
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseFailed,
+				RequestedCapacity: "1Gi",
+				Message:           "redis-data-rfr-test-0: persistent volume claims can't be shrunk from 2Gi to 1Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "2Gi", Phase: redisfailoverv1.StorageExpansionPhaseFailed, Message: "persistent volume claims can't be shrunk from 2Gi to 1Gi"},
+				},
+			},
+		},
+		{
+			name:      "A pod waiting for too long for the file system resize is restarted, the master the last one",
+			requested: "2Gi",
+			pvcs: []corev1.PersistentVolumeClaim{
+				generateStoragePVC("redis-data-rfr-test-0", "2Gi", "1Gi", resizePendingSince(10*time.Minute)),
+				generateStoragePVC("redis-data-rfr-test-1", "2Gi", "1Gi", resizePendingSince(10*time.Minute)),
+			},
+			expDeletedPod: "rfr-test-1",
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseFileSystemResizePending,
+				RequestedCapacity: "2Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhaseFileSystemResizePending},
+					{Name: "redis-data-rfr-test-1", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhaseFileSystemResizePending},
+				},
+			},
+		},
+		{
+			name:      "A pod waiting for too long for the file system resize waits for a maintenance window to be restarted",
+			requested: "2Gi",
+			pvcs: []corev1.PersistentVolumeClaim{
+				generateStoragePVC("redis-data-rfr-test-0", "2Gi", "1Gi", resizePendingSince(10*time.Minute)),
+			},
+			maintenanceWindows: []redisfailoverv1.MaintenanceWindow{generateClosedMaintenanceWindow()},
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseFileSystemResizePending,
+				RequestedCapacity: "2Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhaseFileSystemResizePending},
+				},
+			},
+			expPending: []redisfailoverv1.PendingOperation{
+				{Type: redisfailoverv1.PendingOperationStorageExpansion, Message: "restart of 1 pods to resize the file system of their volumes"},
+			},
+		},
+		{
+			name:      "A pod waiting shortly for the file system resize is not restarted",
+			requested: "2Gi",
+			pvcs:      []corev1.PersistentVolumeClaim{generateStoragePVC("redis-data-rfr-test-0", "2Gi", "1Gi", resizePendingSince(time.Minute))},
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseFileSystemResizePending,
+				RequestedCapacity: "2Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "1Gi", Phase: redisfailoverv1.StorageExpansionPhaseFileSystemResizePending},
+				},
+			},
+		},
+		{
+			name:      "Claims of other volumes are ignored",
+			requested: "2Gi",
+			pvcs:      []corev1.PersistentVolumeClaim{generateStoragePVC("redis-data-rfr-test-0", "2Gi", "2Gi"), generateStoragePVC("other-rfr-test-0", "1Gi", "1Gi")},
+			expStatus: &redisfailoverv1.StorageStatus{
+				Phase:             redisfailoverv1.StorageExpansionPhaseResized,
+				RequestedCapacity: "2Gi",
+				PersistentVolumeClaims: []redisfailoverv1.PersistentVolumeClaimStorageStatus{
+					{Name: "redis-data-rfr-test-0", Capacity: "2Gi", Phase: redisfailoverv1.StorageExpansionPhaseResized},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(test.requested),
+						},
+					},
+				},
+			}
+			rf.Spec.MaintenanceWindows = test.maintenanceWindows
+			selector := map[string]string{"app.kubernetes.io/component": "redis", "app.kubernetes.io/name": name}
+			replicas := int32(2)
+			ss := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: rfservice.GetRedisName(rf), Namespace: namespace},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					Selector: &metav1.LabelSelector{MatchLabels: selector},
+				},
+				Status: appsv1.StatefulSetStatus{ReadyReplicas: replicas},
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(ss, nil)
+			ms.On("ListPersistentVolumeClaims", namespace, selector).Once().Return(&corev1.PersistentVolumeClaimList{Items: test.pvcs}, nil)
+			ms.On("GetStorageClass", "standard").Return(&storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+				AllowVolumeExpansion: &test.allowVolumeExpansion,
+			}, nil).Maybe()
+			for _, pvc := range test.expUpdatedPVCs {
+				pvc := pvc
+				ms.On("UpdatePersistentVolumeClaim", namespace, mock.MatchedBy(func(updated *corev1.PersistentVolumeClaim) bool {
+					requested := updated.Spec.Resources.Requests[corev1.ResourceStorage]
+					return updated.Name == pvc && requested.String() == test.requested
+				})).Once().Return(nil)
+			}
+			if test.expDeletedPod != "" {
+				ms.On("GetPod", namespace, "rfr-test-0").Once().Return(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0", Labels: map[string]string{"redisfailovers-role": "master"}}}, nil)
+				ms.On("GetPod", namespace, "rfr-test-1").Once().Return(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1", Labels: map[string]string{"redisfailovers-role": "slave"}}}, nil)
+				ms.On("DeletePod", namespace, test.expDeletedPod).Once().Return(nil)
+			}
+			var status redisfailoverv1.RedisFailoverStatus
+			ms.On("UpdateRedisFailoverStatus", mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+				status = args.Get(1).(*redisfailoverv1.RedisFailover).Status
+			}).Return(nil, nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureRedisStorageExpansion(rf)
+
+			assert.NoError(err)
+			assert.Equal(test.expStatus, status.Storage)
+			assert.Equal(test.expPending, status.PendingOperations)
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnsureRedisStorageExpansionWithoutPVC(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureRedisStorageExpansion(rf))
+
+	rf.Status.Storage = &redisfailoverv1.StorageStatus{Phase: redisfailoverv1.StorageExpansionPhaseResized}
+	ms.On("UpdateRedisFailoverStatus", mock.Anything, mock.MatchedBy(func(updated *redisfailoverv1.RedisFailover) bool {
+		return updated.Status.Storage == nil
+	})).Once().Return(nil, nil)
+	assert.NoError(client.EnsureRedisStorageExpansion(rf))
+	ms.AssertExpectations(t)
+}
@@ -0,0 +1,188 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// RedisFailoverSnapshotHandler is the Redis Failover snapshot handler. This handler takes a VolumeSnapshot
+// of the data of a replica of the RF, once the replica has saved its dataset to disk.
+type RedisFailoverSnapshotHandler struct {
+	k8sservice k8s.Services
+	rfChecker  rfservice.RedisFailoverCheck
+	rfHealer   rfservice.RedisFailoverHeal
+	logger     log.Logger
+}
+
+// NewRedisFailoverSnapshotHandler returns a new RF snapshot handler
+func NewRedisFailoverSnapshotHandler(k8sservice k8s.Services, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, logger log.Logger) *RedisFailoverSnapshotHandler {
+	return &RedisFailoverSnapshotHandler{
+		k8sservice: k8sservice,
+		rfChecker:  rfChecker,
+		rfHealer:   rfHealer,
+		logger:     logger,
+	}
+}
+
+// Handle will move the redis failover snapshot to its next phase. Every phase is stored on the status,
+// so a snapshot interrupted by an operator restart goes on where it was left.
+func (h *RedisFailoverSnapshotHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	rfs, ok := obj.(*redisfailoverv1.RedisFailoverSnapshot)
+	if !ok {
+		return fmt.Errorf("can't handle the received object: not a redisfailoversnapshot")
+	}
+	if rfs.DeletionTimestamp != nil {
+		return nil
+	}
+
+	switch rfs.Status.Phase {
+	case "":
+		return h.selectReplica(ctx, rfs)
+	case redisfailoverv1.RedisFailoverSnapshotPhaseSaving:
+		return h.saveAndSnapshot(ctx, rfs)
+	case redisfailoverv1.RedisFailoverSnapshotPhaseSnapshotting:
+		return h.checkVolumeSnapshot(rfs)
+	default:
+		return nil
+	}
+}
+
+// selectReplica chooses the replica whose volume is snapshotted. Replicas are used so the master is
+// not loaded with the save.
+func (h *RedisFailoverSnapshotHandler) selectReplica(ctx context.Context, rfs *redisfailoverv1.RedisFailoverSnapshot) error {
+	rf, permanent, err := h.getRedisFailover(rfs)
+	if permanent {
+		return h.fail(rfs, err)
+	}
+	if err != nil {
+		return err
+	}
+	if rf.Spec.Redis.Storage.PersistentVolumeClaim == nil {
+		return h.fail(rfs, fmt.Errorf("redisfailover %s has no persistent volume claims", rf.Name))
+	}
+
+	snapshot, err := h.rfChecker.GetSnapshot(ctx, rf)
+	if err != nil {
+		return err
+	}
+	for _, redis := range snapshot.Redises {
+		if redis.Err != nil || redis.IsMaster || !redis.SlaveReady {
+			continue
+		}
+		status := redisfailoverv1.RedisFailoverSnapshotStatus{
+			Phase:                     redisfailoverv1.RedisFailoverSnapshotPhaseSaving,
+			PodName:                   redis.PodName,
+			PersistentVolumeClaimName: fmt.Sprintf("%s-%s", rf.Spec.Redis.Storage.PersistentVolumeClaim.Name, redis.PodName),
+			VolumeSnapshotName:        rfs.Name,
+		}
+		return h.updateStatus(rfs, status)
+	}
+	return fmt.Errorf("no replica of redisfailover %s is ready to be snapshotted", rf.Name)
+}
+
+// saveAndSnapshot makes the selected replica save its dataset to disk, and takes a VolumeSnapshot of
+// its volume afterwards
+func (h *RedisFailoverSnapshotHandler) saveAndSnapshot(ctx context.Context, rfs *redisfailoverv1.RedisFailoverSnapshot) error {
+	rf, permanent, err := h.getRedisFailover(rfs)
+	if permanent {
+		return h.fail(rfs, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := h.rfChecker.GetSnapshot(ctx, rf)
+	if err != nil {
+		return err
+	}
+	address := ""
+	for _, redis := range snapshot.Redises {
+		if redis.PodName == rfs.Status.PodName && redis.Err == nil {
+			address = redis.Address
+		}
+	}
+	if address == "" {
+		h.logger.WithField("redisfailoversnapshot", rfs.Name).WithField("namespace", rfs.Namespace).Warningf("Pod %s is not available anymore, selecting another replica", rfs.Status.PodName)
+		return h.selectReplica(ctx, rfs)
+	}
+
+	if err := h.rfHealer.BackgroundSave(ctx, address, rf); err != nil {
+		return err
+	}
+
+	volumeSnapshot := k8s.NewVolumeSnapshotFromPVC(rfs.Namespace, rfs.Status.VolumeSnapshotName, rfs.Status.PersistentVolumeClaimName, rfs.Spec.VolumeSnapshotClassName, util.MergeLabels(defaultLabels, map[string]string{rfLabelNameKey: rf.Name}))
+	volumeSnapshot.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(rfs, redisfailoverv1.VersionKind(redisfailoverv1.RFSnapshotKind)),
+	})
+	if err := h.k8sservice.CreateVolumeSnapshot(rfs.Namespace, volumeSnapshot); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	status := *rfs.Status.DeepCopy()
+	status.Phase = redisfailoverv1.RedisFailoverSnapshotPhaseSnapshotting
+	return h.updateStatus(rfs, status)
+}
+
+// checkVolumeSnapshot waits for the VolumeSnapshot to be ready to use
+func (h *RedisFailoverSnapshotHandler) checkVolumeSnapshot(rfs *redisfailoverv1.RedisFailoverSnapshot) error {
+	volumeSnapshot, err := h.k8sservice.GetVolumeSnapshot(rfs.Namespace, rfs.Status.VolumeSnapshotName)
+	if errors.IsNotFound(err) {
+		return h.fail(rfs, fmt.Errorf("volume snapshot %s not found", rfs.Status.VolumeSnapshotName))
+	}
+	if err != nil {
+		return err
+	}
+
+	if message, found, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "error", "message"); found && message != "" {
+		return h.fail(rfs, fmt.Errorf("volume snapshot %s failed: %s", volumeSnapshot.GetName(), message))
+	}
+	if ready, found, _ := unstructured.NestedBool(volumeSnapshot.Object, "status", "readyToUse"); found && ready {
+		status := *rfs.Status.DeepCopy()
+		status.Phase = redisfailoverv1.RedisFailoverSnapshotPhaseReady
+		return h.updateStatus(rfs, status)
+	}
+	// Not ready yet, it is checked again on the next resync.
+	return nil
+}
+
+// getRedisFailover returns the snapshotted RedisFailover with its defaults set. permanent is true when the
+// error won't be solved by retrying.
+func (h *RedisFailoverSnapshotHandler) getRedisFailover(rfs *redisfailoverv1.RedisFailoverSnapshot) (rf *redisfailoverv1.RedisFailover, permanent bool, err error) {
+	rf, err = h.k8sservice.GetRedisFailover(context.TODO(), rfs.Namespace, rfs.Spec.RedisFailoverName)
+	if errors.IsNotFound(err) {
+		return nil, true, fmt.Errorf("redisfailover %s not found", rfs.Spec.RedisFailoverName)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := rf.Validate(); err != nil {
+		return nil, true, err
+	}
+	return rf, false, nil
+}
+
+func (h *RedisFailoverSnapshotHandler) fail(rfs *redisfailoverv1.RedisFailoverSnapshot, err error) error {
+	status := *rfs.Status.DeepCopy()
+	status.Phase = redisfailoverv1.RedisFailoverSnapshotPhaseFailed
+	status.Message = err.Error()
+	return h.updateStatus(rfs, status)
+}
+
+func (h *RedisFailoverSnapshotHandler) updateStatus(rfs *redisfailoverv1.RedisFailoverSnapshot, status redisfailoverv1.RedisFailoverSnapshotStatus) error {
+	h.logger.WithField("redisfailoversnapshot", rfs.Name).WithField("namespace", rfs.Namespace).Infof("Redis failover snapshot phase changed to %s", status.Phase)
+	rfsCopy := rfs.DeepCopy()
+	rfsCopy.Status = status
+	_, err := h.k8sservice.UpdateRedisFailoverSnapshotStatus(context.TODO(), rfsCopy)
+	return err
+}
@@ -0,0 +1,142 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestRedisFailoverSnapshotHandle(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+		EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+	}
+	rfs := &redisfailoverv1.RedisFailoverSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace},
+		Spec: redisfailoverv1.RedisFailoverSnapshotSpec{
+			RedisFailoverName:       name,
+			VolumeSnapshotClassName: "csi-snapclass",
+		},
+	}
+
+	rfcli := redisfailoverfake.NewSimpleClientset(rf, rfs)
+	dyncli := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(), map[schema.GroupVersionResource]string{
+		k8s.VolumeSnapshotGroupVersionResource: "VolumeSnapshotList",
+	})
+	k8sService := k8s.New(kubernetes.NewSimpleClientset(), rfcli, nil, dyncli, log.Dummy, metrics.Dummy)
+
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetSnapshot", mock.Anything, mock.Anything).Return(&rfservice.RedisFailoverSnapshot{
+		Redises: []rfservice.RedisNodeState{
+			{PodName: "rfr-test-0", Address: "0.0.0.0", IsMaster: true},
+			{PodName: "rfr-test-1", Address: "0.0.0.1", SlaveReady: false},
+			{PodName: "rfr-test-2", Address: "0.0.0.2", SlaveReady: true},
+		},
+	}, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("BackgroundSave", mock.Anything, "0.0.0.2", mock.Anything).Once().Return(nil)
+
+	handler := rfOperator.NewRedisFailoverSnapshotHandler(k8sService, mrfc, mrfh, log.Dummy)
+	handle := func() *redisfailoverv1.RedisFailoverSnapshot {
+		stored, err := k8sService.GetRedisFailoverSnapshot(context.TODO(), namespace, "backup")
+		assert.NoError(err)
+		assert.NoError(handler.Handle(context.TODO(), stored))
+		stored, err = k8sService.GetRedisFailoverSnapshot(context.TODO(), namespace, "backup")
+		assert.NoError(err)
+		return stored
+	}
+
+	// A ready replica is selected.
+	stored := handle()
+	assert.Equal(redisfailoverv1.RedisFailoverSnapshotStatus{
+		Phase:                     redisfailoverv1.RedisFailoverSnapshotPhaseSaving,
+		PodName:                   "rfr-test-2",
+		PersistentVolumeClaimName: "redis-data-rfr-test-2",
+		VolumeSnapshotName:        "backup",
+	}, stored.Status)
+
+	// The replica saves its dataset and its volume is snapshotted.
+	stored = handle()
+	assert.Equal(redisfailoverv1.RedisFailoverSnapshotPhaseSnapshotting, stored.Status.Phase)
+	volumeSnapshot, err := k8sService.GetVolumeSnapshot(namespace, "backup")
+	assert.NoError(err)
+	pvcName, _, _ := unstructured.NestedString(volumeSnapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	assert.Equal("redis-data-rfr-test-2", pvcName)
+	assert.Equal("backup", volumeSnapshot.GetOwnerReferences()[0].Name)
+
+	// The snapshot waits for the VolumeSnapshot to be ready.
+	stored = handle()
+	assert.Equal(redisfailoverv1.RedisFailoverSnapshotPhaseSnapshotting, stored.Status.Phase)
+
+	assert.NoError(unstructured.SetNestedField(volumeSnapshot.Object, true, "status", "readyToUse"))
+	_, err = dyncli.Resource(k8s.VolumeSnapshotGroupVersionResource).Namespace(namespace).Update(context.TODO(), volumeSnapshot, metav1.UpdateOptions{})
+	assert.NoError(err)
+	stored = handle()
+	assert.Equal(redisfailoverv1.RedisFailoverSnapshotPhaseReady, stored.Status.Phase)
+
+	mrfh.AssertExpectations(t)
+}
+
+func TestRedisFailoverSnapshotHandleFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		rf         *redisfailoverv1.RedisFailover
+		expMessage string
+	}{
+		{
+			name:       "Missing redisfailover",
+			expMessage: "redisfailover test not found",
+		},
+		{
+			name:       "Redisfailover without persistent volume claims",
+			rf:         generateRF(false, false),
+			expMessage: "redisfailover test has no persistent volume claims",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rfs := &redisfailoverv1.RedisFailoverSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace},
+				Spec:       redisfailoverv1.RedisFailoverSnapshotSpec{RedisFailoverName: name},
+			}
+			objects := []k8sruntime.Object{rfs}
+			if test.rf != nil {
+				objects = append(objects, test.rf)
+			}
+			k8sService := k8s.New(kubernetes.NewSimpleClientset(), redisfailoverfake.NewSimpleClientset(objects...), nil, nil, log.Dummy, metrics.Dummy)
+
+			handler := rfOperator.NewRedisFailoverSnapshotHandler(k8sService, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, log.Dummy)
+			assert.NoError(handler.Handle(context.TODO(), rfs))
+
+			stored, err := k8sService.GetRedisFailoverSnapshot(context.TODO(), namespace, "backup")
+			assert.NoError(err)
+			assert.Equal(redisfailoverv1.RedisFailoverSnapshotPhaseFailed, stored.Status.Phase)
+			assert.Equal(test.expMessage, stored.Status.Message)
+
+			// Failed snapshots are not retried.
+			assert.NoError(handler.Handle(context.TODO(), stored))
+		})
+	}
+}
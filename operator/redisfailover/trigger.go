@@ -0,0 +1,195 @@
+package redisfailover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spotahome/kooper/v2/controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/spotahome/redis-operator/log"
+)
+
+// triggerDelay is the time the triggers of a RedisFailover are gathered for, so a burst of changes makes
+// it be handled once
+const triggerDelay = 250 * time.Millisecond
+
+// reconcileTrigger makes the controller handle a RedisFailover right away instead of waiting for the
+// resync. The last state of the RedisFailover seen by the retriever is sent again as modified through its
+// watches: being the state the informer already has, its resource version doesn't move and no change in
+// between is skipped when the watch is restarted.
+type reconcileTrigger struct {
+	logger log.Logger
+
+	mu      sync.Mutex
+	watches map[chan string]chan struct{} // trigger channel -> done channel of the watch
+	objects map[string]runtime.Object     // RedisFailover key -> last state seen by the retriever
+	pending map[string]bool               // RedisFailovers waiting for the trigger delay
+}
+
+func newReconcileTrigger(logger log.Logger) *reconcileTrigger {
+	return &reconcileTrigger{
+		logger:  logger,
+		watches: map[chan string]chan struct{}{},
+		objects: map[string]runtime.Object{},
+		pending: map[string]bool{},
+	}
+}
+
+// retriever wraps the retriever so the watches it opens receive the triggered RedisFailovers too
+func (t *reconcileTrigger) retriever(ret controller.Retriever) controller.Retriever {
+	return triggeredRetriever{Retriever: ret, trigger: t}
+}
+
+// trigger sends the last state of the RedisFailover to the open watches after the trigger delay
+func (t *reconcileTrigger) trigger(namespace, name string) {
+	key := namespace + "/" + name
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending[key] {
+		return
+	}
+	t.pending[key] = true
+	time.AfterFunc(triggerDelay, func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+		t.send(key)
+	})
+}
+
+// send passes the key to the open watches, that are only open while the controller is leading
+func (t *reconcileTrigger) send(key string) {
+	t.mu.Lock()
+	watches := make(map[chan string]chan struct{}, len(t.watches))
+	for triggered, done := range t.watches {
+		watches[triggered] = done
+	}
+	t.mu.Unlock()
+
+	for triggered, done := range watches {
+		select {
+		case triggered <- key:
+		case <-done:
+		}
+	}
+}
+
+// setListed replaces the known RedisFailovers with the listed ones
+func (t *reconcileTrigger) setListed(list runtime.Object) {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		t.logger.Warningf("Unable to keep the listed RedisFailovers to trigger them: %s", err)
+		return
+	}
+	objects := make(map[string]runtime.Object, len(items))
+	for _, item := range items {
+		if key, err := cache.MetaNamespaceKeyFunc(item); err == nil {
+			objects[key] = item
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objects = objects
+}
+
+// record keeps the state of the RedisFailover of a watch event
+func (t *reconcileTrigger) record(event watch.Event) {
+	if event.Type != watch.Added && event.Type != watch.Modified && event.Type != watch.Deleted {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(event.Object)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if event.Type == watch.Deleted {
+		delete(t.objects, key)
+		return
+	}
+	t.objects[key] = event.Object
+}
+
+func (t *reconcileTrigger) object(key string) (runtime.Object, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	obj, ok := t.objects[key]
+	return obj, ok
+}
+
+// wrap returns a watch sending the events of the given one along with the triggered ones. Both are sent
+// from the same goroutine, so a triggered state is never sent after a newer one.
+func (t *reconcileTrigger) wrap(w watch.Interface) watch.Interface {
+	result := make(chan watch.Event)
+	proxy := watch.NewProxyWatcher(result)
+	triggered := make(chan string)
+	done := make(chan struct{})
+
+	t.mu.Lock()
+	t.watches[triggered] = done
+	t.mu.Unlock()
+
+	go func() {
+		defer func() {
+			w.Stop()
+			close(done)
+			t.mu.Lock()
+			delete(t.watches, triggered)
+			t.mu.Unlock()
+			close(result)
+		}()
+		for {
+			var event watch.Event
+			select {
+			case e, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+				t.record(e)
+				event = e
+			case key := <-triggered:
+				obj, ok := t.object(key)
+				if !ok {
+					continue
+				}
+				event = watch.Event{Type: watch.Modified, Object: obj}
+			case <-proxy.StopChan():
+				return
+			}
+			select {
+			case result <- event:
+			case <-proxy.StopChan():
+				return
+			}
+		}
+	}()
+	return proxy
+}
+
+type triggeredRetriever struct {
+	controller.Retriever
+	trigger *reconcileTrigger
+}
+
+func (r triggeredRetriever) List(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+	list, err := r.Retriever.List(ctx, options)
+	if err != nil {
+		return list, err
+	}
+	r.trigger.setListed(list)
+	return list, nil
+}
+
+func (r triggeredRetriever) Watch(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+	w, err := r.Retriever.Watch(ctx, options)
+	if err != nil {
+		return w, err
+	}
+	return r.trigger.wrap(w), nil
+}
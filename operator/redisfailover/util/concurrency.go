@@ -0,0 +1,26 @@
+package util
+
+import "sync"
+
+// RunConcurrently calls fn for every index in [0, n), running at most limit calls at the same time.
+// It returns once all the calls have finished.
+func RunConcurrently(n int, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
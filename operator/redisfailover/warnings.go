@@ -0,0 +1,68 @@
+package redisfailover
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// warnedGenerations keeps the generation of every RedisFailover whose spec warnings were recorded, so
+// they are only recorded again once the spec changes.
+type warnedGenerations struct {
+	mu         sync.Mutex
+	generation map[string]int64 // RedisFailover key -> generation
+}
+
+func newWarnedGenerations() *warnedGenerations {
+	return &warnedGenerations{
+		generation: map[string]int64{},
+	}
+}
+
+func warnedGenerationsKey(rf *redisfailoverv1.RedisFailover) string {
+	return fmt.Sprintf("%s/%s", rf.Namespace, rf.Name)
+}
+
+// set records the generation of the RedisFailover, returning whether it was already recorded
+func (w *warnedGenerations) set(rf *redisfailoverv1.RedisFailover) (recorded bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := warnedGenerationsKey(rf)
+	generation, known := w.generation[key]
+	w.generation[key] = rf.Generation
+	return known && generation == rf.Generation
+}
+
+// forget removes everything known about the RedisFailover
+func (w *warnedGenerations) forget(rf *redisfailoverv1.RedisFailover) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.generation, warnedGenerationsKey(rf))
+}
+
+// recordWarnings records the warnings of the spec of the RedisFailover as events, once per generation
+func (r *RedisFailoverHandler) recordWarnings(rf *redisfailoverv1.RedisFailover) {
+	if r.warned.set(rf) {
+		return
+	}
+	for _, warning := range rf.Warnings() {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warn(warning)
+		r.k8sservice.RecordEvent(rf, corev1.EventTypeWarning, specWarningReason, warning)
+	}
+}
+
+// recordMonitoringNotInstalled records the prometheus-operator objects that couldn't be created, once per
+// generation of the RedisFailover
+func (r *RedisFailoverHandler) recordMonitoringNotInstalled(rf *redisfailoverv1.RedisFailover, err *rfservice.MonitoringNotInstalledError) {
+	if r.monitoringWarned.set(rf) {
+		return
+	}
+	message := fmt.Sprintf("Unable to create %s: the prometheus-operator CRDs are not installed", strings.Join(err.Objects, ", "))
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warn(message)
+	r.k8sservice.RecordEvent(rf, corev1.EventTypeWarning, monitoringNotInstalledReason, message)
+}
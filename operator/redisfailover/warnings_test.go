@@ -0,0 +1,102 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestHandleRecordsWarningsOncePerGeneration(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Generation = 1
+	rf.Spec.Monitoring.Enabled = true
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	mrfs.On("EnsureFinalizer", rf).Return(nil)
+	mrfs.On("EnsureNotPresentRedisService", rf).Return(errors.New("stop"))
+	mk.On("RecordEvent", rf, corev1.EventTypeWarning, "SpecWarning", "monitoring is enabled without the redis exporter, the alerts have no metrics to fire on").Twice()
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	assert.EqualError(handler.Handle(context.Background(), rf), "stop")
+	// The warnings of the same spec are not recorded again
+	assert.EqualError(handler.Handle(context.Background(), rf), "stop")
+	mk.AssertNumberOfCalls(t, "RecordEvent", 1)
+
+	// But they are once it changes
+	rf.Generation = 2
+	assert.EqualError(handler.Handle(context.Background(), rf), "stop")
+
+	mk.AssertExpectations(t)
+	mrfs.AssertExpectations(t)
+}
+
+func TestEnsureRecordsMonitoringNotInstalledOncePerGeneration(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Generation = 1
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	mrfs.On("EnsureNotPresentRedisService", rf).Return(nil)
+	mrfs.On("EnsureSentinelService", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureSentinelConfigMap", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureSentinelDeployment", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisMasterService", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisSlaveService", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisConfigMap", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisShutdownConfigMap", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisReadinessConfigMap", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureNetworkPolicies", rf, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisStatefulset", rf, mock.Anything, mock.Anything).Return(nil)
+	mrfs.On("EnsureRedisStorageExpansion", rf).Return(nil)
+	notInstalled := &rfservice.MonitoringNotInstalledError{Objects: []string{"ServiceMonitor rfr-test"}}
+	mrfs.On("EnsureMonitoring", rf, mock.Anything, mock.Anything).Times(3).Return(notInstalled)
+	mrfs.On("EnsureMonitoring", rf, mock.Anything, mock.Anything).Once().Return(nil)
+	mrfs.On("EnsureMonitoring", rf, mock.Anything, mock.Anything).Once().Return(notInstalled)
+	mk.On("RecordEvent", rf, corev1.EventTypeWarning, "MonitoringNotInstalled", "Unable to create ServiceMonitor rfr-test: the prometheus-operator CRDs are not installed").Times(3)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+	ensure := func() error {
+		return handler.Ensure(rf, map[string]string{}, []metav1.OwnerReference{}, metrics.Dummy)
+	}
+
+	// The rest of the RedisFailover is ensured anyway
+	assert.NoError(ensure())
+	// The missing CRDs of the same spec are not recorded again
+	assert.NoError(ensure())
+	mk.AssertNumberOfCalls(t, "RecordEvent", 1)
+
+	// But they are once it changes
+	rf.Generation = 2
+	assert.NoError(ensure())
+	mk.AssertNumberOfCalls(t, "RecordEvent", 2)
+
+	// Or once they are missing again after being installed
+	assert.NoError(ensure())
+	assert.NoError(ensure())
+
+	mk.AssertExpectations(t)
+	mrfs.AssertExpectations(t)
+}
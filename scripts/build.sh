@@ -5,6 +5,8 @@ set -o nounset
 
 src=./cmd/redisoperator
 out=./bin/redis-operator
+plugin_src=./cmd/kubectl-rf
+plugin_out=./bin/kubectl-rf
 
 if [[ ! -z ${TARGETOS} ]] && [[ ! -z ${TARGETARCH} ]];
 then
@@ -22,3 +24,6 @@ f_ver="-X main.Version=${VERSION:-dev}"
 
 echo "Building binary at ${out}"
 CGO_ENABLED=0 go build -o ${out} --ldflags "${ldf_cmp} ${f_ver}"  ${src}
+
+echo "Building kubectl plugin at ${plugin_out}"
+CGO_ENABLED=0 go build -o ${plugin_out} --ldflags "${ldf_cmp} ${f_ver}"  ${plugin_src}
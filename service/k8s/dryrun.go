@@ -0,0 +1,502 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// Actions of the changes planned in dry run mode
+const (
+	DryRunCreate = "create"
+	DryRunUpdate = "update"
+	DryRunDelete = "delete"
+)
+
+// redisFailoverNameLabel is the label the operator sets on the objects generated for a RedisFailover
+const redisFailoverNameLabel = "redisfailovers.databases.spotahome.com/name"
+
+// dryRunServices are the services of a dry run, they read the live objects but don't apply any change
+type dryRunServices struct {
+	Services
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewDryRun returns the given services with every change to the cluster replaced by its plan. The change
+// is logged, with the fields that differ from the live object, and recorded on the metrics, but not applied.
+func NewDryRun(services Services, logger log.Logger, metricsRecorder metrics.Recorder) Services {
+	return &dryRunServices{
+		Services:        services,
+		logger:          logger.With("service", "k8s.dryrun"),
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// plan logs and records a change that is not applied
+func (d *dryRunServices) plan(namespace, resource, kind, name, action string, fields []string) {
+	logger := d.logger.WithFields(map[string]interface{}{
+		"namespace":     namespace,
+		"redisfailover": resource,
+		"kind":          kind,
+		"name":          name,
+		"action":        action,
+	})
+	if len(fields) > 0 {
+		logger = logger.WithField("fields", strings.Join(fields, ","))
+	}
+	logger.Info("Dry run, change not applied")
+	d.metricsRecorder.RecordDryRunChange(namespace, resource, kind, name, action)
+}
+
+// createOrUpdate plans the creation of the desired object when there is no live one, or its update when
+// the fields it sets differ from the live ones
+func (d *dryRunServices) createOrUpdate(namespace, kind string, desired, live metav1.Object, err error) error {
+	if errors.IsNotFound(err) {
+		d.plan(namespace, redisFailoverOf(desired), kind, desired.GetName(), DryRunCreate, nil)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fields, err := diffObjects(desired, live, "status")
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		d.plan(namespace, redisFailoverOf(desired), kind, desired.GetName(), DryRunUpdate, fields)
+	}
+	return nil
+}
+
+// delete plans the deletion of the live object, if any
+func (d *dryRunServices) delete(namespace, kind, name string, live metav1.Object, err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d.plan(namespace, redisFailoverOf(live), kind, name, DryRunDelete, nil)
+	return nil
+}
+
+// redisFailoverOf returns the name of the RedisFailover the object was generated for, empty when unknown
+func redisFailoverOf(object metav1.Object) string {
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.Kind == redisfailoverv1.RFKind {
+			return ref.Name
+		}
+	}
+	return object.GetLabels()[redisFailoverNameLabel]
+}
+
+// diffObjects returns the paths of the fields set on the desired object whose value is different on the
+// live one. The fields only set on the live object, like the ones defaulted by kubernetes, are ignored,
+// as are the given top level fields.
+func diffObjects(desired, live interface{}, ignore ...string) ([]string, error) {
+	desiredFields, err := toFields(desired)
+	if err != nil {
+		return nil, err
+	}
+	liveFields, err := toFields(live)
+	if err != nil {
+		return nil, err
+	}
+	if desiredMap, ok := desiredFields.(map[string]interface{}); ok {
+		for _, field := range ignore {
+			delete(desiredMap, field)
+		}
+	}
+	diff := []string{}
+	diffFields("", desiredFields, liveFields, &diff)
+	sort.Strings(diff)
+	return diff, nil
+}
+
+func toFields(object interface{}) (interface{}, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var fields interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func diffFields(path string, desired, live interface{}, diff *[]string) {
+	switch desired := desired.(type) {
+	case nil:
+		// Not set on the desired object
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			*diff = append(*diff, path)
+			return
+		}
+		for key, value := range desired {
+			diffFields(joinFieldPath(path, key), value, liveMap[key], diff)
+		}
+	case []interface{}:
+		liveList, ok := live.([]interface{})
+		if !ok || len(liveList) != len(desired) {
+			*diff = append(*diff, path)
+			return
+		}
+		for i := range desired {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), desired[i], liveList[i], diff)
+		}
+	default:
+		if !reflect.DeepEqual(desired, live) {
+			*diff = append(*diff, path)
+		}
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// ConfigMap
+
+func (d *dryRunServices) CreateConfigMap(namespace string, configMap *corev1.ConfigMap) error {
+	d.plan(namespace, redisFailoverOf(configMap), "ConfigMap", configMap.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdateConfigMap(namespace string, configMap *corev1.ConfigMap) error {
+	return d.CreateOrUpdateConfigMap(namespace, configMap)
+}
+
+func (d *dryRunServices) CreateOrUpdateConfigMap(namespace string, configMap *corev1.ConfigMap) error {
+	live, err := d.GetConfigMap(namespace, configMap.Name)
+	return d.createOrUpdate(namespace, "ConfigMap", configMap, live, err)
+}
+
+func (d *dryRunServices) DeleteConfigMap(namespace string, name string) error {
+	live, err := d.GetConfigMap(namespace, name)
+	return d.delete(namespace, "ConfigMap", name, live, err)
+}
+
+// Deployment
+
+func (d *dryRunServices) CreateDeployment(namespace string, deployment *appsv1.Deployment) error {
+	d.plan(namespace, redisFailoverOf(deployment), "Deployment", deployment.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdateDeployment(namespace string, deployment *appsv1.Deployment) error {
+	return d.CreateOrUpdateDeployment(namespace, deployment)
+}
+
+func (d *dryRunServices) CreateOrUpdateDeployment(namespace string, deployment *appsv1.Deployment) error {
+	live, err := d.GetDeployment(namespace, deployment.Name)
+	return d.createOrUpdate(namespace, "Deployment", deployment, live, err)
+}
+
+func (d *dryRunServices) DeleteDeployment(namespace string, name string) error {
+	live, err := d.GetDeployment(namespace, name)
+	return d.delete(namespace, "Deployment", name, live, err)
+}
+
+// Pod
+
+func (d *dryRunServices) CreatePod(namespace string, pod *corev1.Pod) error {
+	d.plan(namespace, redisFailoverOf(pod), "Pod", pod.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdatePod(namespace string, pod *corev1.Pod) error {
+	return d.CreateOrUpdatePod(namespace, pod)
+}
+
+func (d *dryRunServices) CreateOrUpdatePod(namespace string, pod *corev1.Pod) error {
+	live, err := d.GetPod(namespace, pod.Name)
+	return d.createOrUpdate(namespace, "Pod", pod, live, err)
+}
+
+func (d *dryRunServices) DeletePod(namespace string, name string) error {
+	live, err := d.GetPod(namespace, name)
+	return d.delete(namespace, "Pod", name, live, err)
+}
+
+func (d *dryRunServices) UpdatePodLabels(namespace, podName string, labels map[string]string) error {
+	live, err := d.GetPod(namespace, podName)
+	if err != nil {
+		return err
+	}
+	fields := []string{}
+	for key, value := range labels {
+		if current, ok := live.Labels[key]; !ok || current != value {
+			fields = append(fields, joinFieldPath("metadata.labels", key))
+		}
+	}
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		d.plan(namespace, redisFailoverOf(live), "Pod", podName, DryRunUpdate, fields)
+	}
+	return nil
+}
+
+func (d *dryRunServices) UpdatePodAnnotations(namespace, podName string, annotations map[string]string) error {
+	live, err := d.GetPod(namespace, podName)
+	if err != nil {
+		return err
+	}
+	fields := []string{}
+	for key, value := range annotations {
+		if current, ok := live.Annotations[key]; !ok || current != value {
+			fields = append(fields, joinFieldPath("metadata.annotations", key))
+		}
+	}
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		d.plan(namespace, redisFailoverOf(live), "Pod", podName, DryRunUpdate, fields)
+	}
+	return nil
+}
+
+// PodDisruptionBudget
+
+func (d *dryRunServices) CreatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	d.plan(namespace, redisFailoverOf(podDisruptionBudget), "PodDisruptionBudget", podDisruptionBudget.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	return d.CreateOrUpdatePodDisruptionBudget(namespace, podDisruptionBudget)
+}
+
+func (d *dryRunServices) CreateOrUpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	live, err := d.GetPodDisruptionBudget(namespace, podDisruptionBudget.Name)
+	return d.createOrUpdate(namespace, "PodDisruptionBudget", podDisruptionBudget, live, err)
+}
+
+func (d *dryRunServices) DeletePodDisruptionBudget(namespace string, name string) error {
+	live, err := d.GetPodDisruptionBudget(namespace, name)
+	return d.delete(namespace, "PodDisruptionBudget", name, live, err)
+}
+
+// NetworkPolicy
+
+func (d *dryRunServices) CreateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	d.plan(namespace, redisFailoverOf(networkPolicy), "NetworkPolicy", networkPolicy.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	return d.CreateOrUpdateNetworkPolicy(namespace, networkPolicy)
+}
+
+func (d *dryRunServices) CreateOrUpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	live, err := d.GetNetworkPolicy(namespace, networkPolicy.Name)
+	return d.createOrUpdate(namespace, "NetworkPolicy", networkPolicy, live, err)
+}
+
+func (d *dryRunServices) DeleteNetworkPolicy(namespace string, name string) error {
+	live, err := d.GetNetworkPolicy(namespace, name)
+	return d.delete(namespace, "NetworkPolicy", name, live, err)
+}
+
+// RedisFailover
+
+func (d *dryRunServices) UpdateRedisFailover(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	live, err := d.GetRedisFailover(ctx, rf.Namespace, rf.Name)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := diffObjects(rf, live, "spec", "status")
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		d.plan(rf.Namespace, rf.Name, redisfailoverv1.RFKind, rf.Name, DryRunUpdate, fields)
+	}
+	return rf, nil
+}
+
+func (d *dryRunServices) UpdateRedisFailoverStatus(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	live, err := d.GetRedisFailover(ctx, rf.Namespace, rf.Name)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := diffObjects(map[string]interface{}{"status": rf.Status}, map[string]interface{}{"status": live.Status})
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		d.plan(rf.Namespace, rf.Name, redisfailoverv1.RFKind, rf.Name, DryRunUpdate, fields)
+	}
+	return rf, nil
+}
+
+// RedisFailoverSnapshot
+
+func (d *dryRunServices) UpdateRedisFailoverSnapshotStatus(ctx context.Context, rfs *redisfailoverv1.RedisFailoverSnapshot) (*redisfailoverv1.RedisFailoverSnapshot, error) {
+	live, err := d.GetRedisFailoverSnapshot(ctx, rfs.Namespace, rfs.Name)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := diffObjects(map[string]interface{}{"status": rfs.Status}, map[string]interface{}{"status": live.Status})
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		d.plan(rfs.Namespace, rfs.Spec.RedisFailoverName, redisfailoverv1.RFSnapshotKind, rfs.Name, DryRunUpdate, fields)
+	}
+	return rfs, nil
+}
+
+// Service
+
+func (d *dryRunServices) CreateService(namespace string, service *corev1.Service) error {
+	d.plan(namespace, redisFailoverOf(service), "Service", service.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) CreateIfNotExistsService(namespace string, service *corev1.Service) error {
+	if _, err := d.GetService(namespace, service.Name); !errors.IsNotFound(err) {
+		return err
+	}
+	return d.CreateService(namespace, service)
+}
+
+func (d *dryRunServices) UpdateService(namespace string, service *corev1.Service) error {
+	return d.CreateOrUpdateService(namespace, service)
+}
+
+func (d *dryRunServices) CreateOrUpdateService(namespace string, service *corev1.Service) error {
+	live, err := d.GetService(namespace, service.Name)
+	return d.createOrUpdate(namespace, "Service", service, live, err)
+}
+
+func (d *dryRunServices) DeleteService(namespace string, name string) error {
+	live, err := d.GetService(namespace, name)
+	return d.delete(namespace, "Service", name, live, err)
+}
+
+// RBAC
+
+func (d *dryRunServices) CreateRole(namespace string, role *rbacv1.Role) error {
+	d.plan(namespace, redisFailoverOf(role), "Role", role.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdateRole(namespace string, role *rbacv1.Role) error {
+	return d.CreateOrUpdateRole(namespace, role)
+}
+
+func (d *dryRunServices) CreateOrUpdateRole(namespace string, role *rbacv1.Role) error {
+	live, err := d.GetRole(namespace, role.Name)
+	return d.createOrUpdate(namespace, "Role", role, live, err)
+}
+
+func (d *dryRunServices) CreateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
+	d.plan(namespace, redisFailoverOf(binding), "RoleBinding", binding.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
+	return d.CreateOrUpdateRoleBinding(namespace, binding)
+}
+
+func (d *dryRunServices) CreateOrUpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
+	live, err := d.GetRoleBinding(namespace, binding.Name)
+	return d.createOrUpdate(namespace, "RoleBinding", binding, live, err)
+}
+
+// StatefulSet
+
+func (d *dryRunServices) CreateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
+	d.plan(namespace, redisFailoverOf(statefulSet), "StatefulSet", statefulSet.Name, DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) UpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
+	return d.CreateOrUpdateStatefulSet(namespace, statefulSet)
+}
+
+func (d *dryRunServices) CreateOrUpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
+	live, err := d.GetStatefulSet(namespace, statefulSet.Name)
+	if err == nil {
+		// The volume claim templates are never updated, see CreateOrUpdateStatefulSet
+		statefulSet = statefulSet.DeepCopy()
+		statefulSet.Spec.VolumeClaimTemplates = live.Spec.VolumeClaimTemplates
+	}
+	return d.createOrUpdate(namespace, "StatefulSet", statefulSet, live, err)
+}
+
+func (d *dryRunServices) DeleteStatefulSet(namespace string, name string) error {
+	live, err := d.GetStatefulSet(namespace, name)
+	return d.delete(namespace, "StatefulSet", name, live, err)
+}
+
+// PersistentVolumeClaim
+
+func (d *dryRunServices) UpdatePersistentVolumeClaim(namespace string, pvc *corev1.PersistentVolumeClaim) error {
+	live, err := d.GetPersistentVolumeClaim(namespace, pvc.Name)
+	return d.createOrUpdate(namespace, "PersistentVolumeClaim", pvc, live, err)
+}
+
+func (d *dryRunServices) DeletePersistentVolumeClaim(namespace, name string) error {
+	live, err := d.GetPersistentVolumeClaim(namespace, name)
+	return d.delete(namespace, "PersistentVolumeClaim", name, live, err)
+}
+
+// VolumeSnapshot
+
+func (d *dryRunServices) CreateVolumeSnapshot(namespace string, volumeSnapshot *unstructured.Unstructured) error {
+	d.plan(namespace, redisFailoverOf(volumeSnapshot), "VolumeSnapshot", volumeSnapshot.GetName(), DryRunCreate, nil)
+	return nil
+}
+
+func (d *dryRunServices) DeleteVolumeSnapshot(namespace, name string) error {
+	live, err := d.GetVolumeSnapshot(namespace, name)
+	return d.delete(namespace, "VolumeSnapshot", name, live, err)
+}
+
+// Monitoring
+
+func (d *dryRunServices) CreateOrUpdateMonitoringObject(resource schema.GroupVersionResource, namespace string, object *unstructured.Unstructured) error {
+	live, err := d.GetMonitoringObject(resource, namespace, object.GetName())
+	return d.createOrUpdate(namespace, monitoringKind(resource), object, live, err)
+}
+
+func (d *dryRunServices) DeleteMonitoringObject(resource schema.GroupVersionResource, namespace, name string) error {
+	live, err := d.GetMonitoringObject(resource, namespace, name)
+	return d.delete(namespace, monitoringKind(resource), name, live, err)
+}
+
+// Event
+
+func (d *dryRunServices) RecordEvent(object runtime.Object, eventType, reason, message string) {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return
+	}
+	d.plan(accessor.GetNamespace(), accessor.GetName(), "Event", reason, DryRunCreate, []string{fmt.Sprintf("%s: %s", eventType, message)})
+}
@@ -0,0 +1,169 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// dryRunRecorder keeps the dry run changes recorded on the metrics
+type dryRunRecorder struct {
+	metrics.Recorder
+	changes []string
+}
+
+func (d *dryRunRecorder) RecordDryRunChange(namespace, resource, kind, object, action string) {
+	d.changes = append(d.changes, namespace+"/"+resource+" "+action+" "+kind+" "+object)
+}
+
+func TestDryRunDoesNotApplyChanges(t *testing.T) {
+	owner := []metav1.OwnerReference{{Kind: redisfailoverv1.RFKind, Name: "test"}}
+	liveConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Namespace: "testns", OwnerReferences: owner, ResourceVersion: "10"},
+		Data:       map[string]string{"redis.conf": "maxmemory 1gb"},
+	}
+	liveStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Namespace: "testns", OwnerReferences: owner},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: int32Pointer(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:                    []corev1.Container{{Name: "redis", Image: "redis:6"}},
+					TerminationGracePeriodSeconds: int64Pointer(30),
+				},
+			},
+		},
+	}
+	livePod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0", Namespace: "testns", Labels: map[string]string{"redisfailovers.databases.spotahome.com/name": "test"}},
+	}
+	rf := &redisfailoverv1.RedisFailover{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"}}
+
+	tests := []struct {
+		name       string
+		apply      func(services k8s.Services) error
+		expChanges []string
+	}{
+		{
+			name: "A missing object should be planned as created.",
+			apply: func(services k8s.Services) error {
+				return services.CreateOrUpdateConfigMap("testns", &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "rfs-test", Namespace: "testns", OwnerReferences: owner},
+				})
+			},
+			expChanges: []string{"testns/test create ConfigMap rfs-test"},
+		},
+		{
+			name: "A changed object should be planned as updated.",
+			apply: func(services k8s.Services) error {
+				return services.CreateOrUpdateConfigMap("testns", &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Namespace: "testns", OwnerReferences: owner},
+					Data:       map[string]string{"redis.conf": "maxmemory 2gb"},
+				})
+			},
+			expChanges: []string{"testns/test update ConfigMap rfr-test"},
+		},
+		{
+			name: "An object without changes should not be planned.",
+			apply: func(services k8s.Services) error {
+				return services.CreateOrUpdateConfigMap("testns", &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Namespace: "testns", OwnerReferences: owner},
+					Data:       map[string]string{"redis.conf": "maxmemory 1gb"},
+				})
+			},
+		},
+		{
+			name: "The fields defaulted on the live object should be ignored.",
+			apply: func(services k8s.Services) error {
+				return services.CreateOrUpdateStatefulSet("testns", &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Namespace: "testns", OwnerReferences: owner},
+					Spec: appsv1.StatefulSetSpec{
+						Replicas: int32Pointer(3),
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "redis", Image: "redis:6"}}},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "The deletion of an object should be planned.",
+			apply: func(services k8s.Services) error {
+				return services.DeleteStatefulSet("testns", "rfr-test")
+			},
+			expChanges: []string{"testns/test delete StatefulSet rfr-test"},
+		},
+		{
+			name: "The deletion of a missing object should not be planned.",
+			apply: func(services k8s.Services) error {
+				return services.DeletePod("testns", "rfr-test-1")
+			},
+		},
+		{
+			name: "The changed labels of a pod should be planned as updated.",
+			apply: func(services k8s.Services) error {
+				return services.UpdatePodLabels("testns", "rfr-test-0", map[string]string{"redisfailovers-role": "master"})
+			},
+			expChanges: []string{"testns/test update Pod rfr-test-0"},
+		},
+		{
+			name: "The changed annotations of a pod should be planned as updated.",
+			apply: func(services k8s.Services) error {
+				return services.UpdatePodAnnotations("testns", "rfr-test-0", map[string]string{"test": "value"})
+			},
+			expChanges: []string{"testns/test update Pod rfr-test-0"},
+		},
+		{
+			name: "A changed status of a RedisFailover should be planned as updated.",
+			apply: func(services k8s.Services) error {
+				rf := rf.DeepCopy()
+				rf.Status.Paused = true
+				_, err := services.UpdateRedisFailoverStatus(context.TODO(), rf)
+				return err
+			},
+			expChanges: []string{"testns/test update RedisFailover test"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			objects := []runtime.Object{liveConfigMap.DeepCopy(), liveStatefulSet.DeepCopy(), livePod.DeepCopy()}
+			kcli := kubernetes.NewSimpleClientset(objects...)
+			rfcli := redisfailoverfake.NewSimpleClientset(rf.DeepCopy())
+			recorder := &dryRunRecorder{Recorder: metrics.Dummy}
+			services := k8s.NewDryRun(k8s.New(kcli, rfcli, nil, nil, log.Dummy, metrics.Dummy), log.Dummy, recorder)
+
+			require.NoError(test.apply(services))
+			assert.Equal(test.expChanges, recorder.changes)
+
+			// Nothing is written to the cluster.
+			for _, action := range append(kcli.Actions(), rfcli.Actions()...) {
+				assert.Contains([]string{"get", "list", "watch"}, action.GetVerb())
+			}
+		})
+	}
+}
+
+func int32Pointer(i int32) *int32 {
+	return &i
+}
+
+func int64Pointer(i int64) *int64 {
+	return &i
+}
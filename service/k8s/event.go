@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/spotahome/redis-operator/client/k8s/clientset/versioned/scheme"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+const eventComponent = "redis-operator"
+
+// Event the Event service that knows how to record events of the RedisFailovers on k8s
+type Event interface {
+	RecordEvent(object runtime.Object, eventType, reason, message string)
+}
+
+// EventService is the Event service implementation using API calls to kubernetes.
+type EventService struct {
+	recorder        record.EventRecorder
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewEventService returns a new Event KubeService. The events are sent to kubernetes in background,
+// similar events being aggregated.
+func NewEventService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *EventService {
+	logger = logger.With("service", "k8s.event")
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return &EventService{
+		recorder:        broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: eventComponent}),
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// RecordEvent records an event of the given object, being eventType corev1.EventTypeNormal or corev1.EventTypeWarning
+func (e *EventService) RecordEvent(object runtime.Object, eventType, reason, message string) {
+	e.logger.Debugf("recording %s event %s: %s", eventType, reason, message)
+	e.recorder.Event(object, eventType, reason, message)
+}
@@ -0,0 +1,47 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestEventServiceRecordEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	mcli := kubernetes.NewSimpleClientset()
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "testns",
+			UID:       "1",
+		},
+	}
+
+	service := k8s.NewEventService(mcli, log.Dummy, metrics.Dummy)
+	service.RecordEvent(rf, corev1.EventTypeWarning, "Testing", "testing event")
+
+	var events *corev1.EventList
+	assert.Eventually(func() bool {
+		events, _ = mcli.CoreV1().Events("testns").List(context.TODO(), metav1.ListOptions{})
+		return len(events.Items) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	event := events.Items[0]
+	assert.Equal(corev1.EventTypeWarning, event.Type)
+	assert.Equal("Testing", event.Reason)
+	assert.Equal("testing event", event.Message)
+	assert.Equal("RedisFailover", event.InvolvedObject.Kind)
+	assert.Equal("test", event.InvolvedObject.Name)
+	assert.Equal("redis-operator", event.Source.Component)
+}
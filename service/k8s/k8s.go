@@ -2,6 +2,7 @@ package k8s
 
 import (
 	apiextensionscli "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	redisfailoverclientset "github.com/spotahome/redis-operator/client/k8s/clientset/versioned"
@@ -15,11 +16,18 @@ type Services interface {
 	Secret
 	Pod
 	PodDisruptionBudget
+	NetworkPolicy
 	RedisFailover
+	RedisFailoverSnapshot
 	Service
 	RBAC
 	Deployment
 	StatefulSet
+	PersistentVolumeClaim
+	VolumeSnapshot
+	Monitoring
+	StorageClass
+	Event
 }
 
 type services struct {
@@ -27,24 +35,38 @@ type services struct {
 	Secret
 	Pod
 	PodDisruptionBudget
+	NetworkPolicy
 	RedisFailover
+	RedisFailoverSnapshot
 	Service
 	RBAC
 	Deployment
 	StatefulSet
+	PersistentVolumeClaim
+	VolumeSnapshot
+	Monitoring
+	StorageClass
+	Event
 }
 
 // New returns a new Kubernetes service.
-func New(kubecli kubernetes.Interface, crdcli redisfailoverclientset.Interface, apiextcli apiextensionscli.Interface, logger log.Logger, metricsRecorder metrics.Recorder) Services {
+func New(kubecli kubernetes.Interface, crdcli redisfailoverclientset.Interface, apiextcli apiextensionscli.Interface, dyncli dynamic.Interface, logger log.Logger, metricsRecorder metrics.Recorder) Services {
 	return &services{
-		ConfigMap:           NewConfigMapService(kubecli, logger, metricsRecorder),
-		Secret:              NewSecretService(kubecli, logger, metricsRecorder),
-		Pod:                 NewPodService(kubecli, logger, metricsRecorder),
-		PodDisruptionBudget: NewPodDisruptionBudgetService(kubecli, logger, metricsRecorder),
-		RedisFailover:       NewRedisFailoverService(crdcli, logger, metricsRecorder),
-		Service:             NewServiceService(kubecli, logger, metricsRecorder),
-		RBAC:                NewRBACService(kubecli, logger, metricsRecorder),
-		Deployment:          NewDeploymentService(kubecli, logger, metricsRecorder),
-		StatefulSet:         NewStatefulSetService(kubecli, logger, metricsRecorder),
+		ConfigMap:             NewConfigMapService(kubecli, logger, metricsRecorder),
+		Secret:                NewSecretService(kubecli, logger, metricsRecorder),
+		Pod:                   NewPodService(kubecli, logger, metricsRecorder),
+		PodDisruptionBudget:   NewPodDisruptionBudgetService(kubecli, logger, metricsRecorder),
+		NetworkPolicy:         NewNetworkPolicyService(kubecli, logger, metricsRecorder),
+		RedisFailover:         NewRedisFailoverService(crdcli, logger, metricsRecorder),
+		RedisFailoverSnapshot: NewRedisFailoverSnapshotService(crdcli, logger, metricsRecorder),
+		Service:               NewServiceService(kubecli, logger, metricsRecorder),
+		RBAC:                  NewRBACService(kubecli, logger, metricsRecorder),
+		Deployment:            NewDeploymentService(kubecli, logger, metricsRecorder),
+		StatefulSet:           NewStatefulSetService(kubecli, logger, metricsRecorder),
+		PersistentVolumeClaim: NewPersistentVolumeClaimService(kubecli, logger, metricsRecorder),
+		VolumeSnapshot:        NewVolumeSnapshotService(dyncli, logger, metricsRecorder),
+		Monitoring:            NewMonitoringService(dyncli, logger, metricsRecorder),
+		StorageClass:          NewStorageClassService(kubecli, logger, metricsRecorder),
+		Event:                 NewEventService(kubecli, logger, metricsRecorder),
 	}
 }
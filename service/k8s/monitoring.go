@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// Resources of the prometheus-operator objects
+var (
+	ServiceMonitorGroupVersionResource = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "servicemonitors",
+	}
+	PodMonitorGroupVersionResource = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "podmonitors",
+	}
+	PrometheusRuleGroupVersionResource = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "prometheusrules",
+	}
+)
+
+// Monitoring the service that knows how to interact with k8s to manage the prometheus-operator objects:
+// ServiceMonitors, PodMonitors and PrometheusRules. They are handled as unstructured objects, so the
+// prometheus-operator CRDs are only required when they are used.
+type Monitoring interface {
+	GetMonitoringObject(resource schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error)
+	CreateOrUpdateMonitoringObject(resource schema.GroupVersionResource, namespace string, object *unstructured.Unstructured) error
+	DeleteMonitoringObject(resource schema.GroupVersionResource, namespace, name string) error
+}
+
+// MonitoringService is the Monitoring service implementation using API calls to kubernetes.
+type MonitoringService struct {
+	dynamicClient   dynamic.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewMonitoringService returns a new Monitoring KubeService.
+func NewMonitoringService(dynamicClient dynamic.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *MonitoringService {
+	logger = logger.With("service", "k8s.monitoring")
+	return &MonitoringService{
+		dynamicClient:   dynamicClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetMonitoringObject will retrieve the requested object based on its resource, namespace and name
+func (m *MonitoringService) GetMonitoringObject(resource schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	object, err := m.dynamicClient.Resource(resource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(namespace, monitoringKind(resource), name, "GET", err, m.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// CreateOrUpdateMonitoringObject will create the given object or update the stored one
+func (m *MonitoringService) CreateOrUpdateMonitoringObject(resource schema.GroupVersionResource, namespace string, object *unstructured.Unstructured) error {
+	kind := monitoringKind(resource)
+	stored, err := m.GetMonitoringObject(resource, namespace, object.GetName())
+	if errors.IsNotFound(err) {
+		_, err = m.dynamicClient.Resource(resource).Namespace(namespace).Create(context.TODO(), object, metav1.CreateOptions{})
+		recordMetrics(namespace, kind, object.GetName(), "CREATE", err, m.metricsRecorder)
+		if err != nil {
+			return err
+		}
+		m.logger.WithField("namespace", namespace).WithField(kind, object.GetName()).Debugf("%s created", kind)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Already exists, need to Update.
+	// Set the correct resource version to ensure we are on the latest version. This way the only valid
+	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
+	// we will replace the current namespace state.
+	object.SetResourceVersion(stored.GetResourceVersion())
+	_, err = m.dynamicClient.Resource(resource).Namespace(namespace).Update(context.TODO(), object, metav1.UpdateOptions{})
+	recordMetrics(namespace, kind, object.GetName(), "UPDATE", err, m.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	m.logger.WithField("namespace", namespace).WithField(kind, object.GetName()).Debugf("%s updated", kind)
+	return nil
+}
+
+// DeleteMonitoringObject will delete the given object
+func (m *MonitoringService) DeleteMonitoringObject(resource schema.GroupVersionResource, namespace, name string) error {
+	err := m.dynamicClient.Resource(resource).Namespace(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	recordMetrics(namespace, monitoringKind(resource), name, "DELETE", err, m.metricsRecorder)
+	return err
+}
+
+// IsMonitoringNotInstalled tells if the error of CreateOrUpdateMonitoringObject is the one returned when the
+// resource isn't served, as the prometheus-operator CRDs are not installed. The object not being found
+// makes it be created, so a not found error can only come from the missing resource.
+func IsMonitoringNotInstalled(err error) bool {
+	return meta.IsNoMatchError(err) || errors.IsNotFound(err)
+}
+
+// monitoringKind returns the kind of the objects of the given resource
+func monitoringKind(resource schema.GroupVersionResource) string {
+	switch resource {
+	case ServiceMonitorGroupVersionResource:
+		return "ServiceMonitor"
+	case PodMonitorGroupVersionResource:
+		return "PodMonitor"
+	case PrometheusRuleGroupVersionResource:
+		return "PrometheusRule"
+	}
+	return resource.Resource
+}
@@ -0,0 +1,52 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestMonitoringServiceCreateOrUpdate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	testns := "testns"
+	mcli := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		k8s.ServiceMonitorGroupVersionResource: "ServiceMonitorList",
+	})
+	service := k8s.NewMonitoringService(mcli, log.Dummy, metrics.Dummy)
+
+	serviceMonitor := func(interval string) *unstructured.Unstructured {
+		monitor := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"endpoints": []interface{}{map[string]interface{}{"port": "http-metrics", "interval": interval}},
+			},
+		}}
+		monitor.SetAPIVersion("monitoring.coreos.com/v1")
+		monitor.SetKind("ServiceMonitor")
+		monitor.SetNamespace(testns)
+		monitor.SetName("rfr-test")
+		return monitor
+	}
+
+	require.NoError(service.CreateOrUpdateMonitoringObject(k8s.ServiceMonitorGroupVersionResource, testns, serviceMonitor("30s")))
+	require.NoError(service.CreateOrUpdateMonitoringObject(k8s.ServiceMonitorGroupVersionResource, testns, serviceMonitor("10s")))
+
+	stored, err := service.GetMonitoringObject(k8s.ServiceMonitorGroupVersionResource, testns, "rfr-test")
+	require.NoError(err)
+	endpoints, _, _ := unstructured.NestedSlice(stored.Object, "spec", "endpoints")
+	assert.Equal([]interface{}{map[string]interface{}{"port": "http-metrics", "interval": "10s"}}, endpoints)
+
+	assert.NoError(service.DeleteMonitoringObject(k8s.ServiceMonitorGroupVersionResource, testns, "rfr-test"))
+	_, err = service.GetMonitoringObject(k8s.ServiceMonitorGroupVersionResource, testns, "rfr-test")
+	assert.Error(err)
+}
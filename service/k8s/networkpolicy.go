@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// NetworkPolicy the NetworkPolicy service that knows how to interact with k8s to manage them
+type NetworkPolicy interface {
+	GetNetworkPolicy(namespace string, name string) (*networkingv1.NetworkPolicy, error)
+	CreateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error
+	UpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error
+	CreateOrUpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error
+	DeleteNetworkPolicy(namespace string, name string) error
+}
+
+// NetworkPolicyService is the networkPolicy service implementation using API calls to kubernetes.
+type NetworkPolicyService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewNetworkPolicyService returns a new NetworkPolicy KubeService.
+func NewNetworkPolicyService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *NetworkPolicyService {
+	logger = logger.With("service", "k8s.networkPolicy")
+	return &NetworkPolicyService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+func (n *NetworkPolicyService) GetNetworkPolicy(namespace string, name string) (*networkingv1.NetworkPolicy, error) {
+	networkPolicy, err := n.kubeClient.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(namespace, "NetworkPolicy", name, "GET", err, n.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return networkPolicy, nil
+}
+
+func (n *NetworkPolicyService) CreateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	_, err := n.kubeClient.NetworkingV1().NetworkPolicies(namespace).Create(context.TODO(), networkPolicy, metav1.CreateOptions{})
+	recordMetrics(namespace, "NetworkPolicy", networkPolicy.GetName(), "CREATE", err, n.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	n.logger.WithField("namespace", namespace).WithField("networkPolicy", networkPolicy.Name).Debugf("networkPolicy created")
+	return nil
+}
+
+func (n *NetworkPolicyService) UpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	_, err := n.kubeClient.NetworkingV1().NetworkPolicies(namespace).Update(context.TODO(), networkPolicy, metav1.UpdateOptions{})
+	recordMetrics(namespace, "NetworkPolicy", networkPolicy.GetName(), "UPDATE", err, n.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	n.logger.WithField("namespace", namespace).WithField("networkPolicy", networkPolicy.Name).Debugf("networkPolicy updated")
+	return nil
+}
+
+func (n *NetworkPolicyService) CreateOrUpdateNetworkPolicy(namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	storedNetworkPolicy, err := n.GetNetworkPolicy(namespace, networkPolicy.Name)
+	if err != nil {
+		// If no resource we need to create.
+		if errors.IsNotFound(err) {
+			return n.CreateNetworkPolicy(namespace, networkPolicy)
+		}
+		return err
+	}
+
+	// Already exists, need to Update.
+	// Set the correct resource version to ensure we are on the latest version. This way the only valid
+	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
+	// we will replace the current namespace state.
+	networkPolicy.ResourceVersion = storedNetworkPolicy.ResourceVersion
+	return n.UpdateNetworkPolicy(namespace, networkPolicy)
+}
+
+func (n *NetworkPolicyService) DeleteNetworkPolicy(namespace string, name string) error {
+	err := n.kubeClient.NetworkingV1().NetworkPolicies(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	recordMetrics(namespace, "NetworkPolicy", name, "DELETE", err, n.metricsRecorder)
+	return err
+}
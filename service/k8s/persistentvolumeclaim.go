@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// PersistentVolumeClaim the PersistentVolumeClaim service that knows how to interact with k8s to manage them
+type PersistentVolumeClaim interface {
+	GetPersistentVolumeClaim(namespace, name string) (*corev1.PersistentVolumeClaim, error)
+	ListPersistentVolumeClaims(namespace string, selector map[string]string) (*corev1.PersistentVolumeClaimList, error)
+	UpdatePersistentVolumeClaim(namespace string, pvc *corev1.PersistentVolumeClaim) error
+	DeletePersistentVolumeClaim(namespace, name string) error
+}
+
+// PersistentVolumeClaimService is the PersistentVolumeClaim service implementation using API calls to kubernetes.
+type PersistentVolumeClaimService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewPersistentVolumeClaimService returns a new PersistentVolumeClaim KubeService.
+func NewPersistentVolumeClaimService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *PersistentVolumeClaimService {
+	logger = logger.With("service", "k8s.persistentVolumeClaim")
+	return &PersistentVolumeClaimService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetPersistentVolumeClaim will retrieve the requested persistent volume claim based on namespace and name
+func (p *PersistentVolumeClaimService) GetPersistentVolumeClaim(namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc, err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", name, "GET", err, p.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return pvc, nil
+}
+
+// ListPersistentVolumeClaims will retrieve the persistent volume claims of the namespace matching the selector
+func (p *PersistentVolumeClaimService) ListPersistentVolumeClaims(namespace string, selector map[string]string) (*corev1.PersistentVolumeClaimList, error) {
+	pvcs, err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labels.FormatLabels(selector)})
+	recordMetrics(namespace, "PersistentVolumeClaim", metrics.NOT_APPLICABLE, "LIST", err, p.metricsRecorder)
+	return pvcs, err
+}
+
+// UpdatePersistentVolumeClaim will update the given persistent volume claim
+func (p *PersistentVolumeClaimService) UpdatePersistentVolumeClaim(namespace string, pvc *corev1.PersistentVolumeClaim) error {
+	_, err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Update(context.TODO(), pvc, metav1.UpdateOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", pvc.GetName(), "UPDATE", err, p.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	p.logger.WithField("namespace", namespace).WithField("persistentVolumeClaim", pvc.Name).Debugf("persistentVolumeClaim updated")
+	return nil
+}
+
+// DeletePersistentVolumeClaim will delete the given persistent volume claim
+func (p *PersistentVolumeClaimService) DeletePersistentVolumeClaim(namespace, name string) error {
+	err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", name, "DELETE", err, p.metricsRecorder)
+	return err
+}
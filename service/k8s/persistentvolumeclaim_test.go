@@ -0,0 +1,53 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestPersistentVolumeClaimServiceList(t *testing.T) {
+	assert := assert.New(t)
+
+	testns := "testns"
+	selector := map[string]string{
+		"app.kubernetes.io/component": "redis",
+		"app.kubernetes.io/name":      "test",
+	}
+	newPVC := func(name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testns,
+				Labels:    labels,
+			},
+		}
+	}
+
+	mcli := kubernetes.NewSimpleClientset(
+		newPVC("redis-data-rfr-test-0", selector),
+		newPVC("redis-data-rfr-test-1", map[string]string{"app.kubernetes.io/component": "redis", "app.kubernetes.io/name": "test", "extra": "label"}),
+		newPVC("redis-data-rfr-other-0", map[string]string{"app.kubernetes.io/component": "redis", "app.kubernetes.io/name": "other"}),
+	)
+
+	service := k8s.NewPersistentVolumeClaimService(mcli, log.Dummy, metrics.Dummy)
+	pvcs, err := service.ListPersistentVolumeClaims(testns, selector)
+	assert.NoError(err)
+
+	names := []string{}
+	for _, pvc := range pvcs.Items {
+		names = append(names, pvc.Name)
+	}
+	assert.ElementsMatch([]string{"redis-data-rfr-test-0", "redis-data-rfr-test-1"}, names)
+
+	assert.NoError(service.DeletePersistentVolumeClaim(testns, "redis-data-rfr-test-0"))
+	_, err = service.GetPersistentVolumeClaim(testns, "redis-data-rfr-test-0")
+	assert.Error(err)
+}
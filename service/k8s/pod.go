@@ -24,6 +24,7 @@ type Pod interface {
 	DeletePod(namespace string, name string) error
 	ListPods(namespace string) (*corev1.PodList, error)
 	UpdatePodLabels(namespace, podName string, labels map[string]string) error
+	UpdatePodAnnotations(namespace, podName string, annotations map[string]string) error
 }
 
 // PodService is the pod service implementation using API calls to kubernetes.
@@ -128,3 +129,20 @@ func (p *PodService) UpdatePodLabels(namespace, podName string, labels map[strin
 	}
 	return err
 }
+
+// UpdatePodAnnotations sets the given annotations on the pod, adding the missing ones and keeping the rest
+func (p *PodService) UpdatePodAnnotations(namespace, podName string, annotations map[string]string) error {
+	payload := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	_, err := p.kubeClient.CoreV1().Pods(namespace).Patch(context.TODO(), podName, types.MergePatchType, payloadBytes, metav1.PatchOptions{})
+	recordMetrics(namespace, "Pod", podName, "PATCH", err, p.metricsRecorder)
+	if err != nil {
+		p.logger.Errorf("Update pod annotations failed, namespace: %s, pod name: %s, error: %v", namespace, podName, err)
+	}
+	return err
+}
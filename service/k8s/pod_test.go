@@ -117,3 +117,24 @@ func TestPodServiceGetCreateOrUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestPodServiceUpdatePodAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testpod1",
+			Namespace:   "testns",
+			Annotations: map[string]string{"kept": "value"},
+		},
+	}
+	mcli := kubernetes.NewSimpleClientset(pod)
+	service := k8s.NewPodService(mcli, log.Dummy, metrics.Dummy)
+
+	assert.NoError(service.UpdatePodAnnotations("testns", "testpod1", map[string]string{"added": "value"}))
+	updated, err := service.GetPod("testns", "testpod1")
+	assert.NoError(err)
+	assert.Equal(map[string]string{"kept": "value", "added": "value"}, updated.Annotations)
+
+	assert.Error(service.UpdatePodAnnotations("testns", "missing", map[string]string{"added": "value"}))
+}
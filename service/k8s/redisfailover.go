@@ -14,10 +14,16 @@ import (
 
 // RedisFailover the RF service that knows how to interact with k8s to get them
 type RedisFailover interface {
+	// GetRedisFailover gets a redisfailover on a cluster.
+	GetRedisFailover(ctx context.Context, namespace, name string) (*redisfailoverv1.RedisFailover, error)
 	// ListRedisFailovers lists the redisfailovers on a cluster.
 	ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error)
 	// WatchRedisFailovers watches the redisfailovers on a cluster.
 	WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// UpdateRedisFailover updates a redisfailover on a cluster.
+	UpdateRedisFailover(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error)
+	// UpdateRedisFailoverStatus updates the status of a redisfailover on a cluster.
+	UpdateRedisFailoverStatus(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error)
 }
 
 // RedisFailoverService is the RedisFailover service implementation using API calls to kubernetes.
@@ -37,6 +43,13 @@ func NewRedisFailoverService(k8scli redisfailoverclientset.Interface, logger log
 	}
 }
 
+// GetRedisFailover satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) GetRedisFailover(ctx context.Context, namespace, name string) (*redisfailoverv1.RedisFailover, error) {
+	redisFailover, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisFailover", name, "GET", err, r.metricsRecorder)
+	return redisFailover, err
+}
+
 // ListRedisFailovers satisfies redisfailover.Service interface.
 func (r *RedisFailoverService) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
 	redisFailoverList, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).List(ctx, opts)
@@ -50,3 +63,17 @@ func (r *RedisFailoverService) WatchRedisFailovers(ctx context.Context, namespac
 	recordMetrics(namespace, "RedisFailover", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
 	return watcher, err
 }
+
+// UpdateRedisFailover satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) UpdateRedisFailover(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	redisFailover, err := r.k8sCli.DatabasesV1().RedisFailovers(rf.Namespace).Update(ctx, rf, metav1.UpdateOptions{})
+	recordMetrics(rf.Namespace, "RedisFailover", rf.Name, "UPDATE", err, r.metricsRecorder)
+	return redisFailover, err
+}
+
+// UpdateRedisFailoverStatus satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) UpdateRedisFailoverStatus(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*redisfailoverv1.RedisFailover, error) {
+	redisFailover, err := r.k8sCli.DatabasesV1().RedisFailovers(rf.Namespace).UpdateStatus(ctx, rf, metav1.UpdateOptions{})
+	recordMetrics(rf.Namespace, "RedisFailover", rf.Name, "UPDATE_STATUS", err, r.metricsRecorder)
+	return redisFailover, err
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverclientset "github.com/spotahome/redis-operator/client/k8s/clientset/versioned"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// RedisFailoverSnapshot the RF snapshot service that knows how to interact with k8s to get them
+type RedisFailoverSnapshot interface {
+	// GetRedisFailoverSnapshot gets a redisfailoversnapshot on a cluster.
+	GetRedisFailoverSnapshot(ctx context.Context, namespace, name string) (*redisfailoverv1.RedisFailoverSnapshot, error)
+	// ListRedisFailoverSnapshots lists the redisfailoversnapshots on a cluster.
+	ListRedisFailoverSnapshots(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverSnapshotList, error)
+	// WatchRedisFailoverSnapshots watches the redisfailoversnapshots on a cluster.
+	WatchRedisFailoverSnapshots(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// UpdateRedisFailoverSnapshotStatus updates the status of a redisfailoversnapshot on a cluster.
+	UpdateRedisFailoverSnapshotStatus(ctx context.Context, redisFailoverSnapshot *redisfailoverv1.RedisFailoverSnapshot) (*redisfailoverv1.RedisFailoverSnapshot, error)
+}
+
+// RedisFailoverSnapshotService is the RedisFailoverSnapshot service implementation using API calls to kubernetes.
+type RedisFailoverSnapshotService struct {
+	k8sCli          redisfailoverclientset.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewRedisFailoverSnapshotService returns a new RedisFailoverSnapshot KubeService.
+func NewRedisFailoverSnapshotService(k8scli redisfailoverclientset.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *RedisFailoverSnapshotService {
+	logger = logger.With("service", "k8s.redisfailoversnapshot")
+	return &RedisFailoverSnapshotService{
+		k8sCli:          k8scli,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetRedisFailoverSnapshot satisfies redisfailoversnapshot.Service interface.
+func (r *RedisFailoverSnapshotService) GetRedisFailoverSnapshot(ctx context.Context, namespace, name string) (*redisfailoverv1.RedisFailoverSnapshot, error) {
+	snapshot, err := r.k8sCli.DatabasesV1().RedisFailoverSnapshots(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisFailoverSnapshot", name, "GET", err, r.metricsRecorder)
+	return snapshot, err
+}
+
+// ListRedisFailoverSnapshots satisfies redisfailoversnapshot.Service interface.
+func (r *RedisFailoverSnapshotService) ListRedisFailoverSnapshots(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverSnapshotList, error) {
+	snapshotList, err := r.k8sCli.DatabasesV1().RedisFailoverSnapshots(namespace).List(ctx, opts)
+	recordMetrics(namespace, "RedisFailoverSnapshot", metrics.NOT_APPLICABLE, "LIST", err, r.metricsRecorder)
+	return snapshotList, err
+}
+
+// WatchRedisFailoverSnapshots satisfies redisfailoversnapshot.Service interface.
+func (r *RedisFailoverSnapshotService) WatchRedisFailoverSnapshots(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := r.k8sCli.DatabasesV1().RedisFailoverSnapshots(namespace).Watch(ctx, opts)
+	recordMetrics(namespace, "RedisFailoverSnapshot", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
+	return watcher, err
+}
+
+// UpdateRedisFailoverSnapshotStatus satisfies redisfailoversnapshot.Service interface.
+func (r *RedisFailoverSnapshotService) UpdateRedisFailoverSnapshotStatus(ctx context.Context, rfs *redisfailoverv1.RedisFailoverSnapshot) (*redisfailoverv1.RedisFailoverSnapshot, error) {
+	snapshot, err := r.k8sCli.DatabasesV1().RedisFailoverSnapshots(rfs.Namespace).UpdateStatus(ctx, rfs, metav1.UpdateOptions{})
+	recordMetrics(rfs.Namespace, "RedisFailoverSnapshot", rfs.Name, "UPDATE_STATUS", err, r.metricsRecorder)
+	return snapshot, err
+}
@@ -0,0 +1,39 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestRedisFailoverSnapshotServiceUpdateStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	testns := "testns"
+	mcli := redisfailoverfake.NewSimpleClientset(&redisfailoverv1.RedisFailoverSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: testns},
+		Spec:       redisfailoverv1.RedisFailoverSnapshotSpec{RedisFailoverName: "test"},
+	})
+
+	service := k8s.NewRedisFailoverSnapshotService(mcli, log.Dummy, metrics.Dummy)
+	snapshots, err := service.ListRedisFailoverSnapshots(context.TODO(), testns, metav1.ListOptions{})
+	assert.NoError(err)
+	assert.Len(snapshots.Items, 1)
+
+	snapshot := snapshots.Items[0].DeepCopy()
+	snapshot.Status.Phase = redisfailoverv1.RedisFailoverSnapshotPhaseReady
+	_, err = service.UpdateRedisFailoverSnapshotStatus(context.TODO(), snapshot)
+	assert.NoError(err)
+
+	snapshot, err = service.GetRedisFailoverSnapshot(context.TODO(), testns, "backup")
+	assert.NoError(err)
+	assert.Equal(redisfailoverv1.RedisFailoverSnapshotPhaseReady, snapshot.Status.Phase)
+}
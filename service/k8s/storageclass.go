@@ -0,0 +1,45 @@
+package k8s
+
+import (
+	"context"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// StorageClass the StorageClass service that knows how to interact with k8s to get them
+type StorageClass interface {
+	GetStorageClass(name string) (*storagev1.StorageClass, error)
+}
+
+// StorageClassService is the StorageClass service implementation using API calls to kubernetes.
+type StorageClassService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewStorageClassService returns a new StorageClass KubeService.
+func NewStorageClassService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *StorageClassService {
+	logger = logger.With("service", "k8s.storageClass")
+	return &StorageClassService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetStorageClass will retrieve the requested storage class based on name. Storage classes are not
+// namespaced.
+func (s *StorageClassService) GetStorageClass(name string) (*storagev1.StorageClass, error) {
+	storageClass, err := s.kubeClient.StorageV1().StorageClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(metrics.NOT_APPLICABLE, "StorageClass", name, "GET", err, s.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return storageClass, nil
+}
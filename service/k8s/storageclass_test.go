@@ -0,0 +1,32 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestStorageClassServiceGet(t *testing.T) {
+	assert := assert.New(t)
+
+	allowVolumeExpansion := true
+	mcli := kubernetes.NewSimpleClientset(&storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+		AllowVolumeExpansion: &allowVolumeExpansion,
+	})
+
+	service := k8s.NewStorageClassService(mcli, log.Dummy, metrics.Dummy)
+	storageClass, err := service.GetStorageClass("expandable")
+	assert.NoError(err)
+	assert.True(*storageClass.AllowVolumeExpansion)
+
+	_, err = service.GetStorageClass("missing")
+	assert.Error(err)
+}
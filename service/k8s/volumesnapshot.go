@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// VolumeSnapshotGroupVersionResource is the resource of the CSI VolumeSnapshots
+var VolumeSnapshotGroupVersionResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// VolumeSnapshot the VolumeSnapshot service that knows how to interact with k8s to manage them.
+// VolumeSnapshots are handled as unstructured objects, so the snapshot CRDs are only required
+// when they are used.
+type VolumeSnapshot interface {
+	GetVolumeSnapshot(namespace, name string) (*unstructured.Unstructured, error)
+	CreateVolumeSnapshot(namespace string, volumeSnapshot *unstructured.Unstructured) error
+	DeleteVolumeSnapshot(namespace, name string) error
+}
+
+// VolumeSnapshotService is the VolumeSnapshot service implementation using API calls to kubernetes.
+type VolumeSnapshotService struct {
+	dynamicClient   dynamic.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewVolumeSnapshotService returns a new VolumeSnapshot KubeService.
+func NewVolumeSnapshotService(dynamicClient dynamic.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *VolumeSnapshotService {
+	logger = logger.With("service", "k8s.volumeSnapshot")
+	return &VolumeSnapshotService{
+		dynamicClient:   dynamicClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetVolumeSnapshot will retrieve the requested volume snapshot based on namespace and name
+func (v *VolumeSnapshotService) GetVolumeSnapshot(namespace, name string) (*unstructured.Unstructured, error) {
+	volumeSnapshot, err := v.dynamicClient.Resource(VolumeSnapshotGroupVersionResource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(namespace, "VolumeSnapshot", name, "GET", err, v.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return volumeSnapshot, nil
+}
+
+// CreateVolumeSnapshot will create the given volume snapshot
+func (v *VolumeSnapshotService) CreateVolumeSnapshot(namespace string, volumeSnapshot *unstructured.Unstructured) error {
+	_, err := v.dynamicClient.Resource(VolumeSnapshotGroupVersionResource).Namespace(namespace).Create(context.TODO(), volumeSnapshot, metav1.CreateOptions{})
+	recordMetrics(namespace, "VolumeSnapshot", volumeSnapshot.GetName(), "CREATE", err, v.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	v.logger.WithField("namespace", namespace).WithField("volumeSnapshot", volumeSnapshot.GetName()).Debugf("volumeSnapshot created")
+	return nil
+}
+
+// DeleteVolumeSnapshot will delete the given volume snapshot
+func (v *VolumeSnapshotService) DeleteVolumeSnapshot(namespace, name string) error {
+	err := v.dynamicClient.Resource(VolumeSnapshotGroupVersionResource).Namespace(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	recordMetrics(namespace, "VolumeSnapshot", name, "DELETE", err, v.metricsRecorder)
+	return err
+}
+
+// NewVolumeSnapshotFromPVC returns a VolumeSnapshot of the given persistent volume claim. When
+// className is empty the default VolumeSnapshotClass of the cluster is used.
+func NewVolumeSnapshotFromPVC(namespace, name, pvcName, className string, labels map[string]string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+	if className != "" {
+		spec["volumeSnapshotClassName"] = className
+	}
+
+	volumeSnapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	volumeSnapshot.SetAPIVersion(VolumeSnapshotGroupVersionResource.GroupVersion().String())
+	volumeSnapshot.SetKind("VolumeSnapshot")
+	volumeSnapshot.SetNamespace(namespace)
+	volumeSnapshot.SetName(name)
+	volumeSnapshot.SetLabels(labels)
+	return volumeSnapshot
+}
@@ -0,0 +1,41 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestVolumeSnapshotServiceCreate(t *testing.T) {
+	assert := assert.New(t)
+
+	testns := "testns"
+	mcli := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		k8s.VolumeSnapshotGroupVersionResource: "VolumeSnapshotList",
+	})
+
+	service := k8s.NewVolumeSnapshotService(mcli, log.Dummy, metrics.Dummy)
+	snapshot := k8s.NewVolumeSnapshotFromPVC(testns, "redis-data-rfr-test-0-final", "redis-data-rfr-test-0", "csi-snapclass", map[string]string{"app.kubernetes.io/name": "test"})
+	assert.NoError(service.CreateVolumeSnapshot(testns, snapshot))
+
+	stored, err := service.GetVolumeSnapshot(testns, "redis-data-rfr-test-0-final")
+	assert.NoError(err)
+	assert.Equal("VolumeSnapshot", stored.GetKind())
+	assert.Equal(map[string]string{"app.kubernetes.io/name": "test"}, stored.GetLabels())
+	pvcName, _, _ := unstructured.NestedString(stored.Object, "spec", "source", "persistentVolumeClaimName")
+	assert.Equal("redis-data-rfr-test-0", pvcName)
+	className, _, _ := unstructured.NestedString(stored.Object, "spec", "volumeSnapshotClassName")
+	assert.Equal("csi-snapclass", className)
+
+	assert.NoError(service.DeleteVolumeSnapshot(testns, "redis-data-rfr-test-0-final"))
+	_, err = service.GetVolumeSnapshot(testns, "redis-data-rfr-test-0-final")
+	assert.Error(err)
+}
@@ -8,146 +8,261 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	rediscli "github.com/go-redis/redis/v8"
 	"github.com/spotahome/redis-operator/log"
 	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/redis/info"
 )
 
 // Client defines the functions neccesary to connect to redis and sentinel to get or set what we nned
 type Client interface {
-	GetNumberSentinelsInMemory(ip string) (int32, error)
-	GetNumberSentinelSlavesInMemory(ip string) (int32, error)
-	ResetSentinel(ip string) error
-	GetSlaveOf(ip, port, password string) (string, error)
-	IsMaster(ip, port, password string) (bool, error)
-	MonitorRedis(ip, monitor, quorum, password string) error
-	MonitorRedisWithPort(ip, monitor, port, quorum, password string) error
-	MakeMaster(ip, port, password string) error
-	MakeSlaveOf(ip, masterIP, password string) error
-	MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error
-	GetSentinelMonitor(ip string) (string, string, error)
-	SetCustomSentinelConfig(ip string, configs []string) error
-	SetCustomRedisConfig(ip string, port string, configs []string, password string) error
-	SlaveIsReady(ip, port, password string) (bool, error)
-	SentinelCheckQuorum(ip string) error
+	GetNumberSentinelsInMemory(ctx context.Context, ip string) (int32, error)
+	GetNumberSentinelSlavesInMemory(ctx context.Context, ip string) (int32, error)
+	GetSentinelMaster(ctx context.Context, ip string) (info.SentinelMaster, error)
+	ResetSentinel(ctx context.Context, ip string) error
+	GetSlaveOf(ctx context.Context, ip, port, password string) (string, error)
+	IsMaster(ctx context.Context, ip, port, password string) (bool, error)
+	MonitorRedis(ctx context.Context, ip, monitor, quorum, password string) error
+	MonitorRedisWithPort(ctx context.Context, ip, monitor, port, quorum, password string) error
+	RemoveSentinelMonitor(ctx context.Context, ip string) error
+	MakeMaster(ctx context.Context, ip, port, password string) error
+	MakeSlaveOf(ctx context.Context, ip, masterIP, password string) error
+	MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, password string) error
+	GetSentinelMonitor(ctx context.Context, ip string) (string, string, error)
+	SetCustomSentinelConfig(ctx context.Context, ip string, configs []string) error
+	SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error
+	GetRedisConfigDrift(ctx context.Context, ip string, port string, configs []string, password string) ([]string, error)
+	GetSentinelConfigDrift(ctx context.Context, ip string, configs []string) ([]string, error)
+	SlaveIsReady(ctx context.Context, ip, port, password string) (bool, error)
+	SentinelCheckQuorum(ctx context.Context, ip string) error
+	BackgroundSave(ctx context.Context, ip, port, password string) error
+	GetInfo(ctx context.Context, ip, port, password string) (*info.Info, error)
+	WatchSentinelEvents(ctx context.Context, ip string, patterns ...string) (<-chan SentinelEvent, error)
+	CloseFailover(namespace, name string)
+}
+
+// Config is the configuration of the redis client.
+type Config struct {
+	// DialTimeout is the timeout for establishing new connections.
+	DialTimeout time.Duration
+	// ReadTimeout is the timeout for socket reads.
+	ReadTimeout time.Duration
+	// WriteTimeout is the timeout for socket writes.
+	WriteTimeout time.Duration
+	// IdleTimeout is the time after which an unused address is forgotten and its connections closed.
+	IdleTimeout time.Duration
+	// BackgroundSaveTimeout is the maximum time waited for a background save to finish.
+	BackgroundSaveTimeout time.Duration
+}
+
+// DefaultConfig returns the default configuration of the redis client.
+func DefaultConfig() Config {
+	return Config{
+		DialTimeout:           5 * time.Second,
+		ReadTimeout:           3 * time.Second,
+		WriteTimeout:          3 * time.Second,
+		IdleTimeout:           5 * time.Minute,
+		BackgroundSaveTimeout: 2 * time.Minute,
+	}
 }
 
 type client struct {
+	config          Config
 	metricsRecorder metrics.Recorder
+
+	mu        sync.Mutex
+	clients   map[clientKey]*pooledClient
+	lastSweep time.Time
 }
 
-// New returns a redis client
-func New(metricsRecorder metrics.Recorder) Client {
+type clientKey struct {
+	addr     string
+	password string
+}
+
+type pooledClient struct {
+	rClient   *rediscli.Client
+	failovers map[string]struct{}
+	lastUsed  time.Time
+}
+
+// SentinelEvent is an event published by a sentinel on one of its channels
+type SentinelEvent struct {
+	Channel string
+	Payload string
+}
+
+type failoverContextKey struct{}
+
+type masterNameContextKey struct{}
+
+// New returns a redis client that reuses the connections to every address
+func New(config Config, metricsRecorder metrics.Recorder) Client {
 	return &client{
+		config:          config,
 		metricsRecorder: metricsRecorder,
+		clients:         make(map[clientKey]*pooledClient),
+	}
+}
+
+// WithFailover returns a copy of ctx that attributes the connections opened with it to the given RedisFailover,
+// so they are closed by CloseFailover once the RedisFailover is deleted.
+func WithFailover(ctx context.Context, namespace, name string) context.Context {
+	return context.WithValue(ctx, failoverContextKey{}, failoverID(namespace, name))
+}
+
+// WithMasterName returns a copy of ctx that makes the sentinel operations done with it work on the master known
+// by the given name. Without it, the default name is used.
+func WithMasterName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, masterNameContextKey{}, name)
+}
+
+func getMasterName(ctx context.Context) string {
+	if name, ok := ctx.Value(masterNameContextKey{}).(string); ok && name != "" {
+		return name
+	}
+	return defaultMasterName
+}
+
+func failoverID(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// getClient returns the cached client for the address, creating it if needed
+func (c *client) getClient(ctx context.Context, addr, password string) *rediscli.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.sweepIdle(now)
+
+	key := clientKey{addr: addr, password: password}
+	pc, ok := c.clients[key]
+	if !ok {
+		pc = &pooledClient{
+			rClient: rediscli.NewClient(&rediscli.Options{
+				Addr:         addr,
+				Password:     password,
+				DB:           0,
+				DialTimeout:  c.config.DialTimeout,
+				ReadTimeout:  c.config.ReadTimeout,
+				WriteTimeout: c.config.WriteTimeout,
+				IdleTimeout:  c.config.IdleTimeout,
+			}),
+			failovers: make(map[string]struct{}),
+		}
+		c.clients[key] = pc
+	}
+	if id, ok := ctx.Value(failoverContextKey{}).(string); ok {
+		pc.failovers[id] = struct{}{}
+	}
+	pc.lastUsed = now
+	return pc.rClient
+}
+
+// sweepIdle closes the clients that have not been used during the idle timeout, as the
+// addresses of restarted pods are not used anymore. It must be called with the lock held.
+func (c *client) sweepIdle(now time.Time) {
+	if c.config.IdleTimeout <= 0 || now.Sub(c.lastSweep) < c.config.IdleTimeout {
+		return
+	}
+	c.lastSweep = now
+	for key, pc := range c.clients {
+		if now.Sub(pc.lastUsed) > c.config.IdleTimeout {
+			pc.rClient.Close()
+			delete(c.clients, key)
+		}
+	}
+}
+
+// CloseFailover closes the connections that were only used on behalf of the given RedisFailover
+func (c *client) CloseFailover(namespace, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := failoverID(namespace, name)
+	for key, pc := range c.clients {
+		if _, ok := pc.failovers[id]; !ok {
+			continue
+		}
+		delete(pc.failovers, id)
+		if len(pc.failovers) == 0 {
+			pc.rClient.Close()
+			delete(c.clients, key)
+		}
 	}
 }
 
 const (
-	sentinelsNumberREString = "sentinels=([0-9]+)"
-	slaveNumberREString     = "slaves=([0-9]+)"
-	sentinelStatusREString  = "status=([a-z]+)"
-	redisMasterHostREString = "master_host:([0-9.]+)"
-	redisRoleMaster         = "role:master"
-	redisSyncing            = "master_sync_in_progress:1"
-	redisMasterSillPending  = "master_host:127.0.0.1"
-	redisLinkUp             = "master_link_status:up"
-	redisPort               = "6379"
-	sentinelPort            = "26379"
-	masterName              = "mymaster"
+	redisMemoryREString = "^([0-9]+)(k|kb|m|mb|g|gb)$"
+	redisLastBgSaveOK   = "ok"
+	redisPort           = "6379"
+	sentinelPort        = "26379"
+	defaultMasterName   = "mymaster"
+	noSuchMasterError   = "No such master with that name"
+
+	backgroundSavePollInterval = time.Second
 )
 
 var (
-	sentinelNumberRE  = regexp.MustCompile(sentinelsNumberREString)
-	sentinelStatusRE  = regexp.MustCompile(sentinelStatusREString)
-	slaveNumberRE     = regexp.MustCompile(slaveNumberREString)
-	redisMasterHostRE = regexp.MustCompile(redisMasterHostREString)
+	redisMemoryRE = regexp.MustCompile(redisMemoryREString)
 )
 
 // GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelsInMemory(ctx context.Context, ip string) (int32, error) {
+	master, err := c.getSentinelMaster(ctx, ip, metrics.GET_NUM_SENTINELS_IN_MEM)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
 	}
-	if err2 := isSentinelReady(info); err2 != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, metrics.SENTINEL_NOT_READY)
-		return 0, err2
-	}
-	match := sentinelNumberRE.FindStringSubmatch(info)
-	if len(match) == 0 {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, metrics.REGEX_NOT_FOUND)
-		return 0, errors.New("seninel regex not found")
-	}
-	nSentinels, err := strconv.Atoi(match[1])
+	return master.Sentinels, nil
+}
+
+// GetNumberSentinelSlavesInMemory return the number of slaves that the requested sentinel has
+func (c *client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string) (int32, error) {
+	master, err := c.getSentinelMaster(ctx, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, metrics.MISC)
 		return 0, err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return int32(nSentinels), nil
+	return master.Slaves, nil
 }
 
-// GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+// GetSentinelMaster returns the master monitored by the requested sentinel, with the number of sentinels and
+// slaves it has, out of a single INFO command
+func (c *client) GetSentinelMaster(ctx context.Context, ip string) (info.SentinelMaster, error) {
+	return c.getSentinelMaster(ctx, ip, metrics.GET_SENTINEL_INFO)
+}
+
+// getSentinelMaster returns the master monitored by the sentinel, as long as the sentinel is ready
+func (c *client) getSentinelMaster(ctx context.Context, ip string, operation string) (info.SentinelMaster, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	raw, err := rClient.Info(ctx, "sentinel").Result()
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, getRedisError(err))
-		return 0, err
-	}
-	if err2 := isSentinelReady(info); err2 != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, metrics.SENTINEL_NOT_READY)
-		return 0, err2
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, operation, metrics.FAIL, getRedisError(err))
+		return info.SentinelMaster{}, err
 	}
-	match := slaveNumberRE.FindStringSubmatch(info)
-	if len(match) == 0 {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, metrics.REGEX_NOT_FOUND)
-		return 0, errors.New("slaves regex not found")
-	}
-	nSlaves, err := strconv.Atoi(match[1])
+	sentinelInfo, err := info.Parse(raw)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, metrics.MISC)
-		return 0, err
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, operation, metrics.FAIL, metrics.MISC)
+		return info.SentinelMaster{}, err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return int32(nSlaves), nil
-}
-
-func isSentinelReady(info string) error {
-	matchStatus := sentinelStatusRE.FindStringSubmatch(info)
-	if len(matchStatus) == 0 || matchStatus[1] != "ok" {
-		return errors.New("sentinels not ready")
+	master, ok := sentinelInfo.Sentinel.Master(getMasterName(ctx))
+	if !ok || !master.Ready() {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, operation, metrics.FAIL, metrics.SENTINEL_NOT_READY)
+		return info.SentinelMaster{}, errors.New("sentinels not ready")
 	}
-	return nil
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, operation, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return master, nil
 }
 
-// ResetSentinel sends a sentinel reset * for the given sentinel
-func (c *client) ResetSentinel(ip string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	cmd := rediscli.NewIntCmd(context.TODO(), "SENTINEL", "reset", "*")
-	err := rClient.Process(context.TODO(), cmd)
+// ResetSentinel sends a sentinel reset of the master for the given sentinel. Only the master of the context
+// is reset, so the rest of masters monitored by the same sentinel keep their state.
+func (c *client) ResetSentinel(ctx context.Context, ip string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	cmd := rediscli.NewIntCmd(ctx, "SENTINEL", "reset", getMasterName(ctx))
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.RESET_SENTINEL, metrics.FAIL, getRedisError(err))
 		return err
@@ -162,64 +277,50 @@ func (c *client) ResetSentinel(ip string) error {
 }
 
 // GetSlaveOf returns the master of the given redis, or nil if it's master
-func (c *client) GetSlaveOf(ip, port, password string) (string, error) {
-
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) GetSlaveOf(ctx context.Context, ip, port, password string) (string, error) {
+	replication, err := c.getReplicationInfo(ctx, ip, port, password, metrics.GET_SLAVE_OF)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.FAIL, getRedisError(err))
 		log.Errorf("error while getting masterIP : Failed to get info replication while querying redis instance %v", ip)
 		return "", err
 	}
-	match := redisMasterHostRE.FindStringSubmatch(info)
-	if len(match) == 0 {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-		return "", nil
+	return replication.SlaveOf(), nil
+}
+
+func (c *client) IsMaster(ctx context.Context, ip, port, password string) (bool, error) {
+	replication, err := c.getReplicationInfo(ctx, ip, port, password, metrics.IS_MASTER)
+	if err != nil {
+		return false, err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return match[1], nil
+	return replication.IsMaster(), nil
 }
 
-func (c *client) IsMaster(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
+func (c *client) getReplicationInfo(ctx context.Context, ip, port, password string, operation string) (info.Replication, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, port), password)
+	raw, err := rClient.Info(ctx, "replication").Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, operation, metrics.FAIL, getRedisError(err))
+		return info.Replication{}, err
 	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+	redisInfo, err := info.Parse(raw)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.IS_MASTER, metrics.FAIL, getRedisError(err))
-		return false, err
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, operation, metrics.FAIL, metrics.MISC)
+		return info.Replication{}, err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.IS_MASTER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return strings.Contains(info, redisRoleMaster), nil
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, operation, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return redisInfo.Replication, nil
 }
 
-func (c *client) MonitorRedis(ip, monitor, quorum, password string) error {
-	return c.MonitorRedisWithPort(ip, monitor, redisPort, quorum, password)
+func (c *client) MonitorRedis(ctx context.Context, ip, monitor, quorum, password string) error {
+	return c.MonitorRedisWithPort(ctx, ip, monitor, redisPort, quorum, password)
 }
 
-func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	cmd := rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "REMOVE", masterName)
-	_ = rClient.Process(context.TODO(), cmd)
+func (c *client) MonitorRedisWithPort(ctx context.Context, ip, monitor, port, quorum, password string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	cmd := rediscli.NewBoolCmd(ctx, "SENTINEL", "REMOVE", getMasterName(ctx))
+	_ = rClient.Process(ctx, cmd)
 	// We'll continue even if it fails, the priority is to have the redises monitored
-	cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "MONITOR", masterName, monitor, port, quorum)
-	err := rClient.Process(context.TODO(), cmd)
+	cmd = rediscli.NewBoolCmd(ctx, "SENTINEL", "MONITOR", getMasterName(ctx), monitor, port, quorum)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 		return err
@@ -231,8 +332,8 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string
 	}
 
 	if password != "" {
-		cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "SET", masterName, "auth-pass", password)
-		err := rClient.Process(context.TODO(), cmd)
+		cmd = rediscli.NewBoolCmd(ctx, "SENTINEL", "SET", getMasterName(ctx), "auth-pass", password)
+		err := rClient.Process(ctx, cmd)
 		if err != nil {
 			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 			return err
@@ -247,15 +348,22 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string
 	return nil
 }
 
-func (c *client) MakeMaster(ip string, port string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
+// RemoveSentinelMonitor makes the sentinel stop monitoring the master. A master already unknown by the sentinel
+// is not an error.
+func (c *client) RemoveSentinelMonitor(ctx context.Context, ip string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	err := rClient.Do(ctx, "SENTINEL", "REMOVE", getMasterName(ctx)).Err()
+	if err != nil && !strings.Contains(err.Error(), noSuchMasterError) {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.REMOVE_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
+		return err
 	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	if res := rClient.SlaveOf(context.TODO(), "NO", "ONE"); res.Err() != nil {
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.REMOVE_SENTINEL_MONITOR, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) MakeMaster(ctx context.Context, ip string, port string, password string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, port), password)
+	if res := rClient.SlaveOf(ctx, "NO", "ONE"); res.Err() != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.FAIL, getRedisError(res.Err()))
 		return res.Err()
 	}
@@ -263,19 +371,13 @@ func (c *client) MakeMaster(ip string, port string, password string) error {
 	return nil
 }
 
-func (c *client) MakeSlaveOf(ip, masterIP, password string) error {
-	return c.MakeSlaveOfWithPort(ip, masterIP, redisPort, password)
+func (c *client) MakeSlaveOf(ctx context.Context, ip, masterIP, password string) error {
+	return c.MakeSlaveOfWithPort(ctx, ip, masterIP, redisPort, password)
 }
 
-func (c *client) MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, masterPort), // this is IP and Port for the RedisFailover redis
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	if res := rClient.SlaveOf(context.TODO(), masterIP, masterPort); res.Err() != nil {
+func (c *client) MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, password string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, masterPort), password)
+	if res := rClient.SlaveOf(ctx, masterIP, masterPort); res.Err() != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.FAIL, getRedisError(res.Err()))
 		return res.Err()
 	}
@@ -283,16 +385,10 @@ func (c *client) MakeSlaveOfWithPort(ip, masterIP, masterPort, password string)
 	return nil
 }
 
-func (c *client) GetSentinelMonitor(ip string) (string, string, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	cmd := rediscli.NewSliceCmd(context.TODO(), "SENTINEL", "master", masterName)
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) GetSentinelMonitor(ctx context.Context, ip string) (string, string, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	cmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "master", getMasterName(ctx))
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
 		return "", "", err
@@ -308,37 +404,26 @@ func (c *client) GetSentinelMonitor(ip string) (string, string, error) {
 	return masterIP, masterPort, nil
 }
 
-func (c *client) SetCustomSentinelConfig(ip string, configs []string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
+func (c *client) SetCustomSentinelConfig(ctx context.Context, ip string, configs []string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
 
 	for _, config := range configs {
 		param, value, err := c.getConfigParameters(config)
 		if err != nil {
 			return err
 		}
-		if err := c.applySentinelConfig(param, value, rClient); err != nil {
+		if err := c.applySentinelConfig(ctx, param, value, rClient); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (c *client) SentinelCheckQuorum(ip string) error {
+func (c *client) SentinelCheckQuorum(ctx context.Context, ip string) error {
 
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewSentinelClient(options)
-	defer rClient.Close()
-	cmd := rClient.CkQuorum(context.TODO(), masterName)
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	cmd := rediscli.NewStringCmd(ctx, "SENTINEL", "CKQUORUM", getMasterName(ctx))
+	_ = rClient.Process(ctx, cmd)
 	res, err := cmd.Result()
 
 	if err != nil {
@@ -370,50 +455,292 @@ func (c *client) SentinelCheckQuorum(ip string) error {
 	}
 
 }
-func (c *client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
+
+// SetCustomRedisConfig applies the given configuration to the redis, setting only the parameters whose value
+// differs from the running one. When something changes, the configuration file is rewritten so a restart of
+// redis keeps it.
+func (c *client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, port), password)
+
+	current, err := c.getRedisConfig(ctx, rClient)
+	if err != nil {
+		return err
 	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
 
-	for _, config := range configs {
+	changes, err := c.getConfigChanges(current, configs)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if err := c.applyRedisConfig(ctx, change.parameter, change.value, rClient); err != nil {
+			return err
+		}
+	}
+
+	if len(changes) > 0 {
+		return c.rewriteRedisConfig(ctx, rClient)
+	}
+	return nil
+}
+
+// GetRedisConfigDrift returns the parameters of the given configuration whose value differs from the running one.
+// The parameters unknown to the redis are ignored.
+func (c *client) GetRedisConfigDrift(ctx context.Context, ip string, port string, configs []string, password string) ([]string, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, port), password)
+
+	current, err := c.getRedisConfig(ctx, rClient)
+	if err != nil {
+		return nil, err
+	}
+	return c.getConfigDrift(current, configs)
+}
+
+// GetSentinelConfigDrift returns the parameters of the given configuration whose value differs from the one
+// the sentinel has for the monitored master. The parameters that the sentinel doesn't report are ignored.
+func (c *client) GetSentinelConfigDrift(ctx context.Context, ip string, configs []string) ([]string, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	res, err := rClient.Do(ctx, "SENTINEL", "master", getMasterName(ctx)).Slice()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+
+	current := make(map[string]string, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		param, _ := res[i].(string)
+		value, _ := res[i+1].(string)
+		current[param] = value
+	}
+	return c.getConfigDrift(current, configs)
+}
+
+type configChange struct {
+	parameter string
+	value     string
+}
+
+// getConfigChanges returns the entries of the given configuration that differ from the current one. The
+// repeated parameters are grouped first, as redis reports and sets all their values at once.
+func (c *client) getConfigChanges(current map[string]string, configs []string) ([]configChange, error) {
+	changes := []configChange{}
+	for _, config := range GroupConfig(configs) {
 		param, value, err := c.getConfigParameters(config)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// If the configuration is an empty line , it will result in an incorrect configSet, which will not run properly down the line.
 		// `config set save ""` should support
 		if strings.TrimSpace(param) == "" {
 			continue
 		}
-		if err := c.applyRedisConfig(param, value, rClient); err != nil {
-			return err
+		if currentValue, ok := current[strings.ToLower(param)]; ok && SameConfigValue(param, currentValue, value) {
+			continue
+		}
+		changes = append(changes, configChange{parameter: param, value: value})
+	}
+	return changes, nil
+}
+
+// getConfigDrift returns the parameters of the given configuration that differ from the current one, ignoring
+// the ones not present on it
+func (c *client) getConfigDrift(current map[string]string, configs []string) ([]string, error) {
+	configs = filterConfigs(configs, func(param string) bool {
+		_, ok := current[param]
+		return ok
+	})
+	changes, err := c.getConfigChanges(current, configs)
+	if err != nil {
+		return nil, err
+	}
+	drift := make([]string, 0, len(changes))
+	for _, change := range changes {
+		drift = append(drift, change.parameter)
+	}
+	return drift, nil
+}
+
+func filterConfigs(configs []string, keep func(param string) bool) []string {
+	filtered := []string{}
+	for _, config := range configs {
+		fields := strings.Fields(config)
+		if len(fields) > 0 && keep(strings.ToLower(fields[0])) {
+			filtered = append(filtered, config)
 		}
 	}
+	return filtered
+}
+
+// getRedisConfig returns the value of every configuration parameter of the running redis
+func (c *client) getRedisConfig(ctx context.Context, rClient *rediscli.Client) (map[string]string, error) {
+	result, err := rClient.ConfigGet(ctx, "*").Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.GET_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	config := make(map[string]string, len(result)/2)
+	for i := 0; i+1 < len(result); i += 2 {
+		param, _ := result[i].(string)
+		value, _ := result[i+1].(string)
+		config[param] = value
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.GET_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return config, nil
+}
+
+// rewriteRedisConfig writes the running configuration to the configuration file of the redis
+func (c *client) rewriteRedisConfig(ctx context.Context, rClient *rediscli.Client) error {
+	if err := rClient.ConfigRewrite(ctx).Err(); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.REWRITE_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.REWRITE_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) applyRedisConfig(parameter string, value string, rClient *rediscli.Client) error {
-	result := rClient.ConfigSet(context.TODO(), parameter, value)
+// GroupConfig joins the lines of the configuration setting the same parameter, like the save points or the
+// classes of client-output-buffer-limit, in a single line with all their values, keeping the order of the
+// first one. The malformed lines are kept as they are.
+func GroupConfig(configs []string) []string {
+	type group struct {
+		line   string
+		values []string
+	}
+	groups := []*group{}
+	byParam := map[string]*group{}
+	for _, config := range configs {
+		fields := strings.Fields(config)
+		if len(fields) < 2 {
+			groups = append(groups, &group{line: config})
+			continue
+		}
+		value := strings.Join(fields[1:], " ")
+		if value == `""` {
+			value = ""
+		}
+		g, ok := byParam[strings.ToLower(fields[0])]
+		if !ok {
+			g = &group{line: fields[0]}
+			byParam[strings.ToLower(fields[0])] = g
+			groups = append(groups, g)
+		}
+		if value != "" {
+			g.values = append(g.values, value)
+		}
+	}
+
+	grouped := make([]string, 0, len(groups))
+	for _, g := range groups {
+		switch {
+		case len(g.values) > 0:
+			grouped = append(grouped, g.line+" "+strings.Join(g.values, " "))
+		case byParam[strings.ToLower(g.line)] == g:
+			grouped = append(grouped, g.line+` ""`)
+		default:
+			grouped = append(grouped, g.line)
+		}
+	}
+	return grouped
+}
+
+// SameConfigValue compares the running value of a configuration parameter with a configured one. Redis
+// returns the memory sizes in bytes and the rest of the values in lower case, so the configured one is
+// normalized. The classes of client-output-buffer-limit not configured are not compared.
+func SameConfigValue(parameter, current, value string) bool {
+	if strings.EqualFold(parameter, "client-output-buffer-limit") {
+		return sameClientOutputBufferLimit(current, value)
+	}
+	return sameConfigFields(current, value)
+}
+
+// sameClientOutputBufferLimit compares the limits of every configured class with the running ones, being
+// replica and slave the same class
+func sameClientOutputBufferLimit(current, value string) bool {
+	currentLimits := clientOutputBufferLimits(current)
+	valueLimits := clientOutputBufferLimits(value)
+	if currentLimits == nil || valueLimits == nil {
+		return sameConfigFields(current, value)
+	}
+	for class, limits := range valueLimits {
+		if !sameConfigFields(currentLimits[class], limits) {
+			return false
+		}
+	}
+	return true
+}
+
+// clientOutputBufferLimits returns the limits of every class of a client-output-buffer-limit value, or nil
+// when it is malformed
+func clientOutputBufferLimits(value string) map[string]string {
+	fields := strings.Fields(value)
+	if len(fields)%4 != 0 {
+		return nil
+	}
+	limits := map[string]string{}
+	for i := 0; i < len(fields); i += 4 {
+		class := strings.ToLower(fields[i])
+		if class == "replica" {
+			class = "slave"
+		}
+		limits[class] = strings.Join(fields[i+1:i+4], " ")
+	}
+	return limits
+}
+
+func sameConfigFields(current, value string) bool {
+	currentFields := strings.Fields(current)
+	valueFields := strings.Fields(value)
+	if len(currentFields) != len(valueFields) {
+		return false
+	}
+	for i, field := range valueFields {
+		if currentFields[i] != normalizeRedisConfigField(field) {
+			return false
+		}
+	}
+	return true
+}
+
+var redisMemoryUnits = map[string]int64{
+	"k":  1000,
+	"kb": 1024,
+	"m":  1000 * 1000,
+	"mb": 1024 * 1024,
+	"g":  1000 * 1000 * 1000,
+	"gb": 1024 * 1024 * 1024,
+}
+
+func normalizeRedisConfigField(field string) string {
+	field = strings.ToLower(field)
+	match := redisMemoryRE.FindStringSubmatch(field)
+	if len(match) == 0 {
+		return field
+	}
+	n, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return field
+	}
+	return strconv.FormatInt(n*redisMemoryUnits[match[2]], 10)
+}
+
+func (c *client) applyRedisConfig(ctx context.Context, parameter string, value string, rClient *rediscli.Client) error {
+	result := rClient.ConfigSet(ctx, parameter, value)
 	if nil != result.Err() {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(result.Err()))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(result.Err()))
 		return result.Err()
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.APPLY_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return result.Err()
 }
 
-func (c *client) applySentinelConfig(parameter string, value string, rClient *rediscli.Client) error {
-	cmd := rediscli.NewStatusCmd(context.TODO(), "SENTINEL", "set", masterName, parameter, value)
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) applySentinelConfig(ctx context.Context, parameter string, value string, rClient *rediscli.Client) error {
+	cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "set", getMasterName(ctx), parameter, value)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, getHost(rClient.Options().Addr), metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
 		return err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, getHost(rClient.Options().Addr), metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return cmd.Err()
 }
 
@@ -428,25 +755,66 @@ func (c *client) getConfigParameters(config string) (parameter string, value str
 	return s[0], strings.Join(s[1:], " "), nil
 }
 
-func (c *client) SlaveIsReady(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) SlaveIsReady(ctx context.Context, ip, port, password string) (bool, error) {
+	replication, err := c.getReplicationInfo(ctx, ip, port, password, metrics.SLAVE_IS_READY)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.FAIL, getRedisError(err))
 		return false, err
 	}
+	return replication.SlaveReady(), nil
+}
+
+// BackgroundSave saves the dataset of the redis to disk in background and waits until the save finishes,
+// the background save timeout passes or the context is done. A save already in progress is waited for
+// instead of starting a new one.
+func (c *client) BackgroundSave(ctx context.Context, ip, port, password string) error {
+	if c.config.BackgroundSaveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.BackgroundSaveTimeout)
+		defer cancel()
+	}
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, port), password)
+	if err := rClient.BgSave(ctx).Err(); err != nil && !strings.Contains(err.Error(), "already in progress") {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.BACKGROUND_SAVE, metrics.FAIL, getRedisError(err))
+		return err
+	}
 
-	ok := !strings.Contains(info, redisSyncing) &&
-		!strings.Contains(info, redisMasterSillPending) &&
-		strings.Contains(info, redisLinkUp)
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return ok, nil
+	ticker := time.NewTicker(backgroundSavePollInterval)
+	defer ticker.Stop()
+	for {
+		raw, err := rClient.Info(ctx, "persistence").Result()
+		if err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.BACKGROUND_SAVE, metrics.FAIL, getRedisError(err))
+			return err
+		}
+		redisInfo, err := info.Parse(raw)
+		if err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.BACKGROUND_SAVE, metrics.FAIL, metrics.MISC)
+			return err
+		}
+		if !redisInfo.Persistence.RDBBgsaveInProgress {
+			if redisInfo.Persistence.RDBLastBgsaveStatus != redisLastBgSaveOK {
+				c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.BACKGROUND_SAVE, metrics.FAIL, metrics.NOT_APPLICABLE)
+				return errors.New("background save failed")
+			}
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, getHost(rClient.Options().Addr), metrics.BACKGROUND_SAVE, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// getHost returns the host part of a redis address, keeping IPv6 addresses intact
+func getHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
 }
 
 func getRedisError(err error) string {
@@ -464,3 +832,61 @@ func getRedisError(err error) string {
 		return "MISC"
 	}
 }
+
+// GetInfo returns the default sections of the INFO of the redis, that include the server, replication
+// and keyspace ones
+func (c *client) GetInfo(ctx context.Context, ip, port, password string) (*info.Info, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, port), password)
+	raw, err := rClient.Info(ctx).Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_INFO, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	redisInfo, err := info.Parse(raw)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_INFO, metrics.FAIL, metrics.MISC)
+		return nil, err
+	}
+	if !redisInfo.HasSection("server") || !redisInfo.HasSection("replication") {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_INFO, metrics.FAIL, metrics.MISC)
+		return nil, errors.New("info without server or replication sections")
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_INFO, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return redisInfo, nil
+}
+
+// WatchSentinelEvents subscribes to the channels of the sentinel matching the given patterns and sends the
+// events received until the context is done. The returned channel is closed then.
+func (c *client) WatchSentinelEvents(ctx context.Context, ip string, patterns ...string) (<-chan SentinelEvent, error) {
+	rClient := c.getClient(ctx, net.JoinHostPort(ip, sentinelPort), "")
+	pubsub := rClient.PSubscribe(ctx, patterns...)
+	if _, err := pubsub.ReceiveTimeout(ctx, c.config.ReadTimeout); err != nil {
+		pubsub.Close()
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.WATCH_SENTINEL_EVENTS, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.WATCH_SENTINEL_EVENTS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+
+	events := make(chan SentinelEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case events <- SentinelEvent{Channel: msg.Channel, Payload: msg.Payload}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
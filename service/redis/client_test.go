@@ -0,0 +1,226 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+func TestSameRedisConfigValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		parameter string
+		current   string
+		value     string
+		expected  bool
+	}{
+		{
+			name:     "same value",
+			current:  "allkeys-lru",
+			value:    "allkeys-lru",
+			expected: true,
+		},
+		{
+			name:     "different value",
+			current:  "noeviction",
+			value:    "allkeys-lru",
+			expected: false,
+		},
+		{
+			name:     "case insensitive",
+			current:  "yes",
+			value:    "YES",
+			expected: true,
+		},
+		{
+			name:     "memory units",
+			current:  "1073741824",
+			value:    "1gb",
+			expected: true,
+		},
+		{
+			name:     "several fields",
+			current:  "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+			value:    "normal 0 0 0 slave 256mb 64mb 60 pubsub 32mb 8mb 60",
+			expected: true,
+		},
+		{
+			name:     "different number of fields",
+			current:  "900 1 300 10",
+			value:    "900 1",
+			expected: false,
+		},
+		{
+			name:     "empty",
+			current:  "",
+			value:    "",
+			expected: true,
+		},
+		{
+			name:      "configured client output buffer limit classes",
+			parameter: "client-output-buffer-limit",
+			current:   "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+			value:     "replica 256mb 64mb 60 pubsub 32mb 8mb 60",
+			expected:  true,
+		},
+		{
+			name:      "different client output buffer limit class",
+			parameter: "client-output-buffer-limit",
+			current:   "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+			value:     "pubsub 64mb 8mb 60",
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, SameConfigValue(test.parameter, test.current, test.value))
+		})
+	}
+}
+
+func TestGetConfigDrift(t *testing.T) {
+	current := map[string]string{
+		"maxmemory":                  "104857600",
+		"maxmemory-policy":           "allkeys-lru",
+		"down-after-milliseconds":    "5000",
+		"save":                       "900 1 300 10",
+		"client-output-buffer-limit": "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+	}
+	tests := []struct {
+		name          string
+		configs       []string
+		expectedDrift []string
+	}{
+		{
+			name:          "no drift",
+			configs:       []string{"maxmemory 100mb", "maxmemory-policy allkeys-lru"},
+			expectedDrift: []string{},
+		},
+		{
+			name:          "changed values",
+			configs:       []string{"maxmemory 200mb", "maxmemory-policy noeviction", "down-after-milliseconds 5000"},
+			expectedDrift: []string{"maxmemory", "maxmemory-policy"},
+		},
+		{
+			name:          "unknown parameters are ignored",
+			configs:       []string{"failover-timeout 10000", "maxmemory 100mb"},
+			expectedDrift: []string{},
+		},
+		{
+			name:          "repeated parameters",
+			configs:       []string{"save 900 1", "client-output-buffer-limit replica 256mb 64mb 60", "save 300 10", "client-output-buffer-limit pubsub 32mb 8mb 60"},
+			expectedDrift: []string{},
+		},
+		{
+			name:          "changed repeated parameters",
+			configs:       []string{"save 900 1", "client-output-buffer-limit replica 256mb 64mb 60", "client-output-buffer-limit pubsub 64mb 8mb 60"},
+			expectedDrift: []string{"save", "client-output-buffer-limit"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &client{}
+			drift, err := c.getConfigDrift(current, test.configs)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDrift, drift)
+		})
+	}
+}
+
+func TestGetConfigChangesGroupsRepeatedParameters(t *testing.T) {
+	c := &client{}
+	changes, err := c.getConfigChanges(map[string]string{"save": "3600 1"}, []string{"save 900 1", "save 300 10"})
+	assert.NoError(t, err)
+	assert.Equal(t, []configChange{{parameter: "save", value: "900 1 300 10"}}, changes)
+}
+
+func TestGroupConfig(t *testing.T) {
+	assert.Equal(t, []string{
+		"save 900 1 300 10",
+		"maxmemory 100mb",
+		"client-output-buffer-limit replica 256mb 64mb 60 pubsub 32mb 8mb 60",
+		`appendfsync ""`,
+	}, GroupConfig([]string{
+		"save 900 1",
+		"maxmemory 100mb",
+		"client-output-buffer-limit replica 256mb 64mb 60",
+		"save 300 10",
+		"client-output-buffer-limit pubsub 32mb 8mb 60",
+		`appendfsync ""`,
+	}))
+	assert.Equal(t, []string{"save 900 1"}, GroupConfig([]string{`save ""`, "save 900 1"}))
+}
+
+func TestGetMasterName(t *testing.T) {
+	assert.Equal(t, "mymaster", getMasterName(context.Background()))
+	assert.Equal(t, "cache", getMasterName(WithMasterName(context.Background(), "cache")))
+	assert.Equal(t, "cache", getMasterName(WithFailover(WithMasterName(context.Background(), "cache"), "testns", "test")))
+}
+
+func TestGetHost(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("10.244.0.10", getHost("10.244.0.10:6379"))
+	assert.Equal("fd00:10:244::a", getHost("[fd00:10:244::a]:6379"))
+	assert.Equal("rfr-test-0.rfr-test.testns.svc", getHost("rfr-test-0.rfr-test.testns.svc:6379"))
+}
+
+func TestGetClientReusesClients(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(DefaultConfig(), metrics.Dummy).(*client)
+	ctx := WithFailover(context.Background(), "testns", "test")
+
+	first := c.getClient(ctx, "10.244.0.10:6379", "pass")
+	assert.Same(first, c.getClient(ctx, "10.244.0.10:6379", "pass"))
+	assert.NotSame(first, c.getClient(ctx, "10.244.0.10:6379", "other"))
+	assert.NotSame(first, c.getClient(ctx, "10.244.0.11:6379", "pass"))
+	assert.Len(c.clients, 3)
+	assert.Equal(DefaultConfig().ReadTimeout, first.Options().ReadTimeout)
+}
+
+func TestCloseFailover(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(DefaultConfig(), metrics.Dummy).(*client)
+	ctx1 := WithFailover(context.Background(), "testns", "test1")
+	ctx2 := WithFailover(context.Background(), "testns", "test2")
+
+	c.getClient(ctx1, "10.244.0.10:6379", "")
+	c.getClient(ctx1, "10.244.0.11:6379", "")
+	c.getClient(ctx2, "10.244.0.11:6379", "")
+
+	c.CloseFailover("testns", "test1")
+
+	// The address still used by the other failover is kept.
+	assert.Len(c.clients, 1)
+	_, ok := c.clients[clientKey{addr: "10.244.0.11:6379"}]
+	assert.True(ok)
+
+	c.CloseFailover("testns", "test2")
+	assert.Len(c.clients, 0)
+}
+
+func TestSweepIdleClients(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(DefaultConfig(), metrics.Dummy).(*client)
+	ctx := context.Background()
+
+	c.getClient(ctx, "10.244.0.10:6379", "")
+	c.getClient(ctx, "10.244.0.11:6379", "")
+	c.clients[clientKey{addr: "10.244.0.10:6379"}].lastUsed = time.Now().Add(-2 * DefaultConfig().IdleTimeout)
+	c.lastSweep = time.Time{}
+
+	c.getClient(ctx, "10.244.0.11:6379", "")
+
+	assert.Len(c.clients, 1)
+	_, ok := c.clients[clientKey{addr: "10.244.0.11:6379"}]
+	assert.True(ok)
+}
@@ -0,0 +1,345 @@
+// Package info parses the output of the redis and sentinel INFO command into typed structs.
+package info
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info is the output of the INFO command. Only the sections that were returned are filled, the fields
+// that the redis version doesn't report are left to their zero value.
+type Info struct {
+	Server      Server
+	Clients     Clients
+	Memory      Memory
+	Persistence Persistence
+	Stats       Stats
+	Replication Replication
+	Sentinel    Sentinel
+	Keyspace    Keyspace
+
+	sections map[string]bool
+}
+
+// Server is the server section of the INFO command
+type Server struct {
+	RedisVersion    string `info:"redis_version"`
+	RedisMode       string `info:"redis_mode"`
+	RunID           string `info:"run_id"`
+	TCPPort         int    `info:"tcp_port"`
+	UptimeInSeconds int64  `info:"uptime_in_seconds"`
+}
+
+// Clients is the clients section of the INFO command
+type Clients struct {
+	ConnectedClients int64 `info:"connected_clients"`
+	BlockedClients   int64 `info:"blocked_clients"`
+	MaxClients       int64 `info:"maxclients"`
+}
+
+// Memory is the memory section of the INFO command
+type Memory struct {
+	UsedMemory            int64   `info:"used_memory"`
+	UsedMemoryRSS         int64   `info:"used_memory_rss"`
+	UsedMemoryPeak        int64   `info:"used_memory_peak"`
+	MaxMemory             int64   `info:"maxmemory"`
+	MaxMemoryPolicy       string  `info:"maxmemory_policy"`
+	MemFragmentationRatio float64 `info:"mem_fragmentation_ratio"`
+}
+
+// Persistence is the persistence section of the INFO command
+type Persistence struct {
+	Loading                 bool   `info:"loading"`
+	RDBChangesSinceLastSave int64  `info:"rdb_changes_since_last_save"`
+	RDBBgsaveInProgress     bool   `info:"rdb_bgsave_in_progress"`
+	RDBLastSaveTime         int64  `info:"rdb_last_save_time"`
+	RDBLastBgsaveStatus     string `info:"rdb_last_bgsave_status"`
+	AOFEnabled              bool   `info:"aof_enabled"`
+	AOFRewriteInProgress    bool   `info:"aof_rewrite_in_progress"`
+	AOFLastBgrewriteStatus  string `info:"aof_last_bgrewrite_status"`
+	AOFLastWriteStatus      string `info:"aof_last_write_status"`
+}
+
+// Stats is the stats section of the INFO command
+type Stats struct {
+	TotalConnectionsReceived int64 `info:"total_connections_received"`
+	TotalCommandsProcessed   int64 `info:"total_commands_processed"`
+	InstantaneousOpsPerSec   int64 `info:"instantaneous_ops_per_sec"`
+	RejectedConnections      int64 `info:"rejected_connections"`
+	SyncFull                 int64 `info:"sync_full"`
+	SyncPartialOK            int64 `info:"sync_partial_ok"`
+	SyncPartialErr           int64 `info:"sync_partial_err"`
+	ExpiredKeys              int64 `info:"expired_keys"`
+	EvictedKeys              int64 `info:"evicted_keys"`
+	KeyspaceHits             int64 `info:"keyspace_hits"`
+	KeyspaceMisses           int64 `info:"keyspace_misses"`
+}
+
+// Replication is the replication section of the INFO command
+type Replication struct {
+	Role                   string `info:"role"`
+	MasterHost             string `info:"master_host"`
+	MasterPort             string `info:"master_port"`
+	MasterLinkStatus       string `info:"master_link_status"`
+	MasterLastIOSecondsAgo int64  `info:"master_last_io_seconds_ago"`
+	MasterSyncInProgress   bool   `info:"master_sync_in_progress"`
+	SlaveReplOffset        int64  `info:"slave_repl_offset"`
+	SlavePriority          int64  `info:"slave_priority"`
+	ConnectedSlaves        int64  `info:"connected_slaves"`
+	MasterReplID           string `info:"master_replid"`
+	MasterReplOffset       int64  `info:"master_repl_offset"`
+	// Slaves are the slaves connected to a master
+	Slaves []Slave
+}
+
+// Slave is a slave connected to a master, as listed on its replication section
+type Slave struct {
+	IP     string `info:"ip"`
+	Port   string `info:"port"`
+	State  string `info:"state"`
+	Offset int64  `info:"offset"`
+	Lag    int64  `info:"lag"`
+}
+
+// Sentinel is the sentinel section of the INFO command run on a sentinel
+type Sentinel struct {
+	SentinelMasters int64 `info:"sentinel_masters"`
+	SentinelTilt    bool  `info:"sentinel_tilt"`
+	// Masters are the masters monitored by the sentinel
+	Masters []SentinelMaster
+}
+
+// SentinelMaster is a master monitored by a sentinel, as listed on its sentinel section
+type SentinelMaster struct {
+	Name      string `info:"name"`
+	Status    string `info:"status"`
+	Address   string `info:"address"`
+	Slaves    int32  `info:"slaves"`
+	Sentinels int32  `info:"sentinels"`
+}
+
+// Keyspace is the keyspace section of the INFO command, by database name. Empty databases are not listed.
+type Keyspace map[string]Database
+
+// Database is a database of the keyspace section
+type Database struct {
+	Keys    int64 `info:"keys"`
+	Expires int64 `info:"expires"`
+	AvgTTL  int64 `info:"avg_ttl"`
+}
+
+const (
+	roleMaster      = "master"
+	linkStatusUp    = "up"
+	sentinelReadyOK = "ok"
+	// masterPending is the master host that a slave has until it gets the real master
+	masterPending = "127.0.0.1"
+)
+
+// Parse parses the output of the INFO command
+func Parse(info string) (*Info, error) {
+	result := &Info{
+		Keyspace: Keyspace{},
+		sections: map[string]bool{},
+	}
+
+	section := ""
+	fields := map[string]string{}
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if err := result.setSection(section, fields); err != nil {
+				return nil, err
+			}
+			section = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			fields = map[string]string{}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed info line %q", line)
+		}
+		fields[key] = value
+	}
+	if err := result.setSection(section, fields); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// HasSection returns whether the section was part of the parsed output
+func (i *Info) HasSection(section string) bool {
+	return i.sections[strings.ToLower(section)]
+}
+
+func (i *Info) setSection(section string, fields map[string]string) error {
+	if section == "" {
+		return nil
+	}
+	i.sections[section] = true
+
+	var err error
+	switch section {
+	case "server":
+		err = decode(fields, &i.Server)
+	case "clients":
+		err = decode(fields, &i.Clients)
+	case "memory":
+		err = decode(fields, &i.Memory)
+	case "persistence":
+		err = decode(fields, &i.Persistence)
+	case "stats":
+		err = decode(fields, &i.Stats)
+	case "replication":
+		err = i.setReplication(fields)
+	case "sentinel":
+		err = i.setSentinel(fields)
+	case "keyspace":
+		err = i.setKeyspace(fields)
+	}
+	if err != nil {
+		return fmt.Errorf("%s section: %w", section, err)
+	}
+	return nil
+}
+
+func (i *Info) setReplication(fields map[string]string) error {
+	if err := decode(fields, &i.Replication); err != nil {
+		return err
+	}
+	for n := 0; ; n++ {
+		value, ok := fields[fmt.Sprintf("slave%d", n)]
+		if !ok {
+			return nil
+		}
+		slave := Slave{}
+		if err := decode(parseList(value), &slave); err != nil {
+			return err
+		}
+		i.Replication.Slaves = append(i.Replication.Slaves, slave)
+	}
+}
+
+func (i *Info) setSentinel(fields map[string]string) error {
+	if err := decode(fields, &i.Sentinel); err != nil {
+		return err
+	}
+	for n := 0; ; n++ {
+		value, ok := fields[fmt.Sprintf("master%d", n)]
+		if !ok {
+			return nil
+		}
+		master := SentinelMaster{}
+		if err := decode(parseList(value), &master); err != nil {
+			return err
+		}
+		i.Sentinel.Masters = append(i.Sentinel.Masters, master)
+	}
+}
+
+func (i *Info) setKeyspace(fields map[string]string) error {
+	for name, value := range fields {
+		db := Database{}
+		if err := decode(parseList(value), &db); err != nil {
+			return err
+		}
+		i.Keyspace[name] = db
+	}
+	return nil
+}
+
+// parseList parses the values given as a comma separated list of key=value
+func parseList(value string) map[string]string {
+	fields := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		key, value, _ := strings.Cut(item, "=")
+		fields[key] = value
+	}
+	return fields
+}
+
+// decode sets the fields of the struct pointed by out from the values of the keys in their info tag
+func decode(fields map[string]string, out interface{}) error {
+	v := reflect.ValueOf(out).Elem()
+	t := v.Type()
+	for n := 0; n < t.NumField(); n++ {
+		key := t.Field(n).Tag.Get("info")
+		value, ok := fields[key]
+		if key == "" || !ok {
+			continue
+		}
+		field := v.Field(n)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Bool:
+			field.SetBool(value == "1")
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+			field.SetInt(i)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+			field.SetFloat(f)
+		}
+	}
+	return nil
+}
+
+// Uptime returns the time since the redis server started
+func (s Server) Uptime() time.Duration {
+	return time.Duration(s.UptimeInSeconds) * time.Second
+}
+
+// IsMaster returns whether the redis works as master
+func (r Replication) IsMaster() bool {
+	return r.Role == roleMaster
+}
+
+// SlaveOf returns the master of a slave, empty when the redis has no master
+func (r Replication) SlaveOf() string {
+	if r.IsMaster() {
+		return ""
+	}
+	return r.MasterHost
+}
+
+// SlaveReady returns whether the slave is connected to its master and the initial sync is done
+func (r Replication) SlaveReady() bool {
+	return !r.MasterSyncInProgress && r.MasterHost != masterPending && r.MasterLinkStatus == linkStatusUp
+}
+
+// Master returns the master with the given name monitored by the sentinel
+func (s Sentinel) Master(name string) (SentinelMaster, bool) {
+	for _, master := range s.Masters {
+		if master.Name == name {
+			return master, true
+		}
+	}
+	return SentinelMaster{}, false
+}
+
+// Ready returns whether the sentinel has the master in ok status
+func (m SentinelMaster) Ready() bool {
+	return m.Status == sentinelReadyOK
+}
+
+// TotalKeys returns the keys stored in all the databases
+func (k Keyspace) TotalKeys() int64 {
+	keys := int64(0)
+	for _, db := range k {
+		keys += db.Keys
+	}
+	return keys
+}
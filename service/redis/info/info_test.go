@@ -0,0 +1,205 @@
+package info_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spotahome/redis-operator/service/redis/info"
+)
+
+const redisInfo = "# Server\r\n" +
+	"redis_version:7.0.12\r\n" +
+	"redis_mode:standalone\r\n" +
+	"run_id:e3f9b5c1a2d4\r\n" +
+	"tcp_port:6379\r\n" +
+	"uptime_in_seconds:3600\r\n" +
+	"\r\n" +
+	"# Clients\r\n" +
+	"connected_clients:5\r\n" +
+	"blocked_clients:1\r\n" +
+	"maxclients:10000\r\n" +
+	"\r\n" +
+	"# Memory\r\n" +
+	"used_memory:1048576\r\n" +
+	"used_memory_rss:2097152\r\n" +
+	"maxmemory:104857600\r\n" +
+	"maxmemory_policy:allkeys-lru\r\n" +
+	"mem_fragmentation_ratio:2.00\r\n" +
+	"\r\n" +
+	"# Persistence\r\n" +
+	"loading:0\r\n" +
+	"rdb_bgsave_in_progress:1\r\n" +
+	"rdb_last_bgsave_status:ok\r\n" +
+	"aof_enabled:1\r\n" +
+	"\r\n" +
+	"# Stats\r\n" +
+	"total_commands_processed:1000\r\n" +
+	"sync_full:2\r\n" +
+	"evicted_keys:7\r\n" +
+	"\r\n" +
+	"# Replication\r\n" +
+	"role:master\r\n" +
+	"connected_slaves:2\r\n" +
+	"slave0:ip=10.244.0.11,port=6379,state=online,offset=1500,lag=0\r\n" +
+	"slave1:ip=fd00:10:244::b,port=6379,state=wait_bgsave,offset=0,lag=1\r\n" +
+	"master_replid:8a1c0e8b5c9f\r\n" +
+	"master_repl_offset:1500\r\n" +
+	"\r\n" +
+	"# Keyspace\r\n" +
+	"db0:keys=12,expires=0,avg_ttl=0\r\n" +
+	"db3:keys=30,expires=2,avg_ttl=1000\r\n"
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := info.Parse(redisInfo)
+	assert.NoError(err)
+
+	assert.Equal(info.Server{
+		RedisVersion:    "7.0.12",
+		RedisMode:       "standalone",
+		RunID:           "e3f9b5c1a2d4",
+		TCPPort:         6379,
+		UptimeInSeconds: 3600,
+	}, result.Server)
+	assert.Equal(time.Hour, result.Server.Uptime())
+	assert.Equal(info.Clients{ConnectedClients: 5, BlockedClients: 1, MaxClients: 10000}, result.Clients)
+	assert.Equal(info.Memory{
+		UsedMemory:            1048576,
+		UsedMemoryRSS:         2097152,
+		MaxMemory:             104857600,
+		MaxMemoryPolicy:       "allkeys-lru",
+		MemFragmentationRatio: 2,
+	}, result.Memory)
+	assert.Equal(info.Persistence{RDBBgsaveInProgress: true, RDBLastBgsaveStatus: "ok", AOFEnabled: true}, result.Persistence)
+	assert.Equal(info.Stats{TotalCommandsProcessed: 1000, SyncFull: 2, EvictedKeys: 7}, result.Stats)
+	assert.Equal(info.Replication{
+		Role:             "master",
+		ConnectedSlaves:  2,
+		MasterReplID:     "8a1c0e8b5c9f",
+		MasterReplOffset: 1500,
+		Slaves: []info.Slave{
+			{IP: "10.244.0.11", Port: "6379", State: "online", Offset: 1500},
+			{IP: "fd00:10:244::b", Port: "6379", State: "wait_bgsave", Lag: 1},
+		},
+	}, result.Replication)
+	assert.True(result.Replication.IsMaster())
+	assert.Equal(info.Keyspace{
+		"db0": {Keys: 12},
+		"db3": {Keys: 30, Expires: 2, AvgTTL: 1000},
+	}, result.Keyspace)
+	assert.Equal(int64(42), result.Keyspace.TotalKeys())
+
+	assert.True(result.HasSection("replication"))
+	assert.False(result.HasSection("sentinel"))
+}
+
+func TestParseSentinel(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := info.Parse("# Sentinel\r\n" +
+		"sentinel_masters:2\r\n" +
+		"sentinel_tilt:0\r\n" +
+		"master0:name=other,status=sdown,address=10.244.0.20:6379,slaves=1,sentinels=2\r\n" +
+		"master1:name=mymaster,status=ok,address=10.244.0.10:6379,slaves=2,sentinels=3\r\n")
+	assert.NoError(err)
+
+	assert.Equal(int64(2), result.Sentinel.SentinelMasters)
+	assert.False(result.Sentinel.SentinelTilt)
+	master, ok := result.Sentinel.Master("mymaster")
+	assert.True(ok)
+	assert.Equal(info.SentinelMaster{Name: "mymaster", Status: "ok", Address: "10.244.0.10:6379", Slaves: 2, Sentinels: 3}, master)
+	assert.True(master.Ready())
+	other, _ := result.Sentinel.Master("other")
+	assert.False(other.Ready())
+	_, ok = result.Sentinel.Master("unknown")
+	assert.False(ok)
+}
+
+func TestParseSlaveReplication(t *testing.T) {
+	tests := []struct {
+		name          string
+		info          string
+		expectedHost  string
+		expectedReady bool
+	}{
+		{
+			name:          "IPv4 master",
+			info:          "# Replication\r\nrole:slave\r\nmaster_host:10.244.0.10\r\nmaster_port:6379\r\nmaster_link_status:up\r\nmaster_sync_in_progress:0\r\n",
+			expectedHost:  "10.244.0.10",
+			expectedReady: true,
+		},
+		{
+			name:          "IPv6 master",
+			info:          "# Replication\r\nrole:slave\r\nmaster_host:fd00:10:244::a\r\nmaster_port:6379\r\nmaster_link_status:up\r\nmaster_sync_in_progress:0\r\n",
+			expectedHost:  "fd00:10:244::a",
+			expectedReady: true,
+		},
+		{
+			name:          "Hostname master",
+			info:          "# Replication\r\nrole:slave\r\nmaster_host:rfr-test-0.rfr-test.testns.svc\r\nmaster_port:6379\r\nmaster_link_status:up\r\nmaster_sync_in_progress:0\r\n",
+			expectedHost:  "rfr-test-0.rfr-test.testns.svc",
+			expectedReady: true,
+		},
+		{
+			name:         "syncing",
+			info:         "# Replication\r\nrole:slave\r\nmaster_host:10.244.0.10\r\nmaster_port:6379\r\nmaster_link_status:up\r\nmaster_sync_in_progress:1\r\n",
+			expectedHost: "10.244.0.10",
+		},
+		{
+			name:         "link down",
+			info:         "# Replication\r\nrole:slave\r\nmaster_host:10.244.0.10\r\nmaster_port:6379\r\nmaster_link_status:down\r\nmaster_sync_in_progress:0\r\n",
+			expectedHost: "10.244.0.10",
+		},
+		{
+			name:         "master still pending",
+			info:         "# Replication\r\nrole:slave\r\nmaster_host:127.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:up\r\nmaster_sync_in_progress:0\r\n",
+			expectedHost: "127.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			result, err := info.Parse(test.info)
+			if assert.NoError(err) {
+				assert.False(result.Replication.IsMaster())
+				assert.Equal(test.expectedHost, result.Replication.SlaveOf())
+				assert.Equal(test.expectedReady, result.Replication.SlaveReady())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		info          string
+		expectedError string
+	}{
+		{
+			name:          "malformed line",
+			info:          "# Server\r\nuptime_in_seconds\r\n",
+			expectedError: `malformed info line "uptime_in_seconds"`,
+		},
+		{
+			name:          "invalid number",
+			info:          "# Server\r\nuptime_in_seconds:abc\r\n",
+			expectedError: `server section: invalid uptime_in_seconds: strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+		{
+			name:          "invalid keyspace",
+			info:          "# Keyspace\r\ndb0:keys=abc,expires=0,avg_ttl=0\r\n",
+			expectedError: `keyspace section: invalid keys: strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := info.Parse(test.info)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}